@@ -0,0 +1,482 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// AddBundle adds a bundle image to a file-based catalog directory, inserting
+// it into the channels named in its annotations.
+//
+// The upgrade edges of the new channel entries are computed according to
+// Mode, with the same semantics as "opm index add":
+//   - registry.ReplacesMode uses the replaces and skips of the bundle's CSV.
+//   - registry.SemVerMode makes the bundle replace the highest version below
+//     it in each channel, and the lowest version above it replace the bundle.
+//   - registry.SkipPatchMode additionally makes the bundle skip all lower
+//     versions with the same major and minor version. Unlike in sqlite
+//     catalogs, skipped bundles remain in the channel.
+//
+// Only the files that contain the bundle's package are rewritten. New
+// objects are written to the file that contains the package's olm.package
+// blob or, for a new package, to <package>/catalog.json.
+type AddBundle struct {
+	CatalogDir  string
+	BundleImage string
+	Mode        registry.Mode
+
+	Registry image.Registry
+	Log      *logrus.Entry
+}
+
+func (a AddBundle) Run(ctx context.Context) error {
+	if err := a.validate(); err != nil {
+		return err
+	}
+	log := a.Log
+	if log == nil {
+		log = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	b, fbcBundle, err := a.loadBundle(ctx)
+	if err != nil {
+		return err
+	}
+	log = log.WithFields(logrus.Fields{"package": b.Package, "bundle": b.Name})
+
+	files, err := loadPackageFiles(ctx, a.CatalogDir, b.Package)
+	if err != nil {
+		return err
+	}
+	pkg := newCatalogPackage(b.Package, files)
+	if err := pkg.addBundle(b, *fbcBundle, a.Mode); err != nil {
+		return err
+	}
+
+	cfg := pkg.config()
+	if _, err := declcfg.ConvertToModel(cfg); err != nil {
+		return fmt.Errorf("invalid catalog after adding bundle %q to package %q: %v", b.Name, b.Package, err)
+	}
+
+	for _, f := range pkg.files {
+		if !f.dirty {
+			continue
+		}
+		log.WithField("file", f.path).Info("writing catalog file")
+		if err := f.write(a.CatalogDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a AddBundle) validate() error {
+	if a.CatalogDir == "" {
+		return errors.New("catalog directory is unset")
+	}
+	if a.BundleImage == "" {
+		return errors.New("bundle image is unset")
+	}
+	if a.Registry == nil {
+		return errors.New("image registry is unset")
+	}
+	switch a.Mode {
+	case registry.ReplacesMode, registry.SemVerMode, registry.SkipPatchMode:
+	default:
+		return fmt.Errorf("unsupported update mode %d", a.Mode)
+	}
+	return nil
+}
+
+// loadBundle pulls and parses the bundle image, returning both the bundle,
+// which carries its channel annotations, and its declarative config form.
+func (a AddBundle) loadBundle(ctx context.Context) (*registry.Bundle, *declcfg.Bundle, error) {
+	ref := image.SimpleReference(a.BundleImage)
+	if err := a.Registry.Pull(ctx, ref); err != nil {
+		return nil, nil, fmt.Errorf("failed to pull image %q: %v", ref, err)
+	}
+	tmpDir, err := os.MkdirTemp("", "add-bundle-unpack-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := a.Registry.Unpack(ctx, ref, tmpDir); err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack image %q: %v", ref, err)
+	}
+	img, err := registry.NewImageInput(ref, tmpDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse bundle image %q: %v", ref, err)
+	}
+	if len(img.Bundle.Channels) == 0 {
+		return nil, nil, fmt.Errorf("bundle image %q does not declare any channels", ref)
+	}
+
+	fbcBundle, err := bundleToDeclcfg(img.Bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{*fbcBundle}}
+	moveBundleObjectsToEndOfPropertySlices(cfg)
+	sort.Slice(cfg.Bundles[0].RelatedImages, func(i, j int) bool {
+		return cfg.Bundles[0].RelatedImages[i].Image < cfg.Bundles[0].RelatedImages[j].Image
+	})
+	return img.Bundle, &cfg.Bundles[0], nil
+}
+
+// catalogFile is a file of a catalog directory that contains objects of a
+// single package, and possibly others.
+type catalogFile struct {
+	// path is the slash-separated path of the file, relative to the
+	// catalog directory.
+	path  string
+	cfg   *declcfg.DeclarativeConfig
+	dirty bool
+}
+
+func (f *catalogFile) write(catalogDir string) error {
+	writeFunc := declcfg.WriteJSON
+	switch strings.ToLower(path.Ext(f.path)) {
+	case ".yaml", ".yml":
+		writeFunc = declcfg.WriteYAML
+	}
+	var buf bytes.Buffer
+	if err := writeFunc(*f.cfg, &buf); err != nil {
+		return fmt.Errorf("write catalog file %q: %v", f.path, err)
+	}
+	filename := filepath.Join(catalogDir, filepath.FromSlash(f.path))
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return err
+	}
+	// nolint:gosec
+	return os.WriteFile(filename, buf.Bytes(), 0666)
+}
+
+// loadPackageFiles loads the files of the catalog directory that contain
+// objects of the given package, sorted by path.
+func loadPackageFiles(ctx context.Context, catalogDir, pkgName string) ([]*catalogFile, error) {
+	var (
+		mu    sync.Mutex
+		paths = map[string]struct{}{}
+	)
+	root := os.DirFS(catalogDir)
+	if err := declcfg.WalkMetasFS(ctx, root, func(path string, meta *declcfg.Meta, err error) error {
+		if err != nil {
+			return err
+		}
+		if metaPackage(meta) != pkgName {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		paths[path] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("load catalog %q: %v", catalogDir, err)
+	}
+
+	files := make([]*catalogFile, 0, len(paths))
+	for p := range paths {
+		cfg, err := declcfg.LoadFile(root, p)
+		if err != nil {
+			return nil, fmt.Errorf("load catalog file %q: %v", p, err)
+		}
+		files = append(files, &catalogFile{path: p, cfg: cfg})
+	}
+	slices.SortFunc(files, func(a, b *catalogFile) int { return strings.Compare(a.path, b.path) })
+	return files, nil
+}
+
+func metaPackage(meta *declcfg.Meta) string {
+	if meta.Schema == declcfg.SchemaPackage {
+		return meta.Name
+	}
+	return meta.Package
+}
+
+// catalogPackage is a view of the objects of a package that are spread
+// across catalog files, which allows them to be modified in place.
+type catalogPackage struct {
+	name  string
+	files []*catalogFile
+
+	pkg      *declcfg.Package
+	pkgFile  *catalogFile
+	channels map[string]*declcfg.Channel
+	chFiles  map[string]*catalogFile
+	bundles  map[string]*declcfg.Bundle
+}
+
+func newCatalogPackage(name string, files []*catalogFile) *catalogPackage {
+	p := &catalogPackage{
+		name:     name,
+		files:    files,
+		channels: map[string]*declcfg.Channel{},
+		chFiles:  map[string]*catalogFile{},
+		bundles:  map[string]*declcfg.Bundle{},
+	}
+	for _, f := range files {
+		for i := range f.cfg.Packages {
+			if f.cfg.Packages[i].Name == name && p.pkg == nil {
+				p.pkg, p.pkgFile = &f.cfg.Packages[i], f
+			}
+		}
+		for i := range f.cfg.Channels {
+			if ch := &f.cfg.Channels[i]; ch.Package == name {
+				if _, ok := p.channels[ch.Name]; !ok {
+					p.channels[ch.Name], p.chFiles[ch.Name] = ch, f
+				}
+			}
+		}
+		for i := range f.cfg.Bundles {
+			if b := &f.cfg.Bundles[i]; b.Package == name {
+				p.bundles[b.Name] = b
+			}
+		}
+	}
+	return p
+}
+
+// newObjectsFile returns the file that new objects of the package are
+// written to.
+func (p *catalogPackage) newObjectsFile() *catalogFile {
+	if p.pkgFile != nil {
+		return p.pkgFile
+	}
+	if len(p.files) > 0 {
+		return p.files[0]
+	}
+	f := &catalogFile{
+		path: path.Join(p.name, "catalog.json"),
+		cfg:  &declcfg.DeclarativeConfig{},
+	}
+	p.files = append(p.files, f)
+	return f
+}
+
+// config returns all objects of the package. Other packages' objects that
+// share files with it are not included.
+func (p *catalogPackage) config() declcfg.DeclarativeConfig {
+	var cfg declcfg.DeclarativeConfig
+	for _, f := range p.files {
+		for _, pkg := range f.cfg.Packages {
+			if pkg.Name == p.name {
+				cfg.Packages = append(cfg.Packages, pkg)
+			}
+		}
+		for _, ch := range f.cfg.Channels {
+			if ch.Package == p.name {
+				cfg.Channels = append(cfg.Channels, ch)
+			}
+		}
+		for _, b := range f.cfg.Bundles {
+			if b.Package == p.name {
+				cfg.Bundles = append(cfg.Bundles, b)
+			}
+		}
+		for _, d := range f.cfg.Deprecations {
+			if d.Package == p.name {
+				cfg.Deprecations = append(cfg.Deprecations, d)
+			}
+		}
+		for _, o := range f.cfg.Others {
+			if o.Package == p.name {
+				cfg.Others = append(cfg.Others, o)
+			}
+		}
+	}
+	return cfg
+}
+
+func (p *catalogPackage) addBundle(b *registry.Bundle, fbcBundle declcfg.Bundle, mode registry.Mode) error {
+	if _, ok := p.bundles[b.Name]; ok {
+		return fmt.Errorf("bundle %q already exists in package %q", b.Name, p.name)
+	}
+	version, err := bundleSemver(fbcBundle)
+	if err != nil {
+		return err
+	}
+	skipRange, err := b.SkipRange()
+	if err != nil {
+		return fmt.Errorf("get skipRange of bundle %q: %v", b.Name, err)
+	}
+
+	var csvReplaces string
+	var csvSkips []string
+	if mode == registry.ReplacesMode {
+		if csvReplaces, err = b.Replaces(); err != nil {
+			return fmt.Errorf("get replaces of bundle %q: %v", b.Name, err)
+		}
+		if csvSkips, err = b.Skips(); err != nil {
+			return fmt.Errorf("get skips of bundle %q: %v", b.Name, err)
+		}
+	}
+
+	isHighest := true
+	for _, existing := range p.bundles {
+		v, err := bundleSemver(*existing)
+		if err != nil {
+			return err
+		}
+		if v.GT(version) {
+			isHighest = false
+		}
+	}
+	if err := p.setDefaultChannel(b, mode, isHighest); err != nil {
+		return err
+	}
+
+	// New channels are appended last, since appending to a file's channels
+	// may move the existing channels that are modified in place.
+	f := p.newObjectsFile()
+	var newChannels []declcfg.Channel
+	for _, chName := range b.Channels {
+		entry := declcfg.ChannelEntry{
+			Name:      b.Name,
+			Replaces:  csvReplaces,
+			Skips:     csvSkips,
+			SkipRange: skipRange,
+		}
+		ch, ok := p.channels[chName]
+		if !ok {
+			newChannels = append(newChannels, declcfg.Channel{
+				Schema:  declcfg.SchemaChannel,
+				Name:    chName,
+				Package: p.name,
+				Entries: []declcfg.ChannelEntry{entry},
+			})
+			continue
+		}
+		if mode != registry.ReplacesMode {
+			if err := p.insertSemver(ch, &entry, version, mode == registry.SkipPatchMode); err != nil {
+				return fmt.Errorf("insert bundle %q into channel %q: %v", b.Name, chName, err)
+			}
+		}
+		ch.Entries = append(ch.Entries, entry)
+		p.chFiles[chName].dirty = true
+	}
+
+	f.cfg.Channels = append(f.cfg.Channels, newChannels...)
+	f.cfg.Bundles = append(f.cfg.Bundles, fbcBundle)
+	f.dirty = true
+	return nil
+}
+
+// setDefaultChannel creates the package if it does not exist, and updates
+// its default channel from the bundle's annotations. As in sqlite catalogs,
+// the default channel is taken from every bundle added in the semver modes,
+// but only from the highest version in replaces mode.
+func (p *catalogPackage) setDefaultChannel(b *registry.Bundle, mode registry.Mode, isHighest bool) error {
+	var annotations registry.Annotations
+	if b.Annotations != nil {
+		annotations = *b.Annotations
+	}
+	defaultChannel := annotations.DefaultChannelName
+	if p.pkg == nil {
+		if defaultChannel == "" {
+			defaultChannel = annotations.SelectDefaultChannel()
+		}
+		if defaultChannel == "" {
+			return fmt.Errorf("default channel of package %q is missing and can't be inferred", p.name)
+		}
+		f := p.newObjectsFile()
+		f.cfg.Packages = append(f.cfg.Packages, declcfg.Package{
+			Schema:         declcfg.SchemaPackage,
+			Name:           p.name,
+			DefaultChannel: defaultChannel,
+		})
+		p.pkg, p.pkgFile = &f.cfg.Packages[len(f.cfg.Packages)-1], f
+		f.dirty = true
+		return nil
+	}
+	if defaultChannel == "" || defaultChannel == p.pkg.DefaultChannel {
+		return nil
+	}
+	if mode == registry.ReplacesMode && !isHighest {
+		return nil
+	}
+	p.pkg.DefaultChannel = defaultChannel
+	p.pkgFile.dirty = true
+	return nil
+}
+
+// insertSemver sets the upgrade edges of entry, and of the entry above it,
+// so that entry sits between its neighbours by version in ch.
+func (p *catalogPackage) insertSemver(ch *declcfg.Channel, entry *declcfg.ChannelEntry, version semver.Version, skipPatch bool) error {
+	var (
+		lowestAhead, greatestBehind       *declcfg.ChannelEntry
+		lowestAheadVer, greatestBehindVer semver.Version
+		skipPatchCandidates               []string
+	)
+	for i := range ch.Entries {
+		e := &ch.Entries[i]
+		b, ok := p.bundles[e.Name]
+		if !ok {
+			return fmt.Errorf("bundle %q of channel entry not found in package %q", e.Name, p.name)
+		}
+		v, err := bundleSemver(*b)
+		if err != nil {
+			return err
+		}
+		switch v.Compare(version) {
+		case 0:
+			return fmt.Errorf("bundle version %s already exists in channel as %q", version, e.Name)
+		case 1:
+			if lowestAhead == nil || v.LT(lowestAheadVer) {
+				lowestAhead, lowestAheadVer = e, v
+			}
+		case -1:
+			if greatestBehind == nil || v.GT(greatestBehindVer) {
+				greatestBehind, greatestBehindVer = e, v
+			}
+			if skipPatch && v.Major == version.Major && v.Minor == version.Minor {
+				skipPatchCandidates = append(skipPatchCandidates, e.Name)
+			}
+		}
+	}
+
+	if greatestBehind != nil {
+		entry.Replaces = greatestBehind.Name
+	}
+	for _, name := range skipPatchCandidates {
+		if name != entry.Replaces {
+			entry.Skips = append(entry.Skips, name)
+		}
+	}
+	sort.Strings(entry.Skips)
+	if lowestAhead != nil {
+		lowestAhead.Replaces = entry.Name
+	}
+	return nil
+}
+
+func bundleSemver(b declcfg.Bundle) (semver.Version, error) {
+	props, err := property.Parse(b.Properties)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("parse properties of bundle %q: %v", b.Name, err)
+	}
+	if len(props.Packages) != 1 {
+		return semver.Version{}, fmt.Errorf("bundle %q must have exactly 1 %q property, found %d", b.Name, property.TypePackage, len(props.Packages))
+	}
+	v, err := semver.Parse(props.Packages[0].Version)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("parse version %q of bundle %q: %v", props.Packages[0].Version, b.Name, err)
+	}
+	return v, nil
+}
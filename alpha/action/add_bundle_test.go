@@ -0,0 +1,157 @@
+package action
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+const barCatalog = `{
+    "schema": "olm.package",
+    "name": "bar",
+    "defaultChannel": "alpha"
+}
+`
+
+func TestAddBundle(t *testing.T) {
+	reg := &image.MockRegistry{
+		RemoteImages: map[image.Reference]*image.MockImage{},
+	}
+	for _, v := range []string{"v0.2.0", "v0.3.0", "v0.3.1"} {
+		ref := image.SimpleReference("test.registry/foo-operator/foo-bundle:" + v)
+		reg.RemoteImages[ref] = &image.MockImage{FS: os.DirFS(filepath.Join("testdata", "foo-bundle-"+v))}
+	}
+	bundleRef := func(v string) string { return "test.registry/foo-operator/foo-bundle:" + v }
+
+	type add struct {
+		version string
+		mode    registry.Mode
+	}
+	type spec struct {
+		name             string
+		emptyCatalog     bool
+		adds             []add
+		expectedEntries  map[string][]declcfg.ChannelEntry
+		expectedError    string
+		expectedNewFiles []string
+	}
+	fooV010 := declcfg.ChannelEntry{Name: "foo.v0.1.0", SkipRange: "<0.1.0"}
+	fooV020 := declcfg.ChannelEntry{Name: "foo.v0.2.0", Replaces: "foo.v0.1.0", Skips: []string{"foo.v0.1.1", "foo.v0.1.2"}, SkipRange: "<0.2.0"}
+	stable := []declcfg.ChannelEntry{fooV020}
+	specs := []spec{
+		{
+			name: "Success/Replaces",
+			adds: []add{{"v0.3.0", registry.ReplacesMode}, {"v0.3.1", registry.ReplacesMode}},
+			expectedEntries: map[string][]declcfg.ChannelEntry{
+				"beta": {
+					fooV010, fooV020,
+					{Name: "foo.v0.3.0", Replaces: "foo.v0.2.0"},
+					{Name: "foo.v0.3.1", Replaces: "foo.v0.2.0", Skips: []string{"foo.v0.3.0"}},
+				},
+				"stable": stable,
+			},
+		},
+		{
+			name: "Success/SemverInsertBelowHead",
+			adds: []add{{"v0.3.1", registry.SemVerMode}, {"v0.3.0", registry.SemVerMode}},
+			expectedEntries: map[string][]declcfg.ChannelEntry{
+				"beta": {
+					fooV010, fooV020,
+					{Name: "foo.v0.3.1", Replaces: "foo.v0.3.0"},
+					{Name: "foo.v0.3.0", Replaces: "foo.v0.2.0"},
+				},
+				"stable": stable,
+			},
+		},
+		{
+			name: "Success/SemverSkipPatch",
+			adds: []add{{"v0.3.0", registry.SkipPatchMode}, {"v0.3.1", registry.SkipPatchMode}},
+			expectedEntries: map[string][]declcfg.ChannelEntry{
+				"beta": {
+					fooV010, fooV020,
+					{Name: "foo.v0.3.0", Replaces: "foo.v0.2.0"},
+					{Name: "foo.v0.3.1", Replaces: "foo.v0.3.0"},
+				},
+				"stable": stable,
+			},
+		},
+		{
+			name:         "Success/NewPackage",
+			emptyCatalog: true,
+			adds:         []add{{"v0.2.0", registry.SemVerMode}, {"v0.3.0", registry.SemVerMode}},
+			expectedEntries: map[string][]declcfg.ChannelEntry{
+				"beta": {
+					{Name: "foo.v0.2.0", SkipRange: "<0.2.0"},
+					{Name: "foo.v0.3.0", Replaces: "foo.v0.2.0"},
+				},
+				"stable": {{Name: "foo.v0.2.0", SkipRange: "<0.2.0"}},
+			},
+			expectedNewFiles: []string{"foo/catalog.json"},
+		},
+		{
+			name:          "Fail/BundleExists",
+			adds:          []add{{"v0.2.0", registry.SemVerMode}},
+			expectedError: `bundle "foo.v0.2.0" already exists in package "foo"`,
+		},
+		{
+			name:          "Fail/MissingImage",
+			adds:          []add{{"v0.4.0", registry.SemVerMode}},
+			expectedError: `failed to pull image "test.registry/foo-operator/foo-bundle:v0.4.0": not found`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			catalogDir := t.TempDir()
+			if !s.emptyCatalog {
+				require.NoError(t, os.CopyFS(catalogDir, os.DirFS("testdata/foo-index-v0.2.0-declcfg")))
+			}
+			require.NoError(t, os.MkdirAll(filepath.Join(catalogDir, "bar"), 0777))
+			require.NoError(t, os.WriteFile(filepath.Join(catalogDir, "bar", "catalog.json"), []byte(barCatalog), 0600))
+
+			var err error
+			for _, a := range s.adds {
+				err = AddBundle{
+					CatalogDir:  catalogDir,
+					BundleImage: bundleRef(a.version),
+					Mode:        a.mode,
+					Registry:    reg,
+				}.Run(context.Background())
+				if err != nil {
+					break
+				}
+			}
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			// Other packages' files are left untouched.
+			bar, err := os.ReadFile(filepath.Join(catalogDir, "bar", "catalog.json"))
+			require.NoError(t, err)
+			require.Equal(t, barCatalog, string(bar))
+			for _, f := range s.expectedNewFiles {
+				require.FileExists(t, filepath.Join(catalogDir, f))
+			}
+
+			cfg, err := declcfg.LoadFS(context.Background(), os.DirFS(catalogDir))
+			require.NoError(t, err)
+			entries := map[string][]declcfg.ChannelEntry{}
+			for _, ch := range cfg.Channels {
+				entries[ch.Name] = ch.Entries
+			}
+			require.Equal(t, s.expectedEntries, entries)
+			for _, b := range cfg.Bundles {
+				require.Equal(t, "foo", b.Package)
+			}
+			require.Len(t, cfg.Bundles, len(s.expectedEntries["beta"]))
+		})
+	}
+}
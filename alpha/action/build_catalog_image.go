@@ -0,0 +1,259 @@
+package action
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+const (
+	// catalogImageConfigsDir and catalogImageCacheDir match the locations used
+	// by the Dockerfile that GenerateDockerfile writes.
+	catalogImageConfigsDir = "configs"
+	catalogImageCacheDir   = "tmp/cache"
+
+	// catalogImageCacheUID is the user of the opm image, which must be able
+	// to open the serve cache. The cache is also writable by group 0, so
+	// that it can be used by the arbitrary users that some platforms run
+	// containers as.
+	catalogImageCacheUID      = 1001
+	catalogImageCacheModeDir  = 0775
+	catalogImageCacheModeFile = 0664
+)
+
+// BuildCatalogImage builds a catalog image from a file-based catalog
+// directory without a container runtime. The image contains the catalog at
+// /configs and a serve cache for it at /tmp/cache, and is either pushed to
+// the registry of its reference or written to an OCI image layout directory.
+//
+// The serve cache is built by this version of opm, so the opm in the base
+// image must be able to read it.
+type BuildCatalogImage struct {
+	CatalogDir string
+	// ImageRef is the reference of the image to build.
+	ImageRef string
+	// BaseImage is the image to add the catalog to. If it is "scratch", the
+	// image contains only the catalog and its cache. Otherwise, the base
+	// image is expected to contain /bin/opm, which is set as the entrypoint.
+	BaseImage string
+	// OCILayoutDir, if set, is the OCI image layout directory to write the
+	// image to, tagged with ImageRef. Otherwise, the image is pushed.
+	OCILayoutDir string
+	ExtraLabels  map[string]string
+
+	// Registry must also implement image.Packer.
+	Registry image.Registry
+	Log      *logrus.Entry
+}
+
+func (b BuildCatalogImage) Run(ctx context.Context) error {
+	if err := b.validate(); err != nil {
+		return err
+	}
+	packer, ok := b.Registry.(image.Packer)
+	if !ok {
+		return fmt.Errorf("image registry %T cannot build images", b.Registry)
+	}
+	log := b.Log
+	if log == nil {
+		log = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	catalogDir, err := filepath.EvalSymlinks(b.CatalogDir)
+	if err != nil {
+		return err
+	}
+	if s, err := os.Stat(catalogDir); err != nil {
+		return err
+	} else if !s.IsDir() {
+		return fmt.Errorf("provided catalog path %q is not a directory", b.CatalogDir)
+	}
+
+	cacheDir, err := os.MkdirTemp("", "opm-build-cache-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(cacheDir)
+	log.Info("building serve cache")
+	if err := buildServeCache(ctx, catalogDir, cacheDir, log); err != nil {
+		return fmt.Errorf("build serve cache: %v", err)
+	}
+
+	labels := map[string]string{}
+	for k, v := range b.ExtraLabels {
+		labels[k] = v
+	}
+	labels[containertools.ConfigsLocationLabel] = "/" + catalogImageConfigsDir
+	packOpts := []image.PackOption{image.WithLabels(labels)}
+	if b.BaseImage != "scratch" {
+		base := image.SimpleReference(b.BaseImage)
+		log.WithField("image", b.BaseImage).Info("pulling base image")
+		if err := b.Registry.Pull(ctx, base); err != nil {
+			return fmt.Errorf("pull base image %q: %v", b.BaseImage, err)
+		}
+		packOpts = append(packOpts,
+			image.WithBase(base),
+			image.WithEntrypoint("/bin/opm"),
+			image.WithCmd("serve", "/"+catalogImageConfigsDir, "--cache-dir=/"+catalogImageCacheDir),
+		)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeCatalogLayer(pw, catalogDir, cacheDir))
+	}()
+	ref := image.SimpleReference(b.ImageRef)
+	err = packer.Pack(ctx, ref, pr, packOpts...)
+	pr.CloseWithError(err)
+	if err != nil {
+		return fmt.Errorf("build image: %v", err)
+	}
+
+	if b.OCILayoutDir != "" {
+		log.WithField("dir", b.OCILayoutDir).Info("writing image to OCI layout")
+		if err := packer.Export(ctx, ref, b.OCILayoutDir, b.ImageRef); err != nil {
+			return fmt.Errorf("write image to OCI layout %q: %v", b.OCILayoutDir, err)
+		}
+		return nil
+	}
+	log.WithField("image", b.ImageRef).Info("pushing image")
+	if err := packer.Push(ctx, ref); err != nil {
+		return fmt.Errorf("push image %q: %v", b.ImageRef, err)
+	}
+	return nil
+}
+
+func (b BuildCatalogImage) validate() error {
+	if b.CatalogDir == "" {
+		return errors.New("catalog directory is unset")
+	}
+	if b.ImageRef == "" {
+		return errors.New("image reference is unset")
+	}
+	if b.BaseImage == "" {
+		return errors.New("base image is unset")
+	}
+	if b.Registry == nil {
+		return errors.New("image registry is unset")
+	}
+	return nil
+}
+
+func buildServeCache(ctx context.Context, catalogDir, cacheDir string, log *logrus.Entry) error {
+	c, err := cache.New(cacheDir, cache.WithLog(log))
+	if err != nil {
+		return err
+	}
+	if err := c.Build(ctx, os.DirFS(catalogDir)); err != nil {
+		return errors.Join(err, c.Close())
+	}
+	return c.Close()
+}
+
+// writeCatalogLayer writes a tar archive with the catalog at
+// catalogImageConfigsDir and the serve cache at catalogImageCacheDir.
+// Ownership and timestamps are reset so that the layer does not depend on
+// who built it, or when, and the serve cache is made usable by the user that
+// the opm image runs as.
+func writeCatalogLayer(w io.Writer, catalogDir, cacheDir string) error {
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(catalogLayerHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     "tmp/",
+		Mode:     01777,
+	})); err != nil {
+		return err
+	}
+	if err := addDirToTar(tw, catalogDir, catalogImageConfigsDir, catalogLayerHeader); err != nil {
+		return err
+	}
+	if err := addDirToTar(tw, cacheDir, catalogImageCacheDir, catalogCacheLayerHeader); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// addDirToTar adds the contents of dir to tw under prefix, passing each header
+// through fixHeader before it is written.
+func addDirToTar(tw *tar.Writer, dir, prefix string, fixHeader func(*tar.Header) *tar.Header) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("get file info for %q: %v", p, err)
+		}
+		var link string
+		if d.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		h, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("build tar file info header for %q: %v", p, err)
+		}
+		h.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if d.IsDir() {
+			h.Name += "/"
+		}
+		if err := tw.WriteHeader(fixHeader(h)); err != nil {
+			return fmt.Errorf("write tar header for %q: %v", p, err)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("open file %q: %v", p, err)
+		}
+		defer f.Close()
+		if _, err := io.Copy(tw, f); err != nil {
+			return fmt.Errorf("write tar data for %q: %v", p, err)
+		}
+		return nil
+	})
+}
+
+func catalogLayerHeader(h *tar.Header) *tar.Header {
+	h.Uid = 0
+	h.Gid = 0
+	h.Uname = ""
+	h.Gname = ""
+	h.ModTime = time.Unix(0, 0)
+	h.AccessTime = time.Time{}
+	h.ChangeTime = time.Time{}
+	return h
+}
+
+// catalogCacheLayerHeader is catalogLayerHeader for the serve cache, which is
+// owned by the opm image user instead of root.
+func catalogCacheLayerHeader(h *tar.Header) *tar.Header {
+	h = catalogLayerHeader(h)
+	h.Uid = catalogImageCacheUID
+	switch h.Typeflag {
+	case tar.TypeDir:
+		h.Mode = catalogImageCacheModeDir
+	case tar.TypeReg:
+		h.Mode = catalogImageCacheModeFile
+	}
+	return h
+}
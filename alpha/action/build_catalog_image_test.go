@@ -0,0 +1,184 @@
+package action
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// packingRegistry is a mock registry that records the images it packs.
+type packingRegistry struct {
+	*image.MockRegistry
+	opts     image.PackOptions
+	entries  map[string]*tar.Header
+	pushed   []image.Reference
+	exported map[string]image.Reference
+}
+
+func (r *packingRegistry) Pack(_ context.Context, _ image.Reference, from io.Reader, opts ...image.PackOption) error {
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+	r.entries = map[string]*tar.Header{}
+	tr := tar.NewReader(from)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.entries[h.Name] = h
+	}
+}
+
+func (r *packingRegistry) Push(_ context.Context, ref image.Reference) error {
+	r.pushed = append(r.pushed, ref)
+	return nil
+}
+
+func (r *packingRegistry) Export(_ context.Context, ref image.Reference, dir, _ string) error {
+	if r.exported == nil {
+		r.exported = map[string]image.Reference{}
+	}
+	r.exported[dir] = ref
+	return nil
+}
+
+func TestBuildCatalogImage(t *testing.T) {
+	const (
+		imageRef  = "test.registry/foo-operator/foo-index:v0.2.0"
+		baseImage = "test.registry/opm:latest"
+	)
+	type spec struct {
+		name          string
+		build         BuildCatalogImage
+		registry      image.Registry
+		assertPacked  func(*testing.T, *packingRegistry)
+		expectedError string
+	}
+	newRegistry := func() *packingRegistry {
+		return &packingRegistry{MockRegistry: &image.MockRegistry{
+			RemoteImages: map[image.Reference]*image.MockImage{
+				image.SimpleReference(baseImage): {FS: fstest.MapFS{"bin/opm": &fstest.MapFile{}}},
+			},
+		}}
+	}
+	specs := []spec{
+		{
+			name: "Success/BaseImagePush",
+			build: BuildCatalogImage{
+				CatalogDir:  "testdata/foo-index-v0.2.0-declcfg",
+				ImageRef:    imageRef,
+				BaseImage:   baseImage,
+				ExtraLabels: map[string]string{"example.com/label": "value"},
+			},
+			registry: newRegistry(),
+			assertPacked: func(t *testing.T, r *packingRegistry) {
+				require.Equal(t, image.SimpleReference(baseImage), r.opts.Base)
+				require.Equal(t, map[string]string{
+					containertools.ConfigsLocationLabel: "/configs",
+					"example.com/label":                 "value",
+				}, r.opts.Labels)
+				require.Equal(t, []string{"/bin/opm"}, r.opts.Entrypoint)
+				require.Equal(t, []string{"serve", "/configs", "--cache-dir=/tmp/cache"}, r.opts.Cmd)
+				require.Equal(t, []image.Reference{image.SimpleReference(imageRef)}, r.pushed)
+				require.Empty(t, r.exported)
+			},
+		},
+		{
+			name: "Success/ScratchExport",
+			build: BuildCatalogImage{
+				CatalogDir:   "testdata/foo-index-v0.2.0-declcfg",
+				ImageRef:     imageRef,
+				BaseImage:    "scratch",
+				OCILayoutDir: "catalog-layout",
+			},
+			registry: newRegistry(),
+			assertPacked: func(t *testing.T, r *packingRegistry) {
+				require.Nil(t, r.opts.Base)
+				require.Nil(t, r.opts.Entrypoint)
+				require.Equal(t, map[string]string{containertools.ConfigsLocationLabel: "/configs"}, r.opts.Labels)
+				require.Empty(t, r.pushed)
+				require.Equal(t, map[string]image.Reference{"catalog-layout": image.SimpleReference(imageRef)}, r.exported)
+			},
+		},
+		{
+			name: "Fail/MissingBaseImage",
+			build: BuildCatalogImage{
+				CatalogDir: "testdata/foo-index-v0.2.0-declcfg",
+				ImageRef:   imageRef,
+				BaseImage:  "test.registry/missing:latest",
+			},
+			registry:      newRegistry(),
+			expectedError: `pull base image "test.registry/missing:latest": not found`,
+		},
+		{
+			name: "Fail/NotADirectory",
+			build: BuildCatalogImage{
+				CatalogDir: "testdata/foo-index-v0.2.0-declcfg/foo/index.yaml",
+				ImageRef:   imageRef,
+				BaseImage:  "scratch",
+			},
+			registry:      newRegistry(),
+			expectedError: `provided catalog path "testdata/foo-index-v0.2.0-declcfg/foo/index.yaml" is not a directory`,
+		},
+		{
+			name: "Fail/RegistryCannotPack",
+			build: BuildCatalogImage{
+				CatalogDir: "testdata/foo-index-v0.2.0-declcfg",
+				ImageRef:   imageRef,
+				BaseImage:  "scratch",
+			},
+			registry:      &image.MockRegistry{},
+			expectedError: "image registry *image.MockRegistry cannot build images",
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			s.build.Registry = s.registry
+			err := s.build.Run(context.Background())
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			r := s.registry.(*packingRegistry)
+			require.Contains(t, r.entries, "configs/foo/index.yaml")
+			require.Contains(t, r.entries, "tmp/cache/")
+			require.Equal(t, int64(01777), r.entries["tmp/"].Mode)
+			var cacheFiles int
+			for name, h := range r.entries {
+				require.Zero(t, h.Gid, name)
+				require.Zero(t, h.ModTime.Unix(), name)
+				if !strings.HasPrefix(name, "tmp/cache/") {
+					require.Zero(t, h.Uid, name)
+					continue
+				}
+				// The opm image runs as user 1001, which must be able to
+				// open the cache, as must group 0.
+				require.Equal(t, 1001, h.Uid, name)
+				switch h.Typeflag {
+				case tar.TypeDir:
+					require.Equal(t, int64(0775), h.Mode, name)
+				case tar.TypeReg:
+					require.Equal(t, int64(0664), h.Mode, name)
+					cacheFiles++
+				}
+			}
+			require.NotZero(t, cacheFiles)
+			s.assertPacked(t, r)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// Diff renders two references and computes the structural difference between them.
+// The references may be anything that Render accepts.
+type Diff struct {
+	OldRef   string
+	NewRef   string
+	Registry image.Registry
+}
+
+func (d Diff) Run(ctx context.Context) (*declcfg.DiffResult, error) {
+	oldCfg, err := d.render(ctx, d.OldRef)
+	if err != nil {
+		return nil, fmt.Errorf("render old reference: %w", err)
+	}
+	newCfg, err := d.render(ctx, d.NewRef)
+	if err != nil {
+		return nil, fmt.Errorf("render new reference: %w", err)
+	}
+	return declcfg.Diff(*oldCfg, *newCfg)
+}
+
+func (d Diff) render(ctx context.Context, ref string) (*declcfg.DeclarativeConfig, error) {
+	r := Render{
+		Refs:     []string{ref},
+		Registry: d.Registry,
+
+		// Diffing is a read-only operation, so avoid logging the sqlite
+		// deprecation notice for each of the two references.
+		skipSqliteDeprecationLog: true,
+	}
+	return r.Run(ctx)
+}
@@ -0,0 +1,65 @@
+package action
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+func TestDiff(t *testing.T) {
+	newDir := t.TempDir()
+	cfg, err := declcfg.LoadFS(context.Background(), os.DirFS("testdata/list-index"))
+	require.NoError(t, err)
+	for i := range cfg.Packages {
+		if cfg.Packages[i].Name == "foo" {
+			cfg.Packages[i].DefaultChannel = "stable"
+		}
+	}
+	require.NoError(t, declcfg.WriteFS(*cfg, filepath.Join(newDir, "index"), declcfg.WriteJSON, ".json"))
+
+	type spec struct {
+		name        string
+		diff        Diff
+		expected    *declcfg.DiffResult
+		expectedErr string
+	}
+	specs := []spec{
+		{
+			name:     "Success/Identical",
+			diff:     Diff{OldRef: "testdata/list-index", NewRef: "testdata/list-index"},
+			expected: &declcfg.DiffResult{},
+		},
+		{
+			name: "Success/Changed",
+			diff: Diff{OldRef: "testdata/list-index", NewRef: filepath.Join(newDir, "index")},
+			expected: &declcfg.DiffResult{
+				Packages: []declcfg.PackageDiff{{
+					Name:   "foo",
+					Change: declcfg.ChangeChanged,
+					Fields: []declcfg.FieldChange{{Field: "defaultChannel", Old: "beta", New: "stable"}},
+				}},
+			},
+		},
+		{
+			name:        "Error/UnknownNewRef",
+			diff:        Diff{OldRef: "testdata/list-index", NewRef: "unknown-index"},
+			expectedErr: `render new reference: render reference "unknown-index": failed to pull image "unknown-index": repository name must be canonical`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			actual, err := s.diff.Run(context.Background())
+			if s.expectedErr != "" {
+				require.EqualError(t, err, s.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expected, actual)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package action
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/operator-framework/operator-registry/alpha/action/migrations"
@@ -15,12 +16,21 @@ type Migrate struct {
 	OutputDir  string
 	Migrations *migrations.Migrations
 
+	// DryRun writes a report of the changes that Migrations make to the
+	// catalog to ReportWriter, instead of writing the catalog to OutputDir.
+	DryRun       bool
+	ReportWriter io.Writer
+
 	WriteFunc declcfg.WriteFunc
 	FileExt   string
 	Registry  image.Registry
 }
 
 func (m Migrate) Run(ctx context.Context) error {
+	if m.DryRun {
+		return m.dryRun(ctx)
+	}
+
 	entries, err := os.ReadDir(m.OutputDir)
 	if err != nil && !os.IsNotExist(err) {
 		return err
@@ -29,9 +39,39 @@ func (m Migrate) Run(ctx context.Context) error {
 		return fmt.Errorf("output dir %q must be empty", m.OutputDir)
 	}
 
+	cfg, err := m.render(ctx, m.Migrations)
+	if err != nil {
+		return err
+	}
+
+	return declcfg.WriteFS(*cfg, m.OutputDir, m.WriteFunc, m.FileExt)
+}
+
+func (m Migrate) dryRun(ctx context.Context) error {
+	cfg, err := m.render(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var changes []migrations.Change
+	if m.Migrations != nil {
+		changes, err = m.Migrations.DryRun(cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	w := m.ReportWriter
+	if w == nil {
+		w = os.Stdout
+	}
+	return migrations.WriteReport(w, changes)
+}
+
+func (m Migrate) render(ctx context.Context, migrations *migrations.Migrations) (*declcfg.DeclarativeConfig, error) {
 	r := Render{
 		Refs:       []string{m.CatalogRef},
-		Migrations: m.Migrations,
+		Migrations: migrations,
 
 		// Only allow catalogs to be migrated.
 		AllowedRefMask: RefSqliteImage | RefSqliteFile | RefDCImage | RefDCDir,
@@ -42,8 +82,7 @@ func (m Migrate) Run(ctx context.Context) error {
 
 	cfg, err := r.Run(ctx)
 	if err != nil {
-		return fmt.Errorf("render catalog image: %w", err)
+		return nil, fmt.Errorf("render catalog image: %w", err)
 	}
-
-	return declcfg.WriteFS(*cfg, m.OutputDir, m.WriteFunc, m.FileExt)
+	return cfg, nil
 }
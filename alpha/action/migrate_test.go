@@ -1,6 +1,7 @@
 package action_test
 
 import (
+	"bytes"
 	"context"
 	"io/fs"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/alpha/action/migrations"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/pkg/containertools"
 	"github.com/operator-framework/operator-registry/pkg/image"
@@ -141,6 +143,42 @@ func TestMigrate(t *testing.T) {
 	}
 }
 
+func TestMigrateDryRun(t *testing.T) {
+	sqliteBundles := map[image.Reference]string{
+		image.SimpleReference("test.registry/foo-operator/foo-bundle:v0.1.0"): "testdata/foo-bundle-v0.1.0",
+		image.SimpleReference("test.registry/foo-operator/foo-bundle:v0.2.0"): "testdata/foo-bundle-v0.2.0",
+	}
+	dbFile := filepath.Join(t.TempDir(), "index.db")
+	require.NoError(t, generateSqliteFile(dbFile, sqliteBundles))
+	reg, err := newMigrateRegistry(t, sqliteBundles)
+	require.NoError(t, err)
+
+	m, err := migrations.NewMigrations(migrations.AllMigrations)
+	require.NoError(t, err)
+
+	var report bytes.Buffer
+	outputDir := t.TempDir()
+	migrate := action.Migrate{
+		CatalogRef:   dbFile,
+		OutputDir:    outputDir,
+		Migrations:   m,
+		DryRun:       true,
+		ReportWriter: &report,
+		WriteFunc:    declcfg.WriteYAML,
+		FileExt:      ".yaml",
+		Registry:     reg,
+	}
+	require.NoError(t, migrate.Run(context.Background()))
+	require.Equal(t, `MIGRATION                      SCHEMA      PACKAGE  NAME        CHANGE
+bundle-object-to-csv-metadata  olm.bundle  foo      foo.v0.1.0  replaced 2 "olm.bundle.object" properties with an "olm.csv.metadata" property
+bundle-object-to-csv-metadata  olm.bundle  foo      foo.v0.2.0  replaced 2 "olm.bundle.object" properties with an "olm.csv.metadata" property
+`, report.String())
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "dry run is not expected to write the catalog")
+}
+
 func newMigrateRegistry(t *testing.T, imageMap map[image.Reference]string) (image.Registry, error) {
 	subSqliteImage, err := generateSqliteFS(t, imageMap)
 	if err != nil {
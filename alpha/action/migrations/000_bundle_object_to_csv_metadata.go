@@ -2,46 +2,176 @@ package migrations
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/api/pkg/lib/version"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/alpha/property"
 )
 
-func bundleObjectToCSVMetadata(cfg *declcfg.DeclarativeConfig) error {
-	convertBundleObjectToCSVMetadata := func(b *declcfg.Bundle) error {
+func bundleObjectToCSVMetadata(cfg *declcfg.DeclarativeConfig) ([]Change, error) {
+	convertBundleObjectToCSVMetadata := func(b *declcfg.Bundle) (*Change, error) {
 		if b.Image == "" || b.CsvJSON == "" {
-			return nil
+			return nil, nil
 		}
 
 		var csv v1alpha1.ClusterServiceVersion
 		if err := json.Unmarshal([]byte(b.CsvJSON), &csv); err != nil {
-			return err
+			return nil, err
 		}
 
-		props := b.Properties[:0]
+		props := make([]property.Property, 0, len(b.Properties))
+		removed := 0
 		for _, p := range b.Properties {
 			switch p.Type {
 			case property.TypeBundleObject:
 				// Get rid of the bundle objects
+				removed++
 			case property.TypeCSVMetadata:
 				// If this bundle already has a CSV metadata
 				// property, we won't mutate the bundle at all.
-				return nil
+				return nil, nil
 			default:
 				// Keep all of the other properties
 				props = append(props, p)
 			}
 		}
 		b.Properties = append(props, property.MustBuildCSVMetadata(csv))
-		return nil
+		return &Change{
+			Schema:  declcfg.SchemaBundle,
+			Package: b.Package,
+			Name:    b.Name,
+			Message: fmt.Sprintf("replaced %d %q properties with an %q property", removed, property.TypeBundleObject, property.TypeCSVMetadata),
+		}, nil
 	}
 
+	var changes []Change
 	for bi := range cfg.Bundles {
-		if err := convertBundleObjectToCSVMetadata(&cfg.Bundles[bi]); err != nil {
-			return err
+		c, err := convertBundleObjectToCSVMetadata(&cfg.Bundles[bi])
+		if err != nil {
+			return nil, err
 		}
+		if c != nil {
+			changes = append(changes, *c)
+		}
+	}
+	return changes, nil
+}
+
+// csvMetadataToBundleObject regenerates the "olm.bundle.object" properties
+// of bundles that only have an "olm.csv.metadata" property, for clients that
+// read bundle metadata from their CSV. The bundle's objects are used if they
+// are known, e.g. because the bundle was rendered from an image. Otherwise,
+// a CSV is built from the metadata, and other objects are not restored.
+func csvMetadataToBundleObject(cfg *declcfg.DeclarativeConfig) ([]Change, error) {
+	convertCSVMetadataToBundleObject := func(b *declcfg.Bundle) (*Change, error) {
+		if b.Image == "" {
+			return nil, nil
+		}
+
+		var (
+			metadata *property.CSVMetadata
+			props    = make([]property.Property, 0, len(b.Properties))
+		)
+		for i, p := range b.Properties {
+			switch p.Type {
+			case property.TypeBundleObject:
+				// If this bundle already has bundle object
+				// properties, we won't mutate the bundle at all.
+				return nil, nil
+			case property.TypeCSVMetadata:
+				metadata = &property.CSVMetadata{}
+				if err := json.Unmarshal(p.Value, metadata); err != nil {
+					return nil, fmt.Errorf("package %q, bundle %q: parse property at index %d as CSV metadata: %v", b.Package, b.Name, i, err)
+				}
+			default:
+				props = append(props, p)
+			}
+		}
+		if metadata == nil {
+			return nil, nil
+		}
+
+		message := fmt.Sprintf("replaced the %q property with %d %q properties", property.TypeCSVMetadata, len(b.Objects), property.TypeBundleObject)
+		if len(b.Objects) == 0 {
+			csvJSON, err := csvFromMetadata(b, *metadata)
+			if err != nil {
+				return nil, err
+			}
+			b.Objects = []string{csvJSON}
+			b.CsvJSON = csvJSON
+			message = fmt.Sprintf("replaced the %q property with a %q property for a CSV built from it", property.TypeCSVMetadata, property.TypeBundleObject)
+		}
+		for _, obj := range b.Objects {
+			props = append(props, property.MustBuildBundleObject([]byte(obj)))
+		}
+		b.Properties = props
+		return &Change{
+			Schema:  declcfg.SchemaBundle,
+			Package: b.Package,
+			Name:    b.Name,
+			Message: message,
+		}, nil
+	}
+
+	var changes []Change
+	for bi := range cfg.Bundles {
+		c, err := convertCSVMetadataToBundleObject(&cfg.Bundles[bi])
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			changes = append(changes, *c)
+		}
+	}
+	return changes, nil
+}
+
+// csvFromMetadata returns the JSON of a CSV for b with metadata.
+func csvFromMetadata(b *declcfg.Bundle, metadata property.CSVMetadata) (string, error) {
+	csv := v1alpha1.ClusterServiceVersion{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.ClusterServiceVersionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        b.Name,
+			Annotations: metadata.Annotations,
+			Labels:      metadata.Labels,
+		},
+		Spec: v1alpha1.ClusterServiceVersionSpec{
+			APIServiceDefinitions:     metadata.APIServiceDefinitions,
+			CustomResourceDefinitions: metadata.CustomResourceDefinitions,
+			Description:               metadata.Description,
+			DisplayName:               metadata.DisplayName,
+			InstallModes:              metadata.InstallModes,
+			Keywords:                  metadata.Keywords,
+			Links:                     metadata.Links,
+			Maintainers:               metadata.Maintainers,
+			Maturity:                  metadata.Maturity,
+			MinKubeVersion:            metadata.MinKubeVersion,
+			NativeAPIs:                metadata.NativeAPIs,
+			Provider:                  metadata.Provider,
+		},
+	}
+	props, err := property.Parse(b.Properties)
+	if err != nil {
+		return "", fmt.Errorf("package %q, bundle %q: %v", b.Package, b.Name, err)
+	}
+	if len(props.Packages) == 1 {
+		v, err := semver.Parse(props.Packages[0].Version)
+		if err != nil {
+			return "", fmt.Errorf("package %q, bundle %q: parse version: %v", b.Package, b.Name, err)
+		}
+		csv.Spec.Version = version.OperatorVersion{Version: v}
+	}
+	data, err := json.Marshal(csv)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	return string(data), nil
 }
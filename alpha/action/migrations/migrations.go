@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"text/tabwriter"
@@ -21,16 +22,52 @@ type Migration interface {
 	Token() MigrationToken
 	Help() string
 	Migrate(*declcfg.DeclarativeConfig) error
+	// Describe returns the changes that Migrate makes to a config, without
+	// changing it.
+	Describe(*declcfg.DeclarativeConfig) ([]Change, error)
 }
 
-func newMigration(token string, help string, fn func(config *declcfg.DeclarativeConfig) error) Migration {
+// ReversibleMigration is a migration that can be undone, for example to
+// produce a catalog for clients that predate the migration.
+type ReversibleMigration interface {
+	Migration
+	// Inverse returns the migration that undoes this one.
+	Inverse() Migration
+}
+
+// Change describes how a migration changes one object of a config.
+type Change struct {
+	Migration MigrationToken `json:"migration"`
+	Schema    string         `json:"schema"`
+	Package   string         `json:"package,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Message   string         `json:"message"`
+}
+
+// migrationFunc changes config in place, and returns a description of the
+// changes it made. The token of the migration is set by the caller.
+type migrationFunc func(config *declcfg.DeclarativeConfig) ([]Change, error)
+
+func newMigration(token string, help string, fn migrationFunc) Migration {
 	return &simpleMigration{token: MigrationToken(token), help: help, fn: fn}
 }
 
+// inverseTokenSuffix is appended to the token of a reversible migration to
+// identify its inverse, so that the changes it makes are not reported as
+// those of the migration it undoes.
+const inverseTokenSuffix = "-inverse"
+
+func newReversibleMigration(token string, help string, fn migrationFunc, inverseHelp string, inverse migrationFunc) Migration {
+	return &reversibleMigration{
+		simpleMigration: simpleMigration{token: MigrationToken(token), help: help, fn: fn},
+		inverse:         simpleMigration{token: MigrationToken(token + inverseTokenSuffix), help: inverseHelp, fn: inverse},
+	}
+}
+
 type simpleMigration struct {
 	token MigrationToken
 	help  string
-	fn    func(*declcfg.DeclarativeConfig) error
+	fn    migrationFunc
 }
 
 func (s simpleMigration) Token() MigrationToken {
@@ -38,13 +75,34 @@ func (s simpleMigration) Token() MigrationToken {
 }
 
 func (s simpleMigration) Migrate(config *declcfg.DeclarativeConfig) error {
-	return s.fn(config)
+	_, err := s.fn(config)
+	return err
+}
+
+func (s simpleMigration) Describe(config *declcfg.DeclarativeConfig) ([]Change, error) {
+	changes, err := s.fn(cloneConfig(config))
+	if err != nil {
+		return nil, err
+	}
+	for i := range changes {
+		changes[i].Migration = s.token
+	}
+	return changes, nil
 }
 
 func (s simpleMigration) Help() string {
 	return s.help
 }
 
+type reversibleMigration struct {
+	simpleMigration
+	inverse simpleMigration
+}
+
+func (r reversibleMigration) Inverse() Migration {
+	return r.inverse
+}
+
 type Migrations struct {
 	Migrations []Migration
 }
@@ -52,8 +110,10 @@ type Migrations struct {
 // allMigrations represents the migration catalog
 // the order of these migrations is important
 var allMigrations = []Migration{
-	newMigration(NoMigrations, "do nothing", func(_ *declcfg.DeclarativeConfig) error { return nil }),
-	newMigration("bundle-object-to-csv-metadata", `migrates bundles' "olm.bundle.object" to "olm.csv.metadata"`, bundleObjectToCSVMetadata),
+	newMigration(NoMigrations, "do nothing", func(_ *declcfg.DeclarativeConfig) ([]Change, error) { return nil, nil }),
+	newReversibleMigration("bundle-object-to-csv-metadata",
+		`migrates bundles' "olm.bundle.object" to "olm.csv.metadata"`, bundleObjectToCSVMetadata,
+		`regenerates bundles' "olm.bundle.object" from "olm.csv.metadata"`, csvMetadataToBundleObject),
 }
 
 func NewMigrations(name string) (*Migrations, error) {
@@ -78,6 +138,26 @@ func NewMigrations(name string) (*Migrations, error) {
 	return &Migrations{Migrations: keep}, nil
 }
 
+// NewTargetMigrations returns the migrations that bring a config to the
+// named level, whichever level it is at: the migrations up to and including
+// the level, followed by the inverses of the later migrations, latest first.
+// It fails if a later migration cannot be reversed.
+func NewTargetMigrations(name string) (*Migrations, error) {
+	up, err := NewMigrations(name)
+	if err != nil {
+		return nil, err
+	}
+	migrations := up.Migrations
+	for i := len(allMigrations) - 1; i >= len(up.Migrations); i-- {
+		r, ok := allMigrations[i].(ReversibleMigration)
+		if !ok {
+			return nil, fmt.Errorf("cannot migrate down to level %q: migration %q cannot be reversed", name, allMigrations[i].Token())
+		}
+		migrations = append(migrations, r.Inverse())
+	}
+	return &Migrations{Migrations: migrations}, nil
+}
+
 func HelpText() string {
 	var help strings.Builder
 	help.WriteString("\nThe migrator will run all migrations up to and including the selected level.\n\n")
@@ -91,6 +171,16 @@ func HelpText() string {
 		fmt.Fprintf(tabber, "  - %s\t: %s\n", migration.Token(), migration.Help())
 	}
 	tabber.Flush()
+
+	var reversible []string
+	for _, migration := range allMigrations {
+		if _, ok := migration.(ReversibleMigration); ok {
+			reversible = append(reversible, string(migration.Token()))
+		}
+	}
+	if len(reversible) > 0 {
+		fmt.Fprintf(&help, "\nMigrations that can be reversed: %s\n", strings.Join(reversible, ", "))
+	}
 	return help.String()
 }
 
@@ -102,3 +192,71 @@ func (m *Migrations) Migrate(config *declcfg.DeclarativeConfig) error {
 	}
 	return nil
 }
+
+// DryRun returns the changes that Migrate makes to config, in the order in
+// which the migrations make them, without changing config.
+func (m *Migrations) DryRun(config *declcfg.DeclarativeConfig) ([]Change, error) {
+	config = cloneConfig(config)
+	var changes []Change
+	for _, migration := range m.Migrations {
+		c, err := migration.Describe(config)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c...)
+		if err := migration.Migrate(config); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// WriteReport writes changes as a table.
+func WriteReport(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	tabber := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabber, "MIGRATION\tSCHEMA\tPACKAGE\tNAME\tCHANGE")
+	for _, c := range changes {
+		fmt.Fprintf(tabber, "%s\t%s\t%s\t%s\t%s\n", c.Migration, c.Schema, c.Package, c.Name, c.Message)
+	}
+	return tabber.Flush()
+}
+
+// cloneConfig returns a copy of config that migrations can change without
+// changing config. Property values are shared, so migrations must replace
+// properties rather than change their values in place.
+func cloneConfig(config *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+	out := &declcfg.DeclarativeConfig{
+		Packages:     slices.Clone(config.Packages),
+		Channels:     slices.Clone(config.Channels),
+		Bundles:      slices.Clone(config.Bundles),
+		Others:       slices.Clone(config.Others),
+		Deprecations: slices.Clone(config.Deprecations),
+	}
+	for i, p := range out.Packages {
+		if p.Icon != nil {
+			icon := *p.Icon
+			out.Packages[i].Icon = &icon
+		}
+		out.Packages[i].Properties = slices.Clone(p.Properties)
+	}
+	for i, c := range out.Channels {
+		out.Channels[i].Entries = slices.Clone(c.Entries)
+		for j, e := range out.Channels[i].Entries {
+			out.Channels[i].Entries[j].Skips = slices.Clone(e.Skips)
+		}
+		out.Channels[i].Properties = slices.Clone(c.Properties)
+	}
+	for i, b := range out.Bundles {
+		out.Bundles[i].Properties = slices.Clone(b.Properties)
+		out.Bundles[i].RelatedImages = slices.Clone(b.RelatedImages)
+		out.Bundles[i].Objects = slices.Clone(b.Objects)
+	}
+	for i, d := range out.Deprecations {
+		out.Deprecations[i].Entries = slices.Clone(d.Entries)
+	}
+	return out
+}
@@ -71,6 +71,127 @@ func TestMigrations(t *testing.T) {
 	}
 }
 
+func TestNewTargetMigrations(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          string
+		config         declcfg.DeclarativeConfig
+		expectedTokens []MigrationToken
+		expectedConfig declcfg.DeclarativeConfig
+		expectedErr    string
+	}{
+		{
+			name:           "Down",
+			level:          NoMigrations,
+			config:         csvMetadataCatalogFBC(),
+			expectedTokens: []MigrationToken{MigrationToken(NoMigrations), "bundle-object-to-csv-metadata-inverse"},
+			expectedConfig: bundleObjectCatalogFBC(),
+		},
+		{
+			name:           "Up",
+			level:          "bundle-object-to-csv-metadata",
+			config:         unmigratedCatalogFBC(),
+			expectedTokens: []MigrationToken{MigrationToken(NoMigrations), "bundle-object-to-csv-metadata"},
+			expectedConfig: csvMetadataCatalogFBC(),
+		},
+		{
+			name:        "UnknownLevel",
+			level:       "fizz",
+			expectedErr: `unknown migration level "fizz"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewTargetMigrations(test.level)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			var tokens []MigrationToken
+			for _, migration := range m.Migrations {
+				tokens = append(tokens, migration.Token())
+			}
+			require.Equal(t, test.expectedTokens, tokens)
+
+			require.NoError(t, m.Migrate(&test.config))
+			require.Empty(t, cmp.Diff(test.expectedConfig, test.config))
+
+			// Configs that are already at the level are not changed.
+			changes, err := m.DryRun(&test.expectedConfig)
+			require.NoError(t, err)
+			require.Empty(t, changes)
+		})
+	}
+}
+
+func TestCSVMetadataToBundleObject(t *testing.T) {
+	config := csvMetadataCatalogFBC()
+	config.Bundles[0].Objects = nil
+	config.Bundles[0].CsvJSON = ""
+
+	changes, err := csvMetadataToBundleObject(&config)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Contains(t, changes[0].Message, "for a CSV built from it")
+
+	props, err := property.Parse(config.Bundles[0].Properties)
+	require.NoError(t, err)
+	require.Empty(t, props.CSVMetadatas)
+	require.Len(t, props.BundleObjects, 1)
+
+	var csv v1alpha1.ClusterServiceVersion
+	require.NoError(t, json.Unmarshal(props.BundleObjects[0].Data, &csv))
+	require.Equal(t, "foo.v0.1.0", csv.Name)
+	require.Equal(t, "0.1.0", csv.Spec.Version.String())
+	require.Equal(t, "Foo Operator", csv.Spec.DisplayName)
+	require.Equal(t, v1alpha1.ClusterServiceVersionKind, csv.Kind)
+	require.Equal(t, config.Bundles[0].CsvJSON, string(props.BundleObjects[0].Data))
+}
+
+func TestDryRun(t *testing.T) {
+	m, err := NewMigrations(AllMigrations)
+	require.NoError(t, err)
+
+	config := unmigratedCatalogFBC()
+	changes, err := m.DryRun(&config)
+	require.NoError(t, err)
+	require.Empty(t, cmp.Diff(unmigratedCatalogFBC(), config), "dry run is not expected to change the config")
+	require.Equal(t, []Change{{
+		Migration: "bundle-object-to-csv-metadata",
+		Schema:    declcfg.SchemaBundle,
+		Package:   "foo",
+		Name:      "foo.v0.1.0",
+		Message:   `replaced 2 "olm.bundle.object" properties with an "olm.csv.metadata" property`,
+	}}, changes)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, changes))
+	require.Equal(t, `MIGRATION                      SCHEMA      PACKAGE  NAME        CHANGE
+bundle-object-to-csv-metadata  olm.bundle  foo      foo.v0.1.0  replaced 2 "olm.bundle.object" properties with an "olm.csv.metadata" property
+`, buf.String())
+
+	// Migrated configs have nothing left to change.
+	require.NoError(t, m.Migrate(&config))
+	changes, err = m.DryRun(&config)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestDryRunDown(t *testing.T) {
+	m, err := NewTargetMigrations(NoMigrations)
+	require.NoError(t, err)
+
+	// Changes made by the inverse of a migration are reported as such.
+	config := csvMetadataCatalogFBC()
+	changes, err := m.DryRun(&config)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, MigrationToken("bundle-object-to-csv-metadata-inverse"), changes[0].Migration)
+	require.Equal(t, "foo.v0.1.0", changes[0].Name)
+}
+
 func mustBuildCSVMetadata(r io.Reader) property.Property {
 	var csv v1alpha1.ClusterServiceVersion
 	if err := json.NewDecoder(r).Decode(&csv); err != nil {
@@ -139,3 +260,29 @@ func csvMetadataCatalogFBC() declcfg.DeclarativeConfig {
 		},
 	}
 }
+
+// bundleObjectCatalogFBC is csvMetadataCatalogFBC after the inverse of the
+// bundle-object-to-csv-metadata migration, which regenerates the bundle
+// objects from the bundle's objects.
+func bundleObjectCatalogFBC() declcfg.DeclarativeConfig {
+	return declcfg.DeclarativeConfig{
+		Bundles: []declcfg.Bundle{
+			{
+				Schema:  "olm.bundle",
+				Name:    "foo.v0.1.0",
+				Package: "foo",
+				Image:   "quay.io/openshift-community-operators/foo:v0.1.0",
+				Properties: []property.Property{
+					property.MustBuildGVK("test.foo", "v1", "Foo"),
+					property.MustBuildGVKRequired("test.bar", "v1alpha1", "Bar"),
+					property.MustBuildPackage("foo", "0.1.0"),
+					property.MustBuildPackageRequired("bar", "<0.1.0"),
+					property.MustBuildBundleObject(fooRawCsv),
+					property.MustBuildBundleObject(fooRawCrd),
+				},
+				Objects: []string{string(fooRawCsv), string(fooRawCrd)},
+				CsvJSON: string(fooRawCsv),
+			},
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// catalogRefMask allows the references that "opm render" accepts for
+// catalogs, but not bundles.
+const catalogRefMask = RefDCImage | RefDCDir | RefSqliteImage | RefSqliteFile
+
+// PrunePackages renders a catalog and keeps only the listed packages.
+type PrunePackages struct {
+	CatalogRef string
+	Packages   []string
+	Registry   image.Registry
+}
+
+func (p PrunePackages) Run(ctx context.Context) (*declcfg.DeclarativeConfig, error) {
+	if len(p.Packages) == 0 {
+		return nil, errors.New("no packages specified")
+	}
+	cfg, err := renderCatalog(ctx, p.CatalogRef, p.Registry)
+	if err != nil {
+		return nil, err
+	}
+	out, err := declcfg.PrunePackages(*cfg, p.Packages)
+	if err != nil {
+		return nil, err
+	}
+	return validatedConfig(out)
+}
+
+// PruneStranded renders a catalog and removes the channel entries, and
+// bundles, that are not reachable in their channels' upgrade graphs.
+type PruneStranded struct {
+	CatalogRef string
+	Registry   image.Registry
+}
+
+func (p PruneStranded) Run(ctx context.Context) (*declcfg.DeclarativeConfig, error) {
+	cfg, err := renderCatalog(ctx, p.CatalogRef, p.Registry)
+	if err != nil {
+		return nil, err
+	}
+	out, err := declcfg.PruneStranded(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	return validatedConfig(out)
+}
+
+// TruncateChannel renders a catalog and removes everything below a bundle
+// in the upgrade graph of a channel, or of all channels of the bundle's
+// package that contain it if Channel is empty.
+type TruncateChannel struct {
+	CatalogRef string
+	Package    string
+	Channel    string
+	Bundle     string
+	Registry   image.Registry
+}
+
+func (t TruncateChannel) Run(ctx context.Context) (*declcfg.DeclarativeConfig, error) {
+	if t.Package == "" {
+		return nil, errors.New("package name is unset")
+	}
+	if t.Bundle == "" {
+		return nil, errors.New("bundle name is unset")
+	}
+	cfg, err := renderCatalog(ctx, t.CatalogRef, t.Registry)
+	if err != nil {
+		return nil, err
+	}
+	out, err := declcfg.TruncateChannel(*cfg, t.Package, t.Bundle, t.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return validatedConfig(out)
+}
+
+func renderCatalog(ctx context.Context, ref string, reg image.Registry) (*declcfg.DeclarativeConfig, error) {
+	if ref == "" {
+		return nil, errors.New("catalog reference is unset")
+	}
+	r := Render{
+		Refs:           []string{ref},
+		Registry:       reg,
+		AllowedRefMask: catalogRefMask,
+	}
+	return r.Run(ctx)
+}
+
+// validatedConfig returns cfg if it is a valid catalog.
+func validatedConfig(cfg *declcfg.DeclarativeConfig) (*declcfg.DeclarativeConfig, error) {
+	if _, err := declcfg.ConvertToModel(*cfg); err != nil {
+		return nil, fmt.Errorf("invalid resulting catalog: %v", err)
+	}
+	return cfg, nil
+}
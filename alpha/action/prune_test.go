@@ -0,0 +1,64 @@
+package action
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+func TestCatalogPruneActions(t *testing.T) {
+	type spec struct {
+		name            string
+		run             func(context.Context) (*declcfg.DeclarativeConfig, error)
+		expectedBundles []string
+		expectedError   string
+	}
+	const catalogDir = "testdata/foo-index-v0.2.0-declcfg"
+	reg := &image.MockRegistry{}
+	specs := []spec{
+		{
+			name:            "PrunePackages",
+			run:             PrunePackages{CatalogRef: catalogDir, Packages: []string{"foo"}, Registry: reg}.Run,
+			expectedBundles: []string{"foo.v0.1.0", "foo.v0.2.0"},
+		},
+		{
+			name:          "PrunePackages/Missing",
+			run:           PrunePackages{CatalogRef: catalogDir, Packages: []string{"bar"}, Registry: reg}.Run,
+			expectedError: "packages not found in catalog: bar",
+		},
+		{
+			name:            "PruneStranded",
+			run:             PruneStranded{CatalogRef: catalogDir, Registry: reg}.Run,
+			expectedBundles: []string{"foo.v0.1.0", "foo.v0.2.0"},
+		},
+		{
+			name:            "TruncateChannel",
+			run:             TruncateChannel{CatalogRef: catalogDir, Package: "foo", Bundle: "foo.v0.2.0", Registry: reg}.Run,
+			expectedBundles: []string{"foo.v0.2.0"},
+		},
+		{
+			name:          "BundleRefNotAllowed",
+			run:           PruneStranded{CatalogRef: "testdata/foo-bundle-v0.2.0", Registry: reg}.Run,
+			expectedError: `render reference "testdata/foo-bundle-v0.2.0": cannot render bundle directory "testdata/foo-bundle-v0.2.0": not allowed`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg, err := s.run(context.Background())
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, b := range cfg.Bundles {
+				names = append(names, b.Name)
+			}
+			require.Equal(t, s.expectedBundles, names)
+		})
+	}
+}
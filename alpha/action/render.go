@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -15,10 +16,13 @@ import (
 
 	"github.com/h2non/filetype"
 	"github.com/h2non/filetype/matchers"
+	"github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/operator-framework/operator-registry/alpha/action/migrations"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/filter"
 	"github.com/operator-framework/operator-registry/alpha/property"
 	"github.com/operator-framework/operator-registry/pkg/containertools"
 	"github.com/operator-framework/operator-registry/pkg/image"
@@ -55,6 +59,14 @@ type Render struct {
 	AllowedRefMask   RefType
 	ImageRefTemplate *template.Template
 	Migrations       *migrations.Migrations
+	// Filter, if set, selects the subset of the rendered catalog to return.
+	Filter *filter.Configuration
+	// BundleCache, if set, is checked for images before they are pulled,
+	// and stores the bundles rendered from bundle images. Only bundles are
+	// stored, so an image whose digest is in the cache is a bundle image.
+	// It is only used if AllowedRefMask allows bundle images and Registry
+	// can resolve image digests.
+	BundleCache *BundleCache
 
 	skipSqliteDeprecationLog bool
 }
@@ -97,7 +109,11 @@ func (r Render) Run(ctx context.Context) (*declcfg.DeclarativeConfig, error) {
 		cfgs = append(cfgs, *cfg)
 	}
 
-	return combineConfigs(cfgs), nil
+	cfg := combineConfigs(cfgs)
+	if r.Filter != nil {
+		return filter.FilterCatalog(*cfg, *r.Filter)
+	}
+	return cfg, nil
 }
 
 func (r Render) renderReference(ctx context.Context, ref string) (*declcfg.DeclarativeConfig, error) {
@@ -144,6 +160,15 @@ func (r Render) renderReference(ctx context.Context, ref string) (*declcfg.Decla
 
 func (r Render) imageToDeclcfg(ctx context.Context, imageRef string) (*declcfg.DeclarativeConfig, error) {
 	ref := image.SimpleReference(imageRef)
+	// Any image may be a bundle image, so its digest is looked up in the
+	// bundle cache before it is pulled. For images that turn out not to be
+	// bundles, this costs a digest resolution, which is far cheaper than the
+	// pull that a cached bundle saves.
+	dgst := r.bundleCacheDigest(ctx, ref)
+	if b := r.cachedBundle(ref, dgst); b != nil {
+		return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{*b}}, nil
+	}
+
 	if err := r.Registry.Pull(ctx, ref); err != nil {
 		return nil, fmt.Errorf("failed to pull image %q: %v", ref, err)
 	}
@@ -196,6 +221,11 @@ func (r Render) imageToDeclcfg(ctx context.Context, imageRef string) (*declcfg.D
 		if err != nil {
 			return nil, err
 		}
+		if dgst != "" {
+			if err := r.BundleCache.Put(dgst, *bundle); err != nil {
+				logrus.WithError(err).Warnf("failed to write bundle cache for image %q", ref)
+			}
+		}
 		cfg = &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{*bundle}}
 	} else {
 		labelKeys := sets.StringKeySet(labels)
@@ -212,6 +242,65 @@ func (r Render) imageToDeclcfg(ctx context.Context, imageRef string) (*declcfg.D
 	return cfg, nil
 }
 
+// bundleCacheDigest returns the digest that ref is cached by, or an empty
+// digest if the bundle cache cannot be used for ref. Failing to resolve the
+// digest is not an error: the image is pulled instead, which reports a more
+// useful error if the image is unavailable.
+func (r Render) bundleCacheDigest(ctx context.Context, ref image.Reference) digest.Digest {
+	if r.BundleCache == nil || !r.AllowedRefMask.Allowed(RefBundleImage) {
+		return ""
+	}
+	resolver, ok := r.Registry.(image.Resolver)
+	if !ok {
+		return ""
+	}
+	dgst, err := resolver.Digest(ctx, ref)
+	if err != nil || dgst.Validate() != nil {
+		return ""
+	}
+	return dgst
+}
+
+// cachedBundle returns the bundle cached for dgst, with ref as its image, or
+// nil if there is none.
+func (r Render) cachedBundle(ref image.Reference, dgst digest.Digest) *declcfg.Bundle {
+	if dgst == "" {
+		return nil
+	}
+	b, ok, err := r.BundleCache.Get(dgst)
+	if err != nil {
+		logrus.WithError(err).Warnf("failed to read bundle cache for image %q", ref)
+	}
+	if !ok {
+		return nil
+	}
+	b.RelatedImages = replaceBundleRelatedImage(b.RelatedImages, b.Image, ref.String())
+	b.Image = ref.String()
+	return b
+}
+
+// replaceBundleRelatedImage replaces the related image that getRelatedImages
+// added for a bundle rendered from oldImage with one for newImage, as if the
+// bundle had been rendered from newImage. The entry is dropped if newImage is
+// already a related image.
+func replaceBundleRelatedImage(relatedImages []declcfg.RelatedImage, oldImage, newImage string) []declcfg.RelatedImage {
+	if oldImage == newImage {
+		return relatedImages
+	}
+	hasNew := slices.ContainsFunc(relatedImages, func(ri declcfg.RelatedImage) bool { return ri.Image == newImage })
+	out := make([]declcfg.RelatedImage, 0, len(relatedImages))
+	for _, ri := range relatedImages {
+		if ri.Name == "" && ri.Image == oldImage {
+			if hasNew {
+				continue
+			}
+			ri.Image = newImage
+		}
+		out = append(out, ri)
+	}
+	return out
+}
+
 // checkDBFile returns an error if ref is not an sqlite3 database.
 func checkDBFile(ref string) error {
 	typ, err := filetype.MatchFile(ref)
@@ -0,0 +1,113 @@
+package action
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+// bundleCacheVersion is part of the path of cached bundles. Bump it when a
+// change to bundle rendering makes previously cached bundles incorrect.
+const bundleCacheVersion = "v1"
+
+// BundleCache stores rendered bundles on disk, keyed by the manifest digest of
+// the bundle image they were rendered from. Since images are immutable by
+// digest, entries never need to be invalidated.
+type BundleCache struct {
+	dir string
+}
+
+// NewBundleCache returns a BundleCache that stores bundles in dir.
+func NewBundleCache(dir string) *BundleCache {
+	return &BundleCache{dir: dir}
+}
+
+// DefaultBundleCacheDir returns the directory that rendered bundles are cached
+// in by default: "renders" in $OLM_CACHE_DIR, next to the image cache, or in
+// the user cache directory if OLM_CACHE_DIR is not set.
+func DefaultBundleCacheDir() (string, error) {
+	if dir := os.Getenv("OLM_CACHE_DIR"); dir != "" {
+		return filepath.Join(dir, "renders"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "opm", "renders"), nil
+}
+
+// bundleCacheEntry holds the fields of a bundle that are not serialized
+// with it.
+type bundleCacheEntry struct {
+	Bundle  declcfg.Bundle `json:"bundle"`
+	CsvJSON string         `json:"csvJson,omitempty"`
+	Objects []string       `json:"objects,omitempty"`
+}
+
+func (c *BundleCache) path(dgst digest.Digest) string {
+	return filepath.Join(c.dir, bundleCacheVersion, dgst.Algorithm().String(), dgst.Encoded()+".json")
+}
+
+// Get returns the bundle rendered from the image with the given digest, or
+// false if it is not cached.
+func (c *BundleCache) Get(dgst digest.Digest) (*declcfg.Bundle, bool, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(c.path(dgst))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var entry bundleCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, fmt.Errorf("parse cached bundle %s: %v", dgst, err)
+	}
+	b := entry.Bundle
+	b.CsvJSON = entry.CsvJSON
+	b.Objects = entry.Objects
+	return &b, true, nil
+}
+
+// Put stores the bundle rendered from the image with the given digest.
+func (c *BundleCache) Put(dgst digest.Digest, b declcfg.Bundle) error {
+	if err := dgst.Validate(); err != nil {
+		return err
+	}
+	// Property values are stored as is, so that a cached bundle is identical
+	// to a rendered one.
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(bundleCacheEntry{Bundle: b, CsvJSON: b.CsvJSON, Objects: b.Objects}); err != nil {
+		return err
+	}
+	path := c.path(dgst)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that concurrent renders never read
+	// a partially written entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data.Bytes()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,109 @@
+package action_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/lib/bundle"
+)
+
+func TestBundleCache(t *testing.T) {
+	cache := action.NewBundleCache(t.TempDir())
+	dgst := digest.FromString("foo.v0.1.0")
+
+	_, ok, err := cache.Get(dgst)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	b := declcfg.Bundle{
+		Schema:     declcfg.SchemaBundle,
+		Name:       "foo.v0.1.0",
+		Package:    "foo",
+		Image:      "test.registry/foo:v0.1.0",
+		Properties: []property.Property{property.MustBuildPackageRequired("bar", "<0.1.0")},
+		CsvJSON:    `{"kind":"ClusterServiceVersion"}`,
+		Objects:    []string{`{"kind":"ClusterServiceVersion"}`},
+	}
+	require.NoError(t, cache.Put(dgst, b))
+	cached, ok, err := cache.Get(dgst)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, b, *cached)
+
+	require.Error(t, cache.Put("sha256:bad", b))
+	_, _, err = cache.Get("sha256:bad")
+	require.Error(t, err)
+}
+
+func TestRenderBundleCache(t *testing.T) {
+	ctx := context.Background()
+	tagRef := image.SimpleReference("test.registry/foo-operator/foo-bundle:v0.1.0")
+	otherTagRef := image.SimpleReference("test.registry/foo-operator/foo-bundle:latest")
+	dgst := digest.FromString("foo-bundle:v0.1.0")
+	cache := action.NewBundleCache(t.TempDir())
+
+	reg, err := newRegistry(t)
+	require.NoError(t, err)
+	reg.(*image.MockRegistry).RemoteImages[tagRef].Digest = dgst
+	reg.(*image.MockRegistry).RemoteImages[otherTagRef] = reg.(*image.MockRegistry).RemoteImages[tagRef]
+
+	expected, err := action.Render{Refs: []string{tagRef.String()}, Registry: reg}.Run(ctx)
+	require.NoError(t, err)
+	expectedOther, err := action.Render{Refs: []string{otherTagRef.String()}, Registry: reg}.Run(ctx)
+	require.NoError(t, err)
+	require.Contains(t, expectedOther.Bundles[0].RelatedImages, declcfg.RelatedImage{Image: otherTagRef.String()})
+	actual, err := action.Render{Refs: []string{tagRef.String()}, Registry: reg, BundleCache: cache}.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// An image with the same digest is rendered from the cache, without
+	// unpacking it, and keeps the reference it was rendered from.
+	emptyReg := &image.MockRegistry{RemoteImages: map[image.Reference]*image.MockImage{
+		tagRef:      {FS: fstest.MapFS{}, Digest: dgst, Labels: map[string]string{bundle.PackageLabel: "foo"}},
+		otherTagRef: {FS: fstest.MapFS{}, Digest: dgst},
+	}}
+	actual, err = action.Render{Refs: []string{tagRef.String()}, Registry: emptyReg, BundleCache: cache}.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// Cached images are rendered without reading their labels, as if they
+	// had been rendered from the new reference, with the default ref mask as well as one that only allows bundle images.
+	for _, mask := range []action.RefType{action.RefAll, action.RefBundleImage} {
+		actual, err = action.Render{Refs: []string{otherTagRef.String()}, Registry: emptyReg, BundleCache: cache, AllowedRefMask: mask}.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectedOther, actual)
+	}
+
+	// The cache is not used when it is not set, or when bundle images are
+	// not allowed.
+	_, err = action.Render{Refs: []string{tagRef.String()}, Registry: emptyReg}.Run(ctx)
+	require.Error(t, err)
+	_, err = action.Render{Refs: []string{tagRef.String()}, Registry: emptyReg, BundleCache: cache, AllowedRefMask: action.RefDCImage}.Run(ctx)
+	require.ErrorIs(t, err, action.ErrNotAllowed)
+}
+
+func TestRenderBundleCacheSkipsCatalogImages(t *testing.T) {
+	reg, err := newRegistry(t)
+	require.NoError(t, err)
+	cacheDir := t.TempDir()
+
+	// Catalog images are looked up in the cache, but are not stored in it.
+	_, err = action.Render{
+		Refs:        []string{"test.registry/foo-operator/foo-index-declcfg:v0.2.0"},
+		Registry:    reg,
+		BundleCache: action.NewBundleCache(cacheDir),
+	}.Run(context.Background())
+	require.NoError(t, err)
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -41,6 +41,9 @@ func (m fauxMigration) Help() string {
 func (m fauxMigration) Migrate(config *declcfg.DeclarativeConfig) error {
 	return m.migrate(config)
 }
+func (m fauxMigration) Describe(_ *declcfg.DeclarativeConfig) ([]migrations.Change, error) {
+	return nil, nil
+}
 
 func TestRender(t *testing.T) {
 	type spec struct {
@@ -0,0 +1,107 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// UpgradePath computes the shortest upgrade path from a bundle to another
+// bundle (by default, the channel head) within a single channel.
+type UpgradePath struct {
+	Ref         string
+	PackageName string
+	// ChannelName defaults to the package's default channel.
+	ChannelName string
+	From        string
+	// To defaults to the channel head.
+	To       string
+	Registry image.Registry
+}
+
+type UpgradePathResult struct {
+	Package   string       `json:"package"`
+	Channel   string       `json:"channel"`
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Path      []model.Edge `json:"path"`
+	Reachable []string     `json:"reachable"`
+}
+
+func (u UpgradePath) Run(ctx context.Context) (*UpgradePathResult, error) {
+	r := Render{
+		Refs:     []string{u.Ref},
+		Registry: u.Registry,
+	}
+	cfg, err := r.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := declcfg.ConvertToModel(*cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, ok := m[u.PackageName]
+	if !ok {
+		return nil, fmt.Errorf("package %q not found", u.PackageName)
+	}
+	ch := pkg.DefaultChannel
+	if u.ChannelName != "" {
+		if ch, ok = pkg.Channels[u.ChannelName]; !ok {
+			return nil, fmt.Errorf("channel %q not found in package %q", u.ChannelName, u.PackageName)
+		}
+	}
+
+	g := ch.Graph()
+	to := u.To
+	if to == "" {
+		head, err := ch.Head()
+		if err != nil {
+			return nil, fmt.Errorf("find head of channel %q: %v", ch.Name, err)
+		}
+		to = head.Name
+	}
+	path, err := g.ShortestPath(u.From, to)
+	if err != nil {
+		return nil, err
+	}
+	reachable, err := g.Reachable(u.From)
+	if err != nil {
+		return nil, err
+	}
+	return &UpgradePathResult{
+		Package:   pkg.Name,
+		Channel:   ch.Name,
+		From:      u.From,
+		To:        to,
+		Path:      path,
+		Reachable: reachable,
+	}, nil
+}
+
+func (r *UpgradePathResult) WriteColumns(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "STEP\tFROM\tTO\tVIA"); err != nil {
+		return err
+	}
+	for i, e := range r.Path {
+		if _, err := fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, e.From, e.To, e.Type); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func (r *UpgradePathResult) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
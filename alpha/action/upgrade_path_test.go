@@ -0,0 +1,62 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradePath(t *testing.T) {
+	type spec struct {
+		name        string
+		upgradePath UpgradePath
+		expectedOut string
+		expectedErr string
+	}
+	specs := []spec{
+		{
+			name:        "Success/DefaultChannelHead",
+			upgradePath: UpgradePath{Ref: "testdata/list-index", PackageName: "foo", From: "foo.v0.1.0"},
+			expectedOut: `STEP  FROM        TO          VIA
+1     foo.v0.1.0  foo.v0.2.0  replaces
+`,
+		},
+		{
+			name:        "Success/AlreadyAtHead",
+			upgradePath: UpgradePath{Ref: "testdata/list-index", PackageName: "foo", From: "foo.v0.2.0"},
+			expectedOut: "STEP  FROM  TO  VIA\n",
+		},
+		{
+			name:        "Error/UnknownChannel",
+			upgradePath: UpgradePath{Ref: "testdata/list-index", PackageName: "foo", ChannelName: "alpha", From: "foo.v0.1.0"},
+			expectedErr: `channel "alpha" not found in package "foo"`,
+		},
+		{
+			name:        "Error/NotInChannel",
+			upgradePath: UpgradePath{Ref: "testdata/list-index", PackageName: "foo", ChannelName: "stable", From: "foo.v0.1.0"},
+			expectedErr: `no upgrade path from "foo.v0.1.0" to "foo.v0.2.0": bundle "foo.v0.1.0" is not in channel "stable"`,
+		},
+		{
+			name:        "Error/UnknownPackage",
+			upgradePath: UpgradePath{Ref: "testdata/list-index", PackageName: "baz", From: "baz.v0.1.0"},
+			expectedErr: `package "baz" not found`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			res, err := s.upgradePath.Run(context.Background())
+			if s.expectedErr != "" {
+				require.Nil(t, res)
+				require.EqualError(t, err, s.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, res.WriteColumns(buf))
+			require.Equal(t, s.expectedOut, buf.String())
+		})
+	}
+}
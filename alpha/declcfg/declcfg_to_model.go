@@ -258,6 +258,21 @@ func ConvertToModel(cfg DeclarativeConfig) (model.Model, error) {
 		}
 	}
 
+	// Blobs of custom schemas are kept with their package. Blobs that do not
+	// belong to a package in the config have no place in the model; they are
+	// reported by ValidateOthers.
+	for _, o := range cfg.Others {
+		mpkg, ok := mpkgs[o.Package]
+		if !ok {
+			continue
+		}
+		mpkg.Others = append(mpkg.Others, model.Other{
+			Schema: o.Schema,
+			Name:   o.Name,
+			Blob:   o.Blob,
+		})
+	}
+
 	if err := mpkgs.Validate(); err != nil {
 		return nil, err
 	}
@@ -265,6 +280,23 @@ func ConvertToModel(cfg DeclarativeConfig) (model.Model, error) {
 	return mpkgs, nil
 }
 
+// ValidateOthers checks the blobs of custom schemas of cfg, which was
+// converted to m by ConvertToModel: each blob that names a package must
+// belong to a package of cfg, and must pass the validator registered for its
+// schema with model.RegisterSchema.
+func ValidateOthers(cfg DeclarativeConfig, m model.Model) error {
+	for _, o := range cfg.Others {
+		if _, ok := m[o.Package]; o.Package == "" || ok {
+			continue
+		}
+		if o.Name == "" {
+			return fmt.Errorf("unknown package %q for %q blob", o.Package, o.Schema)
+		}
+		return fmt.Errorf("unknown package %q for %q blob %q", o.Package, o.Schema, o.Name)
+	}
+	return m.ValidateOthers()
+}
+
 func relatedImagesToModelRelatedImages(in []RelatedImage) []model.RelatedImage {
 	// nolint:prealloc
 	var out []model.RelatedImage
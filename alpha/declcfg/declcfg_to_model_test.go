@@ -2,6 +2,7 @@ package declcfg
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -12,6 +13,24 @@ import (
 	"github.com/operator-framework/operator-registry/alpha/property"
 )
 
+// testSupportTier is the type of the blobs of the test.support-tier schema,
+// which is registered for all tests in the package.
+type testSupportTier struct {
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+}
+
+func (t *testSupportTier) Validate() error {
+	if t.Tier == "" {
+		return errors.New("tier is required")
+	}
+	return nil
+}
+
+func init() {
+	model.RegisterSchema("test.support-tier", model.TypeValidator[testSupportTier]())
+}
+
 func TestConvertToModel(t *testing.T) {
 	type spec struct {
 		name      string
@@ -615,6 +634,83 @@ func TestConvertToModelBundle(t *testing.T) {
 	assert.Equal(t, semver.MustParse("0.1.0"), b.Version)
 }
 
+func TestConvertToModelOthers(t *testing.T) {
+	cfg := DeclarativeConfig{
+		Packages: []Package{newTestPackage("foo", "alpha", svgSmallCircle)},
+		Channels: []Channel{newTestChannel("foo", "alpha", ChannelEntry{Name: testBundleName("foo", "0.1.0")})},
+		Bundles:  []Bundle{newTestBundle("foo", "0.1.0")},
+		Others: []Meta{
+			{Schema: "test.support-tier", Package: "foo", Name: "none", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"foo","name":"none"}`)},
+			{Schema: "test.support-tier", Package: "bar", Name: "premium", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"bar","name":"premium","tier":"premium"}`)},
+			{Schema: "test.unregistered", Blob: json.RawMessage(`{"schema":"test.unregistered","anything":"goes"}`)},
+		},
+	}
+	m, err := ConvertToModel(cfg)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	assert.Equal(t, []model.Other{{Schema: "test.support-tier", Name: "none", Blob: cfg.Others[0].Blob}}, m["foo"].Others)
+}
+
+func TestValidateOthers(t *testing.T) {
+	type spec struct {
+		name      string
+		cfg       DeclarativeConfig
+		assertion require.ErrorAssertionFunc
+	}
+	specs := []spec{
+		{
+			name:      "Error/UnknownPackage",
+			assertion: hasError(`unknown package "bar" for "test.support-tier" blob "premium"`),
+			cfg: DeclarativeConfig{
+				Packages: []Package{newTestPackage("foo", "alpha", svgSmallCircle)},
+				Channels: []Channel{newTestChannel("foo", "alpha", ChannelEntry{Name: testBundleName("foo", "0.1.0")})},
+				Bundles:  []Bundle{newTestBundle("foo", "0.1.0")},
+				Others: []Meta{
+					{Schema: "test.support-tier", Package: "bar", Name: "premium", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"bar","name":"premium","tier":"premium"}`)},
+				},
+			},
+		},
+		{
+			name: "Error/Invalid",
+			assertion: hasError(`invalid index:
+└── invalid package "foo":
+    ├── invalid "test.support-tier" blob "premium":
+    │   └── json: unknown field "level"
+    └── invalid "test.support-tier" blob "none":
+        └── tier is required`),
+			cfg: DeclarativeConfig{
+				Packages: []Package{newTestPackage("foo", "alpha", svgSmallCircle)},
+				Channels: []Channel{newTestChannel("foo", "alpha", ChannelEntry{Name: testBundleName("foo", "0.1.0")})},
+				Bundles:  []Bundle{newTestBundle("foo", "0.1.0")},
+				Others: []Meta{
+					{Schema: "test.support-tier", Package: "foo", Name: "premium", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"foo","name":"premium","level":"premium"}`)},
+					{Schema: "test.support-tier", Package: "foo", Name: "none", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"foo","name":"none"}`)},
+				},
+			},
+		},
+		{
+			name:      "Success",
+			assertion: require.NoError,
+			cfg: DeclarativeConfig{
+				Packages: []Package{newTestPackage("foo", "alpha", svgSmallCircle)},
+				Channels: []Channel{newTestChannel("foo", "alpha", ChannelEntry{Name: testBundleName("foo", "0.1.0")})},
+				Bundles:  []Bundle{newTestBundle("foo", "0.1.0")},
+				Others: []Meta{
+					{Schema: "test.support-tier", Package: "foo", Name: "premium", Blob: json.RawMessage(`{"schema":"test.support-tier","package":"foo","name":"premium","tier":"premium"}`)},
+					{Schema: "test.unregistered", Blob: json.RawMessage(`{"schema":"test.unregistered","anything":"goes"}`)},
+				},
+			},
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			m, err := ConvertToModel(s.cfg)
+			require.NoError(t, err)
+			s.assertion(t, ValidateOthers(s.cfg, m))
+		})
+	}
+}
+
 func TestConvertToModelRoundtrip(t *testing.T) {
 	expected := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: false}) // TODO: turn on deprecation when we have model-->declcfg conversion
 
@@ -627,7 +723,15 @@ func TestConvertToModelRoundtrip(t *testing.T) {
 
 	assert.Equal(t, expected.Packages, actual.Packages)
 	assert.Equal(t, expected.Bundles, actual.Bundles)
-	assert.Empty(t, actual.Others, "expected unrecognized schemas not to make the roundtrip")
+
+	// Only the blobs of custom schemas that belong to a package make the roundtrip.
+	var expectedOthers []Meta
+	for _, o := range expected.Others {
+		if o.Package != "" {
+			expectedOthers = append(expectedOthers, o)
+		}
+	}
+	assert.Equal(t, expectedOthers, actual.Others)
 }
 
 func hasError(expectedError string) require.ErrorAssertionFunc {
@@ -0,0 +1,674 @@
+package declcfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+// ChangeType describes how an object differs between two declarative configs.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// DiffResult is the structural difference between two declarative configs.
+// Objects are matched by identity (package name, channel name, bundle name,
+// etc.) rather than by position, so reordering objects, properties, or JSON
+// keys does not produce a difference.
+type DiffResult struct {
+	Packages []PackageDiff `json:"packages,omitempty"`
+	// Others contains differences in custom schema blobs that are not
+	// associated with any package.
+	Others []MetaDiff `json:"others,omitempty"`
+}
+
+type PackageDiff struct {
+	Name         string            `json:"name"`
+	Change       ChangeType        `json:"change"`
+	Fields       []FieldChange     `json:"fields,omitempty"`
+	Channels     []ChannelDiff     `json:"channels,omitempty"`
+	Bundles      []BundleDiff      `json:"bundles,omitempty"`
+	Deprecations []DeprecationDiff `json:"deprecations,omitempty"`
+	Others       []MetaDiff        `json:"others,omitempty"`
+}
+
+type ChannelDiff struct {
+	Name    string             `json:"name"`
+	Change  ChangeType         `json:"change"`
+	Fields  []FieldChange      `json:"fields,omitempty"`
+	Entries []ChannelEntryDiff `json:"entries,omitempty"`
+}
+
+type ChannelEntryDiff struct {
+	Name   string        `json:"name"`
+	Change ChangeType    `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+type BundleDiff struct {
+	Name   string        `json:"name"`
+	Change ChangeType    `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+type DeprecationDiff struct {
+	Reference  PackageScopedReference `json:"reference"`
+	Change     ChangeType             `json:"change"`
+	OldMessage string                 `json:"oldMessage,omitempty"`
+	NewMessage string                 `json:"newMessage,omitempty"`
+}
+
+type MetaDiff struct {
+	Schema string          `json:"schema"`
+	Name   string          `json:"name,omitempty"`
+	Change ChangeType      `json:"change"`
+	Old    json.RawMessage `json:"old,omitempty"`
+	New    json.RawMessage `json:"new,omitempty"`
+}
+
+// FieldChange records the old and new values of a single field of a changed object.
+// Property changes are grouped by property type, with field names of the form
+// "properties[<type>]".
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// Empty returns true if the result contains no differences.
+func (r DiffResult) Empty() bool {
+	return len(r.Packages) == 0 && len(r.Others) == 0
+}
+
+// Diff computes the structural difference between oldCfg and newCfg.
+func Diff(oldCfg, newCfg DeclarativeConfig) (*DiffResult, error) {
+	oldPkgs, oldRoot, err := indexForDiff(oldCfg)
+	if err != nil {
+		return nil, fmt.Errorf("old config: %v", err)
+	}
+	newPkgs, newRoot, err := indexForDiff(newCfg)
+	if err != nil {
+		return nil, fmt.Errorf("new config: %v", err)
+	}
+
+	result := &DiffResult{}
+	for _, name := range sortedUnion(oldPkgs, newPkgs) {
+		pd, err := diffPackage(name, oldPkgs[name], newPkgs[name])
+		if err != nil {
+			return nil, fmt.Errorf("package %q: %v", name, err)
+		}
+		if pd != nil {
+			result.Packages = append(result.Packages, *pd)
+		}
+	}
+	others, err := diffMetas(oldRoot, newRoot)
+	if err != nil {
+		return nil, err
+	}
+	result.Others = others
+	return result, nil
+}
+
+// diffPackageIndex holds all objects belonging to a single package, keyed by identity.
+type diffPackageIndex struct {
+	pkg          *Package
+	channels     map[string]*Channel
+	bundles      map[string]*Bundle
+	deprecations map[PackageScopedReference]string
+	others       []Meta
+}
+
+func indexForDiff(cfg DeclarativeConfig) (map[string]*diffPackageIndex, []Meta, error) {
+	pkgs := map[string]*diffPackageIndex{}
+	get := func(name string) *diffPackageIndex {
+		if _, ok := pkgs[name]; !ok {
+			pkgs[name] = &diffPackageIndex{
+				channels:     map[string]*Channel{},
+				bundles:      map[string]*Bundle{},
+				deprecations: map[PackageScopedReference]string{},
+			}
+		}
+		return pkgs[name]
+	}
+
+	for i := range cfg.Packages {
+		p := &cfg.Packages[i]
+		idx := get(p.Name)
+		if idx.pkg != nil {
+			return nil, nil, fmt.Errorf("duplicate package %q", p.Name)
+		}
+		idx.pkg = p
+	}
+	for i := range cfg.Channels {
+		c := &cfg.Channels[i]
+		idx := get(c.Package)
+		if _, ok := idx.channels[c.Name]; ok {
+			return nil, nil, fmt.Errorf("duplicate channel %q in package %q", c.Name, c.Package)
+		}
+		idx.channels[c.Name] = c
+	}
+	for i := range cfg.Bundles {
+		b := &cfg.Bundles[i]
+		idx := get(b.Package)
+		if _, ok := idx.bundles[b.Name]; ok {
+			return nil, nil, fmt.Errorf("duplicate bundle %q in package %q", b.Name, b.Package)
+		}
+		idx.bundles[b.Name] = b
+	}
+	for _, d := range cfg.Deprecations {
+		idx := get(d.Package)
+		for _, e := range d.Entries {
+			if _, ok := idx.deprecations[e.Reference]; ok {
+				return nil, nil, fmt.Errorf("duplicate deprecation entry for %s %q in package %q", e.Reference.Schema, e.Reference.Name, d.Package)
+			}
+			idx.deprecations[e.Reference] = e.Message
+		}
+	}
+	var rootOthers []Meta
+	for _, o := range cfg.Others {
+		if o.Package == "" {
+			rootOthers = append(rootOthers, o)
+			continue
+		}
+		idx := get(o.Package)
+		idx.others = append(idx.others, o)
+	}
+	return pkgs, rootOthers, nil
+}
+
+func diffPackage(name string, oldIdx, newIdx *diffPackageIndex) (*PackageDiff, error) {
+	pd := &PackageDiff{Name: name, Change: ChangeChanged}
+	switch {
+	case oldIdx == nil:
+		pd.Change = ChangeAdded
+		oldIdx = &diffPackageIndex{}
+	case newIdx == nil:
+		pd.Change = ChangeRemoved
+		newIdx = &diffPackageIndex{}
+	}
+
+	if pd.Change == ChangeChanged {
+		fields, err := diffPackageFields(oldIdx.pkg, newIdx.pkg)
+		if err != nil {
+			return nil, err
+		}
+		pd.Fields = fields
+	}
+
+	for _, chName := range sortedUnion(oldIdx.channels, newIdx.channels) {
+		cd, err := diffChannel(chName, oldIdx.channels[chName], newIdx.channels[chName])
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %v", chName, err)
+		}
+		if cd != nil {
+			pd.Channels = append(pd.Channels, *cd)
+		}
+	}
+
+	for _, bName := range sortedUnion(oldIdx.bundles, newIdx.bundles) {
+		bd, err := diffBundle(bName, oldIdx.bundles[bName], newIdx.bundles[bName])
+		if err != nil {
+			return nil, fmt.Errorf("bundle %q: %v", bName, err)
+		}
+		if bd != nil {
+			pd.Bundles = append(pd.Bundles, *bd)
+		}
+	}
+
+	refs := sets.KeySet(oldIdx.deprecations).Union(sets.KeySet(newIdx.deprecations)).UnsortedList()
+	slices.SortFunc(refs, func(a, b PackageScopedReference) int {
+		if c := strings.Compare(a.Schema, b.Schema); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, ref := range refs {
+		oldMsg, inOld := oldIdx.deprecations[ref]
+		newMsg, inNew := newIdx.deprecations[ref]
+		switch {
+		case !inOld:
+			pd.Deprecations = append(pd.Deprecations, DeprecationDiff{Reference: ref, Change: ChangeAdded, NewMessage: newMsg})
+		case !inNew:
+			pd.Deprecations = append(pd.Deprecations, DeprecationDiff{Reference: ref, Change: ChangeRemoved, OldMessage: oldMsg})
+		case oldMsg != newMsg:
+			pd.Deprecations = append(pd.Deprecations, DeprecationDiff{Reference: ref, Change: ChangeChanged, OldMessage: oldMsg, NewMessage: newMsg})
+		}
+	}
+
+	others, err := diffMetas(oldIdx.others, newIdx.others)
+	if err != nil {
+		return nil, err
+	}
+	pd.Others = others
+
+	if pd.Change == ChangeChanged && len(pd.Fields) == 0 && len(pd.Channels) == 0 && len(pd.Bundles) == 0 && len(pd.Deprecations) == 0 && len(pd.Others) == 0 {
+		return nil, nil
+	}
+	return pd, nil
+}
+
+func diffPackageFields(oldPkg, newPkg *Package) ([]FieldChange, error) {
+	// A package may be referenced by channels or bundles without having an
+	// olm.package blob; treat a missing blob as an empty one.
+	if oldPkg == nil {
+		oldPkg = &Package{}
+	}
+	if newPkg == nil {
+		newPkg = &Package{}
+	}
+
+	var fields []FieldChange
+	fields = appendStringChange(fields, "defaultChannel", oldPkg.DefaultChannel, newPkg.DefaultChannel)
+	fields = appendStringChange(fields, "description", oldPkg.Description, newPkg.Description)
+	if !iconsEqual(oldPkg.Icon, newPkg.Icon) {
+		fields = append(fields, FieldChange{Field: "icon", Old: iconSummary(oldPkg.Icon), New: iconSummary(newPkg.Icon)})
+	}
+	propFields, err := diffProperties(oldPkg.Properties, newPkg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	return append(fields, propFields...), nil
+}
+
+func diffChannel(name string, oldCh, newCh *Channel) (*ChannelDiff, error) {
+	cd := &ChannelDiff{Name: name, Change: ChangeChanged}
+	switch {
+	case oldCh == nil:
+		cd.Change = ChangeAdded
+		oldCh = &Channel{}
+	case newCh == nil:
+		cd.Change = ChangeRemoved
+		newCh = &Channel{}
+	}
+
+	if cd.Change == ChangeChanged {
+		fields, err := diffProperties(oldCh.Properties, newCh.Properties)
+		if err != nil {
+			return nil, err
+		}
+		cd.Fields = fields
+	}
+
+	oldEntries := entriesByName(oldCh.Entries)
+	newEntries := entriesByName(newCh.Entries)
+	for _, eName := range sortedUnion(oldEntries, newEntries) {
+		oldEntry, inOld := oldEntries[eName]
+		newEntry, inNew := newEntries[eName]
+		switch {
+		case !inOld:
+			cd.Entries = append(cd.Entries, ChannelEntryDiff{Name: eName, Change: ChangeAdded, Fields: entryFields(ChannelEntry{}, newEntry)})
+		case !inNew:
+			cd.Entries = append(cd.Entries, ChannelEntryDiff{Name: eName, Change: ChangeRemoved, Fields: entryFields(oldEntry, ChannelEntry{})})
+		default:
+			if fields := entryFields(oldEntry, newEntry); len(fields) > 0 {
+				cd.Entries = append(cd.Entries, ChannelEntryDiff{Name: eName, Change: ChangeChanged, Fields: fields})
+			}
+		}
+	}
+
+	if cd.Change == ChangeChanged && len(cd.Fields) == 0 && len(cd.Entries) == 0 {
+		return nil, nil
+	}
+	return cd, nil
+}
+
+func entriesByName(entries []ChannelEntry) map[string]ChannelEntry {
+	out := make(map[string]ChannelEntry, len(entries))
+	for _, e := range entries {
+		out[e.Name] = e
+	}
+	return out
+}
+
+func entryFields(oldEntry, newEntry ChannelEntry) []FieldChange {
+	var fields []FieldChange
+	fields = appendStringChange(fields, "replaces", oldEntry.Replaces, newEntry.Replaces)
+	oldSkips := sets.List(sets.New(oldEntry.Skips...))
+	newSkips := sets.List(sets.New(newEntry.Skips...))
+	if !slices.Equal(oldSkips, newSkips) {
+		fields = append(fields, FieldChange{Field: "skips", Old: nilIfEmpty(oldSkips), New: nilIfEmpty(newSkips)})
+	}
+	fields = appendStringChange(fields, "skipRange", oldEntry.SkipRange, newEntry.SkipRange)
+	return fields
+}
+
+func diffBundle(name string, oldB, newB *Bundle) (*BundleDiff, error) {
+	switch {
+	case oldB == nil:
+		return &BundleDiff{Name: name, Change: ChangeAdded}, nil
+	case newB == nil:
+		return &BundleDiff{Name: name, Change: ChangeRemoved}, nil
+	}
+
+	var fields []FieldChange
+	fields = appendStringChange(fields, "image", oldB.Image, newB.Image)
+
+	oldRelated := relatedImageStrings(oldB.RelatedImages)
+	newRelated := relatedImageStrings(newB.RelatedImages)
+	if !slices.Equal(oldRelated, newRelated) {
+		fields = append(fields, FieldChange{Field: "relatedImages", Old: nilIfEmpty(oldRelated), New: nilIfEmpty(newRelated)})
+	}
+
+	propFields, err := diffProperties(oldB.Properties, newB.Properties)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, propFields...)
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &BundleDiff{Name: name, Change: ChangeChanged, Fields: fields}, nil
+}
+
+func relatedImageStrings(images []RelatedImage) []string {
+	s := sets.New[string]()
+	for _, ri := range images {
+		if ri.Name == "" {
+			s.Insert(ri.Image)
+			continue
+		}
+		s.Insert(fmt.Sprintf("%s=%s", ri.Name, ri.Image))
+	}
+	return sets.List(s)
+}
+
+// diffProperties compares two property lists as sets, independent of order
+// and JSON formatting, grouping any differences by property type.
+func diffProperties(oldProps, newProps []property.Property) ([]FieldChange, error) {
+	oldByType, err := canonicalPropertiesByType(oldProps)
+	if err != nil {
+		return nil, err
+	}
+	newByType, err := canonicalPropertiesByType(newProps)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []FieldChange
+	for _, typ := range sortedUnion(oldByType, newByType) {
+		oldVals, newVals := oldByType[typ], newByType[typ]
+		if slices.Equal(oldVals, newVals) {
+			continue
+		}
+		fields = append(fields, FieldChange{
+			Field: fmt.Sprintf("properties[%s]", typ),
+			Old:   rawMessages(oldVals),
+			New:   rawMessages(newVals),
+		})
+	}
+	return fields, nil
+}
+
+func canonicalPropertiesByType(props []property.Property) (map[string][]string, error) {
+	out := map[string][]string{}
+	for _, p := range props {
+		v, err := canonicalJSON(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for property of type %q: %v", p.Type, err)
+		}
+		out[p.Type] = append(out[p.Type], v)
+	}
+	for typ := range out {
+		slices.Sort(out[typ])
+	}
+	return out, nil
+}
+
+func rawMessages(vals []string) any {
+	if len(vals) == 0 {
+		return nil
+	}
+	out := make([]json.RawMessage, 0, len(vals))
+	for _, v := range vals {
+		out = append(out, json.RawMessage(v))
+	}
+	return out
+}
+
+type metaKey struct {
+	schema string
+	name   string
+}
+
+// diffMetas compares custom schema blobs, matching them by schema and name.
+// When several blobs share the same schema and name, they are compared as a
+// set of canonical JSON documents.
+func diffMetas(oldMetas, newMetas []Meta) ([]MetaDiff, error) {
+	oldByKey, err := canonicalMetasByKey(oldMetas)
+	if err != nil {
+		return nil, err
+	}
+	newByKey, err := canonicalMetasByKey(newMetas)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := sets.KeySet(oldByKey).Union(sets.KeySet(newByKey)).UnsortedList()
+	slices.SortFunc(keys, func(a, b metaKey) int {
+		if c := strings.Compare(a.schema, b.schema); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var diffs []MetaDiff
+	for _, k := range keys {
+		oldBlobs, newBlobs := oldByKey[k], newByKey[k]
+		if len(oldBlobs) == 1 && len(newBlobs) == 1 {
+			if oldBlobs[0] != newBlobs[0] {
+				diffs = append(diffs, MetaDiff{Schema: k.schema, Name: k.name, Change: ChangeChanged, Old: json.RawMessage(oldBlobs[0]), New: json.RawMessage(newBlobs[0])})
+			}
+			continue
+		}
+		oldSet, newSet := sets.New(oldBlobs...), sets.New(newBlobs...)
+		for _, blob := range sets.List(oldSet.Difference(newSet)) {
+			diffs = append(diffs, MetaDiff{Schema: k.schema, Name: k.name, Change: ChangeRemoved, Old: json.RawMessage(blob)})
+		}
+		for _, blob := range sets.List(newSet.Difference(oldSet)) {
+			diffs = append(diffs, MetaDiff{Schema: k.schema, Name: k.name, Change: ChangeAdded, New: json.RawMessage(blob)})
+		}
+	}
+	return diffs, nil
+}
+
+func canonicalMetasByKey(metas []Meta) (map[metaKey][]string, error) {
+	out := map[metaKey][]string{}
+	for _, m := range metas {
+		blob, err := canonicalJSON(m.Blob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob for schema %q: %v", m.Schema, err)
+		}
+		k := metaKey{schema: m.Schema, name: m.Name}
+		out[k] = append(out[k], blob)
+	}
+	return out, nil
+}
+
+// canonicalJSON re-encodes a JSON document with sorted object keys and no
+// insignificant whitespace, so that semantically equal documents compare equal.
+func canonicalJSON(in []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(in))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func iconsEqual(a, b *Icon) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.MediaType == b.MediaType && bytes.Equal(a.Data, b.Data)
+}
+
+func iconSummary(i *Icon) any {
+	if i == nil {
+		return nil
+	}
+	return fmt.Sprintf("%s (%d bytes)", i.MediaType, len(i.Data))
+}
+
+func appendStringChange(fields []FieldChange, name, oldVal, newVal string) []FieldChange {
+	if oldVal == newVal {
+		return fields
+	}
+	fc := FieldChange{Field: name}
+	if oldVal != "" {
+		fc.Old = oldVal
+	}
+	if newVal != "" {
+		fc.New = newVal
+	}
+	return append(fields, fc)
+}
+
+// nilIfEmpty avoids storing a typed nil slice in a FieldChange value, which
+// would not be omitted from JSON output.
+func nilIfEmpty(s []string) any {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func sortedUnion[V any](a, b map[string]V) []string {
+	return sets.List(sets.KeySet(a).Union(sets.KeySet(b)))
+}
+
+// WriteJSON writes the diff result as an indented JSON document.
+func (r DiffResult) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
+
+// WriteText writes a human-readable summary of the diff result.
+//
+// Example output:
+//
+//	package "foo": changed
+//	  defaultChannel: "stable" -> "fast"
+//	  channel "fast": added
+//	    entry "foo.v0.2.0": added
+//	      replaces: "" -> "foo.v0.1.0"
+//	  bundle "foo.v0.2.0": added
+//	  deprecation olm.bundle "foo.v0.1.0": added
+//	    message: "" -> "use 0.2.0"
+func (r DiffResult) WriteText(w io.Writer) error {
+	tw := &textDiffWriter{w: w}
+	for _, p := range r.Packages {
+		tw.printf(0, "package %q: %s\n", p.Name, p.Change)
+		tw.fields(1, p.Fields)
+		for _, c := range p.Channels {
+			tw.printf(1, "channel %q: %s\n", c.Name, c.Change)
+			tw.fields(2, c.Fields)
+			for _, e := range c.Entries {
+				tw.printf(2, "entry %q: %s\n", e.Name, e.Change)
+				tw.fields(3, e.Fields)
+			}
+		}
+		for _, b := range p.Bundles {
+			tw.printf(1, "bundle %q: %s\n", b.Name, b.Change)
+			tw.fields(2, b.Fields)
+		}
+		for _, d := range p.Deprecations {
+			tw.printf(1, "deprecation %s: %s\n", deprecationReferenceString(d.Reference), d.Change)
+			tw.printf(2, "message: %q -> %q\n", d.OldMessage, d.NewMessage)
+		}
+		tw.metas(1, p.Others)
+	}
+	tw.metas(0, r.Others)
+	return tw.err
+}
+
+func deprecationReferenceString(ref PackageScopedReference) string {
+	if ref.Name == "" {
+		return ref.Schema
+	}
+	return fmt.Sprintf("%s %q", ref.Schema, ref.Name)
+}
+
+type textDiffWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (tw *textDiffWriter) printf(indent int, format string, args ...any) {
+	if tw.err != nil {
+		return
+	}
+	_, tw.err = fmt.Fprintf(tw.w, strings.Repeat("  ", indent)+format, args...)
+}
+
+func (tw *textDiffWriter) fields(indent int, fields []FieldChange) {
+	for _, f := range fields {
+		tw.printf(indent, "%s: %s -> %s\n", f.Field, textValue(f.Old), textValue(f.New))
+	}
+}
+
+func (tw *textDiffWriter) metas(indent int, metas []MetaDiff) {
+	for _, m := range metas {
+		if m.Name == "" {
+			tw.printf(indent, "%s: %s\n", m.Schema, m.Change)
+		} else {
+			tw.printf(indent, "%s %q: %s\n", m.Schema, m.Name, m.Change)
+		}
+		if m.Old != nil {
+			tw.printf(indent+1, "- %s\n", truncateTextValue(string(m.Old)))
+		}
+		if m.New != nil {
+			tw.printf(indent+1, "+ %s\n", truncateTextValue(string(m.New)))
+		}
+	}
+}
+
+func textValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return `""`
+	case string:
+		return fmt.Sprintf("%q", t)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return truncateTextValue(string(data))
+}
+
+// truncateTextValue keeps large values, such as olm.bundle.object properties,
+// from overwhelming the text output. The JSON output is never truncated.
+func truncateTextValue(s string) string {
+	const maxLen = 200
+	if len(s) <= maxLen {
+		return s
+	}
+	// Cut on a rune boundary, so that the result is valid UTF-8.
+	end := maxLen
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return fmt.Sprintf("%s... (%d bytes)", s[:end], len(s))
+}
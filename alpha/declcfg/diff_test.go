@@ -0,0 +1,180 @@
+package declcfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+func TestDiff(t *testing.T) {
+	type spec struct {
+		name     string
+		mutate   func(*DeclarativeConfig)
+		expected DiffResult
+	}
+	specs := []spec{
+		{
+			name:     "Success/NoChanges",
+			mutate:   func(*DeclarativeConfig) {},
+			expected: DiffResult{},
+		},
+		{
+			name: "Success/ReorderingIsNotAChange",
+			mutate: func(cfg *DeclarativeConfig) {
+				cfg.Packages[0], cfg.Packages[1] = cfg.Packages[1], cfg.Packages[0]
+				cfg.Bundles[0], cfg.Bundles[4] = cfg.Bundles[4], cfg.Bundles[0]
+				props := cfg.Bundles[1].Properties
+				props[0], props[len(props)-1] = props[len(props)-1], props[0]
+				entries := cfg.Channels[0].Entries
+				entries[0], entries[2] = entries[2], entries[0]
+				cfg.Others[2].Blob = json.RawMessage(`{"schema":"custom.3","package":"anakin","myField":"foobar"}`)
+			},
+			expected: DiffResult{},
+		},
+		{
+			name: "Success/PackageAddedAndRemoved",
+			mutate: func(cfg *DeclarativeConfig) {
+				cfg.Packages = append(cfg.Packages[:1], newTestPackage("cody", "clone", svgSmallCircle))
+				cfg.Channels = append(cfg.Channels[:2], newTestChannel("cody", "clone", ChannelEntry{Name: testBundleName("cody", "1.0.0")}))
+				cfg.Bundles = append(cfg.Bundles[:3], newTestBundle("cody", "1.0.0"))
+				cfg.Others = cfg.Others[:3]
+			},
+			expected: DiffResult{
+				Packages: []PackageDiff{
+					{
+						Name:   "boba-fett",
+						Change: ChangeRemoved,
+						Channels: []ChannelDiff{{
+							Name:   "mando",
+							Change: ChangeRemoved,
+							Entries: []ChannelEntryDiff{
+								{Name: testBundleName("boba-fett", "1.0.0"), Change: ChangeRemoved},
+								{Name: testBundleName("boba-fett", "2.0.0"), Change: ChangeRemoved, Fields: []FieldChange{{Field: "replaces", Old: testBundleName("boba-fett", "1.0.0")}}},
+							},
+						}},
+						Bundles: []BundleDiff{
+							{Name: testBundleName("boba-fett", "1.0.0"), Change: ChangeRemoved},
+							{Name: testBundleName("boba-fett", "2.0.0"), Change: ChangeRemoved},
+						},
+						Others: []MetaDiff{{Schema: "custom.3", Change: ChangeRemoved, Old: json.RawMessage(`{"myField":"foobar","package":"boba-fett","schema":"custom.3"}`)}},
+					},
+					{
+						Name:   "cody",
+						Change: ChangeAdded,
+						Channels: []ChannelDiff{{
+							Name:    "clone",
+							Change:  ChangeAdded,
+							Entries: []ChannelEntryDiff{{Name: testBundleName("cody", "1.0.0"), Change: ChangeAdded}},
+						}},
+						Bundles: []BundleDiff{{Name: testBundleName("cody", "1.0.0"), Change: ChangeAdded}},
+					},
+				},
+			},
+		},
+		{
+			name: "Success/FieldsChanged",
+			mutate: func(cfg *DeclarativeConfig) {
+				cfg.Packages[0].DefaultChannel = "light"
+				cfg.Channels[0].Entries[2].Skips = nil
+				cfg.Channels[0].Entries[2].SkipRange = "<0.1.1"
+				cfg.Bundles[0].Image = "anakin-bundle:new"
+				cfg.Bundles[0].Properties = append(cfg.Bundles[0].Properties, property.MustBuildGVK("jedi.io", "v1", "Lightsaber"))
+				cfg.Deprecations[0].Entries[1].Message = "The light side is deprecated"
+				cfg.Deprecations[0].Entries = cfg.Deprecations[0].Entries[1:]
+				cfg.Others[0].Blob = json.RawMessage(`{"schema": "custom.1", "foo": "bar"}`)
+			},
+			expected: DiffResult{
+				Packages: []PackageDiff{{
+					Name:   "anakin",
+					Change: ChangeChanged,
+					Fields: []FieldChange{{Field: "defaultChannel", Old: "dark", New: "light"}},
+					Channels: []ChannelDiff{{
+						Name:   "dark",
+						Change: ChangeChanged,
+						Entries: []ChannelEntryDiff{{
+							Name:   testBundleName("anakin", "0.1.1"),
+							Change: ChangeChanged,
+							Fields: []FieldChange{
+								{Field: "skips", Old: []string{testBundleName("anakin", "0.1.0")}},
+								{Field: "skipRange", New: "<0.1.1"},
+							},
+						}},
+					}},
+					Bundles: []BundleDiff{{
+						Name:   testBundleName("anakin", "0.0.1"),
+						Change: ChangeChanged,
+						Fields: []FieldChange{
+							{Field: "image", Old: testBundleImage("anakin", "0.0.1"), New: "anakin-bundle:new"},
+							{Field: "properties[olm.gvk]", New: []json.RawMessage{json.RawMessage(`{"group":"jedi.io","kind":"Lightsaber","version":"v1"}`)}},
+						},
+					}},
+					Deprecations: []DeprecationDiff{
+						{Reference: PackageScopedReference{Schema: SchemaBundle, Name: testBundleName("anakin", "0.0.1")}, Change: ChangeRemoved, OldMessage: "This bundle version is deprecated"},
+						{Reference: PackageScopedReference{Schema: SchemaChannel, Name: "light"}, Change: ChangeChanged, OldMessage: "This channel is deprecated", NewMessage: "The light side is deprecated"},
+					},
+				}},
+				Others: []MetaDiff{{Schema: "custom.1", Change: ChangeChanged, Old: json.RawMessage(`{"schema":"custom.1"}`), New: json.RawMessage(`{"foo":"bar","schema":"custom.1"}`)}},
+			},
+		},
+	}
+
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			oldCfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true})
+			newCfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true})
+			s.mutate(&newCfg)
+
+			actual, err := Diff(oldCfg, newCfg)
+			require.NoError(t, err)
+			require.Equal(t, s.expected, *actual)
+			require.Equal(t, len(s.expected.Packages) == 0 && len(s.expected.Others) == 0, actual.Empty())
+		})
+	}
+}
+
+func TestDiffDuplicates(t *testing.T) {
+	cfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{})
+	dup := cfg
+	dup.Bundles = append(dup.Bundles, dup.Bundles[0])
+	_, err := Diff(cfg, dup)
+	require.EqualError(t, err, `new config: duplicate bundle "anakin.v0.0.1" in package "anakin"`)
+}
+
+func TestDiffResultWriteText(t *testing.T) {
+	oldCfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeDeprecations: true})
+	newCfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeDeprecations: true})
+	newCfg.Packages[1].DefaultChannel = "clone"
+	newCfg.Channels = append(newCfg.Channels, newTestChannel("boba-fett", "clone", ChannelEntry{Name: testBundleName("boba-fett", "2.0.0")}))
+	newCfg.Deprecations[0].Entries = newCfg.Deprecations[0].Entries[:2]
+
+	res, err := Diff(oldCfg, newCfg)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, res.WriteText(buf))
+	require.Equal(t, `package "anakin": changed
+  deprecation olm.package: removed
+    message: "This package is deprecated... there is another" -> ""
+package "boba-fett": changed
+  defaultChannel: "mando" -> "clone"
+  channel "clone": added
+    entry "boba-fett.v2.0.0": added
+`, buf.String())
+}
+
+func TestTruncateTextValue(t *testing.T) {
+	short := strings.Repeat("a", 200)
+	require.Equal(t, short, truncateTextValue(short))
+
+	// The 200th byte is in the middle of "é", which is not split.
+	long := strings.Repeat("a", 199) + strings.Repeat("é", 10)
+	truncated := truncateTextValue(long)
+	require.True(t, utf8.ValidString(truncated))
+	require.Equal(t, strings.Repeat("a", 199)+"... (219 bytes)", truncated)
+}
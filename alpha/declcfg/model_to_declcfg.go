@@ -32,6 +32,14 @@ func ConvertFromModel(mpkgs model.Model) DeclarativeConfig {
 		})
 		cfg.Channels = append(cfg.Channels, channels...)
 		cfg.Bundles = append(cfg.Bundles, bundles...)
+		for _, o := range mpkg.Others {
+			cfg.Others = append(cfg.Others, Meta{
+				Schema:  o.Schema,
+				Package: mpkg.Name,
+				Name:    o.Name,
+				Blob:    o.Blob,
+			})
+		}
 	}
 
 	sort.Slice(cfg.Packages, func(i, j int) bool {
@@ -49,6 +57,10 @@ func ConvertFromModel(mpkgs model.Model) DeclarativeConfig {
 		}
 		return cfg.Bundles[i].Name < cfg.Bundles[j].Name
 	})
+	// Keep the order of the blobs of each package.
+	sort.SliceStable(cfg.Others, func(i, j int) bool {
+		return cfg.Others[i].Package < cfg.Others[j].Package
+	})
 
 	return cfg
 }
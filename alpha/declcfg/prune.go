@@ -0,0 +1,235 @@
+package declcfg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// PrunePackages returns a copy of cfg that only contains the objects of the
+// given packages. Objects that do not belong to any package are kept.
+func PrunePackages(cfg DeclarativeConfig, packages []string) (*DeclarativeConfig, error) {
+	keep := sets.New(packages...)
+	found := sets.New[string]()
+	out := &DeclarativeConfig{}
+	for _, p := range cfg.Packages {
+		if keep.Has(p.Name) {
+			found.Insert(p.Name)
+			out.Packages = append(out.Packages, p)
+		}
+	}
+	if missing := sets.List(keep.Difference(found)); len(missing) > 0 {
+		return nil, fmt.Errorf("packages not found in catalog: %s", strings.Join(missing, ", "))
+	}
+	for _, ch := range cfg.Channels {
+		if keep.Has(ch.Package) {
+			out.Channels = append(out.Channels, ch)
+		}
+	}
+	for _, b := range cfg.Bundles {
+		if keep.Has(b.Package) {
+			out.Bundles = append(out.Bundles, b)
+		}
+	}
+	for _, d := range cfg.Deprecations {
+		if keep.Has(d.Package) {
+			out.Deprecations = append(out.Deprecations, d)
+		}
+	}
+	for _, o := range cfg.Others {
+		if o.Package == "" || keep.Has(o.Package) {
+			out.Others = append(out.Others, o)
+		}
+	}
+	return out, nil
+}
+
+// PruneStranded returns a copy of cfg without stranded channel entries, and
+// without the bundles that are no longer in any channel as a result.
+//
+// An entry is stranded if it is neither on the replaces chain that starts
+// at the channel head nor skipped by another entry, which is the condition
+// that channel validation rejects.
+func PruneStranded(cfg DeclarativeConfig) (*DeclarativeConfig, error) {
+	out := cfg
+	out.Channels = make([]Channel, 0, len(cfg.Channels))
+	for _, ch := range cfg.Channels {
+		stranded, err := strandedEntries(ch)
+		if err != nil {
+			return nil, fmt.Errorf("package %q, channel %q: %v", ch.Package, ch.Name, err)
+		}
+		out.Channels = append(out.Channels, withoutEntries(ch, stranded))
+	}
+	removeUnreferencedBundles(&out)
+	return &out, nil
+}
+
+// TruncateChannel returns a copy of cfg in which the given bundle is the
+// tail of the upgrade graph of the named channel of a package, or of all
+// channels of the package that contain the bundle if channel is empty.
+//
+// All entries that the bundle replaces or skips, directly or transitively,
+// are removed from the channel, along with the bundles that are no longer
+// in any channel as a result. The bundle's own upgrade edges are kept,
+// since the tail of a replaces chain may replace a missing entry.
+func TruncateChannel(cfg DeclarativeConfig, packageName, bundleName, channel string) (*DeclarativeConfig, error) {
+	out := cfg
+	out.Channels = slices.Clone(cfg.Channels)
+	truncated := false
+	for i, ch := range out.Channels {
+		if ch.Package != packageName || (channel != "" && ch.Name != channel) {
+			continue
+		}
+		below, ok := entriesBelow(ch, bundleName)
+		if !ok {
+			if channel != "" {
+				return nil, fmt.Errorf("bundle %q not found in channel %q of package %q", bundleName, channel, packageName)
+			}
+			continue
+		}
+		out.Channels[i] = withoutEntries(ch, below)
+		truncated = true
+	}
+	if !truncated {
+		if channel != "" {
+			return nil, fmt.Errorf("channel %q not found in package %q", channel, packageName)
+		}
+		return nil, fmt.Errorf("bundle %q not found in any channel of package %q", bundleName, packageName)
+	}
+	removeUnreferencedBundles(&out)
+	return &out, nil
+}
+
+// channelHead returns the entry of ch that no other entry replaces or skips.
+func channelHead(ch Channel) (*ChannelEntry, error) {
+	incoming := sets.New[string]()
+	for _, e := range ch.Entries {
+		if e.Replaces != "" {
+			incoming.Insert(e.Replaces)
+		}
+		incoming.Insert(e.Skips...)
+	}
+	var heads []*ChannelEntry
+	for i := range ch.Entries {
+		if !incoming.Has(ch.Entries[i].Name) {
+			heads = append(heads, &ch.Entries[i])
+		}
+	}
+	switch len(heads) {
+	case 0:
+		return nil, fmt.Errorf("no channel head found in graph")
+	case 1:
+		return heads[0], nil
+	}
+	names := make([]string, 0, len(heads))
+	for _, h := range heads {
+		names = append(names, h.Name)
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("multiple channel heads found in graph: %s", strings.Join(names, ", "))
+}
+
+// strandedEntries returns the names of the entries of ch that are neither on
+// the replaces chain from the channel head nor skipped by another entry.
+func strandedEntries(ch Channel) (sets.Set[string], error) {
+	head, err := channelHead(ch)
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]ChannelEntry{}
+	all := sets.New[string]()
+	skipped := sets.New[string]()
+	for _, e := range ch.Entries {
+		entries[e.Name] = e
+		all.Insert(e.Name)
+		skipped.Insert(e.Skips...)
+	}
+
+	chain := sets.New(head.Name)
+	cur, ok := *head, true
+	for ok && cur.Replaces != "" && !skipped.Has(cur.Replaces) {
+		if chain.Has(cur.Replaces) {
+			return nil, fmt.Errorf("detected cycle in replaces chain of upgrade graph at %q", cur.Replaces)
+		}
+		chain.Insert(cur.Replaces)
+		cur, ok = entries[cur.Replaces]
+	}
+	return all.Difference(chain).Difference(skipped), nil
+}
+
+// entriesBelow returns the names of the entries of ch that the named entry
+// replaces or skips, directly or transitively. It returns false if ch does
+// not contain the entry.
+func entriesBelow(ch Channel, name string) (sets.Set[string], bool) {
+	entries := map[string]ChannelEntry{}
+	for _, e := range ch.Entries {
+		entries[e.Name] = e
+	}
+	if _, ok := entries[name]; !ok {
+		return nil, false
+	}
+	below := sets.New[string]()
+	queue := []string{name}
+	for len(queue) > 0 {
+		e, ok := entries[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		next := e.Skips
+		if e.Replaces != "" {
+			next = append(slices.Clone(next), e.Replaces)
+		}
+		for _, n := range next {
+			if n != name && !below.Has(n) {
+				below.Insert(n)
+				queue = append(queue, n)
+			}
+		}
+	}
+	return below, true
+}
+
+func withoutEntries(ch Channel, names sets.Set[string]) Channel {
+	if names.Len() == 0 {
+		return ch
+	}
+	ch.Entries = slices.DeleteFunc(slices.Clone(ch.Entries), func(e ChannelEntry) bool {
+		return names.Has(e.Name)
+	})
+	return ch
+}
+
+// removeUnreferencedBundles removes the bundles that are not in any channel
+// of their package, and the deprecation entries that refer to them.
+func removeUnreferencedBundles(cfg *DeclarativeConfig) {
+	referenced := map[string]sets.Set[string]{}
+	for _, ch := range cfg.Channels {
+		if referenced[ch.Package] == nil {
+			referenced[ch.Package] = sets.New[string]()
+		}
+		for _, e := range ch.Entries {
+			referenced[ch.Package].Insert(e.Name)
+		}
+	}
+	isReferenced := func(pkg, name string) bool {
+		return referenced[pkg] != nil && referenced[pkg].Has(name)
+	}
+
+	cfg.Bundles = slices.DeleteFunc(slices.Clone(cfg.Bundles), func(b Bundle) bool {
+		return !isReferenced(b.Package, b.Name)
+	})
+	if len(cfg.Deprecations) == 0 {
+		return
+	}
+	deprecations := make([]Deprecation, 0, len(cfg.Deprecations))
+	for _, d := range cfg.Deprecations {
+		d.Entries = slices.DeleteFunc(slices.Clone(d.Entries), func(e DeprecationEntry) bool {
+			return e.Reference.Schema == SchemaBundle && !isReferenced(d.Package, e.Reference.Name)
+		})
+		deprecations = append(deprecations, d)
+	}
+	cfg.Deprecations = deprecations
+}
@@ -0,0 +1,189 @@
+package declcfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bundleNames(cfg DeclarativeConfig) []string {
+	names := []string{}
+	for _, b := range cfg.Bundles {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func channelEntryNames(cfg DeclarativeConfig) map[string][]string {
+	entries := map[string][]string{}
+	for _, ch := range cfg.Channels {
+		key := ch.Package + "/" + ch.Name
+		entries[key] = []string{}
+		for _, e := range ch.Entries {
+			entries[key] = append(entries[key], e.Name)
+		}
+	}
+	return entries
+}
+
+func TestPrunePackages(t *testing.T) {
+	cfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true})
+
+	out, err := PrunePackages(cfg, []string{"anakin"})
+	require.NoError(t, err)
+	require.Len(t, out.Packages, 1)
+	require.Equal(t, "anakin", out.Packages[0].Name)
+	require.Equal(t, map[string][]string{
+		"anakin/dark":  {"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1"},
+		"anakin/light": {"anakin.v0.0.1", "anakin.v0.1.0"},
+	}, channelEntryNames(*out))
+	require.Equal(t, []string{"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1"}, bundleNames(*out))
+	require.Len(t, out.Deprecations, 1)
+	var otherSchemas []string
+	for _, o := range out.Others {
+		require.Contains(t, []string{"", "anakin"}, o.Package)
+		otherSchemas = append(otherSchemas, o.Schema)
+	}
+	require.Equal(t, []string{"custom.1", "custom.2", "custom.3"}, otherSchemas)
+
+	_, err = PrunePackages(cfg, []string{"anakin", "yoda"})
+	require.EqualError(t, err, "packages not found in catalog: yoda")
+}
+
+func TestPruneStranded(t *testing.T) {
+	type spec struct {
+		name            string
+		mutate          func(*DeclarativeConfig)
+		expectedEntries map[string][]string
+		expectedBundles []string
+		expectedError   string
+	}
+	specs := []spec{
+		{
+			name:   "Success/NothingStranded",
+			mutate: func(*DeclarativeConfig) {},
+			expectedEntries: map[string][]string{
+				"anakin/dark":     {"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1"},
+				"anakin/light":    {"anakin.v0.0.1", "anakin.v0.1.0"},
+				"boba-fett/mando": {"boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+			},
+			expectedBundles: []string{"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1", "boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+		},
+		{
+			name: "Success/StrandedBelowSkippedReplaces",
+			mutate: func(cfg *DeclarativeConfig) {
+				cfg.Bundles = append(cfg.Bundles, newTestBundle("boba-fett", "3.0.0"))
+				cfg.Channels[2].Entries = append(cfg.Channels[2].Entries, ChannelEntry{
+					Name:     testBundleName("boba-fett", "3.0.0"),
+					Replaces: testBundleName("boba-fett", "2.0.0"),
+					Skips:    []string{testBundleName("boba-fett", "2.0.0")},
+				})
+			},
+			expectedEntries: map[string][]string{
+				"anakin/dark":     {"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1"},
+				"anakin/light":    {"anakin.v0.0.1", "anakin.v0.1.0"},
+				"boba-fett/mando": {"boba-fett.v2.0.0", "boba-fett.v3.0.0"},
+			},
+			expectedBundles: []string{"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1", "boba-fett.v2.0.0", "boba-fett.v3.0.0"},
+		},
+		{
+			name: "Fail/MultipleHeads",
+			mutate: func(cfg *DeclarativeConfig) {
+				cfg.Channels[2].Entries = append(cfg.Channels[2].Entries, ChannelEntry{Name: testBundleName("boba-fett", "3.0.0")})
+			},
+			expectedError: `package "boba-fett", channel "mando": multiple channel heads found in graph: boba-fett.v2.0.0, boba-fett.v3.0.0`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeDeprecations: true})
+			s.mutate(&cfg)
+			out, err := PruneStranded(cfg)
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expectedEntries, channelEntryNames(*out))
+			require.Equal(t, s.expectedBundles, bundleNames(*out))
+			_, err = ConvertToModel(*out)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTruncateChannel(t *testing.T) {
+	type spec struct {
+		name                    string
+		bundle, channel         string
+		expectedEntries         map[string][]string
+		expectedBundles         []string
+		expectedDeprecationRefs []string
+		expectedError           string
+	}
+	specs := []spec{
+		{
+			name:   "Success/AllChannels",
+			bundle: "anakin.v0.1.0",
+			expectedEntries: map[string][]string{
+				"anakin/dark":     {"anakin.v0.1.0", "anakin.v0.1.1"},
+				"anakin/light":    {"anakin.v0.1.0"},
+				"boba-fett/mando": {"boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+			},
+			expectedBundles:         []string{"anakin.v0.1.0", "anakin.v0.1.1", "boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+			expectedDeprecationRefs: []string{"olm.channel/light", "olm.package/"},
+		},
+		{
+			name:    "Success/SingleChannel",
+			bundle:  "anakin.v0.1.0",
+			channel: "light",
+			expectedEntries: map[string][]string{
+				"anakin/dark":     {"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1"},
+				"anakin/light":    {"anakin.v0.1.0"},
+				"boba-fett/mando": {"boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+			},
+			expectedBundles:         []string{"anakin.v0.0.1", "anakin.v0.1.0", "anakin.v0.1.1", "boba-fett.v1.0.0", "boba-fett.v2.0.0"},
+			expectedDeprecationRefs: []string{"olm.bundle/anakin.v0.0.1", "olm.channel/light", "olm.package/"},
+		},
+		{
+			name:          "Fail/BundleNotInChannel",
+			bundle:        "anakin.v0.1.1",
+			channel:       "light",
+			expectedError: `bundle "anakin.v0.1.1" not found in channel "light" of package "anakin"`,
+		},
+		{
+			name:          "Fail/UnknownChannel",
+			bundle:        "anakin.v0.1.0",
+			channel:       "neutral",
+			expectedError: `channel "neutral" not found in package "anakin"`,
+		},
+		{
+			name:          "Fail/UnknownBundle",
+			bundle:        "anakin.v9.9.9",
+			expectedError: `bundle "anakin.v9.9.9" not found in any channel of package "anakin"`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeDeprecations: true})
+			out, err := TruncateChannel(cfg, "anakin", s.bundle, s.channel)
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expectedEntries, channelEntryNames(*out))
+			require.Equal(t, s.expectedBundles, bundleNames(*out))
+			var refs []string
+			for _, e := range out.Deprecations[0].Entries {
+				refs = append(refs, e.Reference.Schema+"/"+e.Reference.Name)
+			}
+			require.Equal(t, s.expectedDeprecationRefs, refs)
+			_, err = ConvertToModel(*out)
+			require.NoError(t, err)
+
+			// The input is not modified.
+			require.Equal(t, buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeDeprecations: true}), cfg)
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/yaml"
 
+	"github.com/operator-framework/operator-registry/alpha/model"
 	"github.com/operator-framework/operator-registry/alpha/property"
 )
 
@@ -84,162 +85,63 @@ func WithV0Semantics(drawV0Semantics bool) MermaidOption {
 //
 // end
 func (writer *MermaidWriter) WriteChannels(cfg DeclarativeConfig, out io.Writer) error {
-	pkgs := map[string]*strings.Builder{}
-
-	sort.Slice(cfg.Channels, func(i, j int) bool {
-		return cfg.Channels[i].Name < cfg.Channels[j].Name
-	})
-
-	versionMap, err := getBundleVersions(&cfg)
+	g, err := buildChannelGraph(cfg, writer.graphOptions())
 	if err != nil {
 		return err
 	}
 
-	// establish a 'floor' version, either specified by user or entirely open
-	minVersion := semver.Version{Major: 0, Minor: 0, Patch: 0}
-
-	if writer.MinEdgeName != "" {
-		if _, ok := versionMap[writer.MinEdgeName]; !ok {
-			return fmt.Errorf("unknown minimum edge name: %q", writer.MinEdgeName)
-		}
-		minVersion = versionMap[writer.MinEdgeName]
-	}
-
-	// build increasing-version-ordered bundle names, so we can meaningfully iterate over a range
-	orderedBundles := []string{}
-	for n := range versionMap {
-		orderedBundles = append(orderedBundles, n)
-	}
-	sort.Slice(orderedBundles, func(i, j int) bool {
-		return versionMap[orderedBundles[i]].LT(versionMap[orderedBundles[j]])
-	})
-
-	minEdgePackage := writer.getMinEdgePackage(&cfg)
-
-	depByPackage := sets.Set[string]{}
-	depByChannel := sets.Set[string]{}
-	depByBundle := sets.Set[string]{}
-
-	for _, d := range cfg.Deprecations {
-		for _, e := range d.Entries {
-			switch e.Reference.Schema {
-			case SchemaPackage:
-				depByPackage.Insert(d.Package)
-			case SchemaChannel:
-				depByChannel.Insert(e.Reference.Name)
-			case SchemaBundle:
-				depByBundle.Insert(e.Reference.Name)
-			}
-		}
-	}
-
+	pkgs := map[string]*strings.Builder{}
 	var deprecatedPackage string
 	deprecatedChannelIDs := []string{}
 	decoratedBundleIDs := map[string][]string{"deprecated": {}, "skipped": {}, "deprecatedskipped": {}}
 	linkID := 0
 	skippedLinkIDs := []string{}
 
-	for _, c := range cfg.Channels {
-		filteredChannel := writer.filterChannel(&c, versionMap, minVersion, minEdgePackage)
-		// nolint:nestif
-		if filteredChannel != nil {
-			pkgBuilder, ok := pkgs[c.Package]
-			if !ok {
-				pkgBuilder = &strings.Builder{}
-				pkgs[c.Package] = pkgBuilder
-			}
+	for _, c := range g.channels {
+		pkgBuilder, ok := pkgs[c.pkg]
+		if !ok {
+			pkgBuilder = &strings.Builder{}
+			pkgs[c.pkg] = pkgBuilder
+		}
 
-			channelID := fmt.Sprintf("%s-%s", filteredChannel.Package, filteredChannel.Name)
-			fmt.Fprintf(pkgBuilder, "    %%%% channel %q\n", filteredChannel.Name)
-			fmt.Fprintf(pkgBuilder, "    subgraph %s[%q]\n", channelID, filteredChannel.Name)
+		fmt.Fprintf(pkgBuilder, "    %%%% channel %q\n", c.name)
+		fmt.Fprintf(pkgBuilder, "    subgraph %s[%q]\n", c.id, c.name)
 
-			if depByPackage.Has(filteredChannel.Package) {
-				deprecatedPackage = filteredChannel.Package
-			}
+		if g.deprecatedPackages.Has(c.pkg) {
+			deprecatedPackage = c.pkg
+		}
 
-			if depByChannel.Has(filteredChannel.Name) {
-				deprecatedChannelIDs = append(deprecatedChannelIDs, channelID)
-			}
+		if c.deprecated {
+			deprecatedChannelIDs = append(deprecatedChannelIDs, c.id)
+		}
 
-			// sort edges by decreasing version
-			sortedEntries := make([]*ChannelEntry, 0, len(filteredChannel.Entries))
-			for i := range filteredChannel.Entries {
-				sortedEntries = append(sortedEntries, &filteredChannel.Entries[i])
+		for _, n := range c.nodes {
+			fmt.Fprintf(pkgBuilder, "      %s[%q]\n", n.id, n.name)
+
+			// mermaid allows specification of only a single decoration class, so any combinations must be independently represented
+			switch {
+			case n.deprecated && n.skipped:
+				decoratedBundleIDs["deprecatedskipped"] = append(decoratedBundleIDs["deprecatedskipped"], n.id)
+			case n.deprecated:
+				decoratedBundleIDs["deprecated"] = append(decoratedBundleIDs["deprecated"], n.id)
+			case n.skipped:
+				decoratedBundleIDs["skipped"] = append(decoratedBundleIDs["skipped"], n.id)
 			}
-			sort.Slice(sortedEntries, func(i, j int) bool {
-				// Sort by decreasing version: greater version comes first
-				return versionMap[sortedEntries[i].Name].GT(versionMap[sortedEntries[j].Name])
-			})
-
-			skippedEntities := sets.Set[string]{}
-
-			const (
-				captureNewEntry = true
-				processExisting = false
-			)
-			handleSemantics := func(edge string, linkID int, captureNew bool) {
-				if writer.DrawV0Semantics {
-					if captureNew {
-						if skippedEntities.Has(edge) {
-							skippedLinkIDs = append(skippedLinkIDs, fmt.Sprintf("%d", linkID))
-						} else {
-							skippedEntities.Insert(edge)
-						}
-					} else {
-						if skippedEntities.Has(edge) {
-							skippedLinkIDs = append(skippedLinkIDs, fmt.Sprintf("%d", linkID))
-						}
-					}
-				}
-			}
-
-			for _, ce := range sortedEntries {
-				entryID := fmt.Sprintf("%s-%s", channelID, ce.Name)
-				fmt.Fprintf(pkgBuilder, "      %s[%q]\n", entryID, ce.Name)
-
-				// mermaid allows specification of only a single decoration class, so any combinations must be independently represented
-				switch {
-				case depByBundle.Has(ce.Name) && skippedEntities.Has(ce.Name):
-					decoratedBundleIDs["deprecatedskipped"] = append(decoratedBundleIDs["deprecatedskipped"], entryID)
-				case depByBundle.Has(ce.Name):
-					decoratedBundleIDs["deprecated"] = append(decoratedBundleIDs["deprecated"], entryID)
-				case skippedEntities.Has(ce.Name):
-					decoratedBundleIDs["skipped"] = append(decoratedBundleIDs["skipped"], entryID)
-				}
 
-				if len(ce.Skips) > 0 {
-					for _, s := range ce.Skips {
-						skipsID := fmt.Sprintf("%s-%s", channelID, s)
-						fmt.Fprintf(pkgBuilder, "      %s[%q]-- %s --> %s[%q]\n", skipsID, s, "skip", entryID, ce.Name)
-						handleSemantics(s, linkID, captureNewEntry)
-						linkID++
-					}
-				}
-				if len(ce.SkipRange) > 0 {
-					skipRange, err := semver.ParseRange(ce.SkipRange)
-					if err == nil {
-						for _, edgeName := range filteredChannel.Entries {
-							if skipRange(versionMap[edgeName.Name]) {
-								skipRangeID := fmt.Sprintf("%s-%s", channelID, edgeName.Name)
-								fmt.Fprintf(pkgBuilder, "      %s[%q]-- \"%s(%s)\" --> %s[%q]\n", skipRangeID, edgeName.Name, "skipRange", ce.SkipRange, entryID, ce.Name)
-								handleSemantics(ce.Name, linkID, processExisting)
-								linkID++
-							}
-						}
-					} else {
-						fmt.Fprintf(os.Stderr, "warning: ignoring invalid SkipRange for package/edge %q/%q: %v\n", c.Package, ce.Name, err)
-					}
+			for _, e := range n.edges {
+				switch e.edgeType {
+				case skipRangeGraphEdge:
+					fmt.Fprintf(pkgBuilder, "      %s[%q]-- \"%s(%s)\" --> %s[%q]\n", e.fromID, e.fromName, "skipRange", e.skipRange, n.id, n.name)
+				default:
+					fmt.Fprintf(pkgBuilder, "      %s[%q]-- %s --> %s[%q]\n", e.fromID, e.fromName, e.edgeType, n.id, n.name)
 				}
-				// have to process replaces last, because applicablity can be impacted by skips
-				if len(ce.Replaces) > 0 {
-					replacesID := fmt.Sprintf("%s-%s", channelID, ce.Replaces)
-					fmt.Fprintf(pkgBuilder, "      %s[%q]-- %s --> %s[%q]\n", replacesID, ce.Replaces, "replace", entryID, ce.Name)
-					handleSemantics(ce.Name, linkID, processExisting)
-					linkID++
+				if e.skipped {
+					skippedLinkIDs = append(skippedLinkIDs, fmt.Sprintf("%d", linkID))
 				}
+				linkID++
 			}
-			fmt.Fprintf(pkgBuilder, "    end\n")
 		}
+		fmt.Fprintf(pkgBuilder, "    end\n")
 	}
 
 	_, _ = out.Write([]byte("graph LR\n"))
@@ -287,16 +189,24 @@ func (writer *MermaidWriter) WriteChannels(cfg DeclarativeConfig, out io.Writer)
 	return nil
 }
 
+func (writer *MermaidWriter) graphOptions() GraphOptions {
+	return GraphOptions{
+		MinEdgeName:          writer.MinEdgeName,
+		SpecifiedPackageName: writer.SpecifiedPackageName,
+		DrawV0Semantics:      writer.DrawV0Semantics,
+	}
+}
+
 // filters the channel edges to include only those which are greater-than-or-equal to the edge named by startVersion
 // returns a nil channel if all edges are filtered out
-func (writer *MermaidWriter) filterChannel(c *Channel, versionMap map[string]semver.Version, minVersion semver.Version, minEdgePackage string) *Channel {
+func filterGraphChannel(c *Channel, opts GraphOptions, versionMap map[string]semver.Version, minVersion semver.Version, minEdgePackage string) *Channel {
 	// short-circuit if no active filters
-	if writer.MinEdgeName == "" && writer.SpecifiedPackageName == "" {
+	if opts.MinEdgeName == "" && opts.SpecifiedPackageName == "" {
 		return c
 	}
 
 	// short-circuit if channel's package doesn't match filter
-	if writer.SpecifiedPackageName != "" && c.Package != writer.SpecifiedPackageName {
+	if opts.SpecifiedPackageName != "" && c.Package != opts.SpecifiedPackageName {
 		return nil
 	}
 
@@ -309,7 +219,7 @@ func (writer *MermaidWriter) filterChannel(c *Channel, versionMap map[string]sem
 	for _, ce := range c.Entries {
 		filteredCe := ChannelEntry{Name: ce.Name}
 		// nolint:nestif
-		if writer.MinEdgeName == "" {
+		if opts.MinEdgeName == "" {
 			// no minimum-edge specified
 			filteredCe.SkipRange = ce.SkipRange
 			filteredCe.Replaces = ce.Replaces
@@ -325,7 +235,7 @@ func (writer *MermaidWriter) filterChannel(c *Channel, versionMap map[string]sem
 				}
 			}
 		} else {
-			if ce.Name == writer.MinEdgeName {
+			if ce.Name == opts.MinEdgeName {
 				// edge is the 'floor', meaning that since all references are "backward references", and we don't want any references from this edge
 				// accumulate w/o references
 				out.Entries = append(out.Entries, filteredCe)
@@ -405,14 +315,34 @@ func getBundleVersions(cfg *DeclarativeConfig) (map[string]semver.Version, error
 	return entries, nil
 }
 
-func (writer *MermaidWriter) getMinEdgePackage(cfg *DeclarativeConfig) string {
-	if writer.MinEdgeName == "" {
+// getBundleReleases returns the release versions of the bundles that have one
+func getBundleReleases(cfg *DeclarativeConfig) (map[string]model.Release, error) {
+	releases := make(map[string]model.Release)
+	for _, b := range cfg.Bundles {
+		props, err := property.Parse(b.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("parse properties for bundle %q: %v", b.Name, err)
+		}
+		if len(props.Packages) != 1 || props.Packages[0].Release == "" {
+			continue
+		}
+		r, err := model.NewRelease(props.Packages[0].Release)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %q has invalid release %q: %v", b.Name, props.Packages[0].Release, err)
+		}
+		releases[b.Name] = r
+	}
+	return releases, nil
+}
+
+func getMinEdgePackage(cfg *DeclarativeConfig, minEdgeName string) string {
+	if minEdgeName == "" {
 		return ""
 	}
 
 	for _, c := range cfg.Channels {
 		for _, ce := range c.Entries {
-			if writer.MinEdgeName == ce.Name {
+			if minEdgeName == ce.Name {
 				return c.Package
 			}
 		}
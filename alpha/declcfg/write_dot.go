@@ -0,0 +1,131 @@
+package declcfg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DotWriter writes the channel edges of a declarative config in the Graphviz
+// DOT language, for rendering with tools like dot(1). Unlike mermaid, DOT has
+// no input size limit, so it can draw the graphs of large catalogs.
+type DotWriter struct {
+	GraphOptions
+}
+
+func NewDotWriter(opts GraphOptions) *DotWriter {
+	return &DotWriter{GraphOptions: opts}
+}
+
+// WriteChannels writes the upgrade graph of cfg as a directed graph, with a
+// cluster per package and, within it, a cluster per channel. The graph
+// contains the same nodes and edges as the MermaidWriter output, with the
+// same decorations.
+//
+// Example output:
+//
+//	digraph "catalog" {
+//	  rankdir=LR;
+//	  node [shape=box, style="rounded,filled", fillcolor="#FFFFFF"];
+//	  subgraph "cluster_neuvector-certified-operator-rhmp" {
+//	    label="neuvector-certified-operator-rhmp";
+//	    subgraph "cluster_neuvector-certified-operator-rhmp-beta" {
+//	      label="beta";
+//	      "neuvector-certified-operator-rhmp-beta-neuvector-operator.v1.3.0" [label="neuvector-operator.v1.3.0"];
+//	      "neuvector-certified-operator-rhmp-beta-neuvector-operator.v1.2.8" -> "neuvector-certified-operator-rhmp-beta-neuvector-operator.v1.3.0" [label="replace"];
+//	      ...
+//	    }
+//	  }
+//	}
+func (writer *DotWriter) WriteChannels(cfg DeclarativeConfig, out io.Writer) error {
+	g, err := buildChannelGraph(cfg, writer.GraphOptions)
+	if err != nil {
+		return err
+	}
+
+	byPackage := map[string][]graphChannel{}
+	for _, c := range g.channels {
+		byPackage[c.pkg] = append(byPackage[c.pkg], c)
+	}
+	pkgNames := make([]string, 0, len(byPackage))
+	for name := range byPackage {
+		pkgNames = append(pkgNames, name)
+	}
+	sort.Strings(pkgNames)
+
+	w := bufio.NewWriter(out)
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote("catalog"))
+	fmt.Fprintf(w, "  rankdir=LR;\n")
+	fmt.Fprintf(w, "  node [shape=box, style=\"rounded,filled\", fillcolor=\"#FFFFFF\"];\n")
+	for _, pkgName := range pkgNames {
+		fmt.Fprintf(w, "  subgraph %s {\n", dotQuote("cluster_"+pkgName))
+		fmt.Fprintf(w, "    label=%s;\n", dotQuote(pkgName))
+		if g.deprecatedPackages.Has(pkgName) {
+			fmt.Fprintf(w, "    style=filled;\n    fillcolor=\"#989695\";\n")
+		}
+		for _, c := range byPackage[pkgName] {
+			writeDotChannel(w, c)
+		}
+		fmt.Fprintf(w, "  }\n")
+	}
+	fmt.Fprintf(w, "}\n")
+	return w.Flush()
+}
+
+func writeDotChannel(w io.Writer, c graphChannel) {
+	fmt.Fprintf(w, "    subgraph %s {\n", dotQuote("cluster_"+c.id))
+	fmt.Fprintf(w, "      label=%s;\n", dotQuote(c.name))
+	if c.deprecated {
+		fmt.Fprintf(w, "      style=filled;\n      fillcolor=\"#DCD0FF\";\n")
+	}
+
+	declared := sets.New[string]()
+	for _, n := range c.nodes {
+		declared.Insert(n.id)
+	}
+	for _, n := range c.nodes {
+		var attrs []string
+		attrs = append(attrs, "label="+dotQuote(n.name))
+		if n.deprecated {
+			attrs = append(attrs, `fillcolor="#E8960F"`)
+		}
+		if n.skipped {
+			attrs = append(attrs, `color="#FF0000"`, "penwidth=4")
+		}
+		fmt.Fprintf(w, "      %s [%s];\n", dotQuote(n.id), strings.Join(attrs, ", "))
+	}
+	for _, n := range c.nodes {
+		for _, e := range n.edges {
+			// edges may come from bundles that are not in the channel
+			if !declared.Has(e.fromID) {
+				fmt.Fprintf(w, "      %s [label=%s];\n", dotQuote(e.fromID), dotQuote(e.fromName))
+				declared.Insert(e.fromID)
+			}
+			var attrs []string
+			switch e.edgeType {
+			case skipRangeGraphEdge:
+				attrs = append(attrs, "label="+dotQuote(fmt.Sprintf("skipRange(%s)", e.skipRange)), "style=dotted")
+			case skipGraphEdge:
+				attrs = append(attrs, "label="+dotQuote(string(e.edgeType)), "style=dashed")
+			default:
+				attrs = append(attrs, "label="+dotQuote(string(e.edgeType)))
+			}
+			if e.skipped {
+				attrs = append(attrs, `color="#FF0000"`, "penwidth=3")
+			}
+			fmt.Fprintf(w, "      %s -> %s [%s];\n", dotQuote(e.fromID), dotQuote(n.id), strings.Join(attrs, ", "))
+		}
+	}
+	fmt.Fprintf(w, "    }\n")
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
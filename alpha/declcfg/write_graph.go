@@ -0,0 +1,200 @@
+package declcfg
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// GraphOptions are the options of the upgrade graph writers. They have the
+// same meaning as the fields of MermaidWriter.
+type GraphOptions struct {
+	// MinEdgeName is the channel entry that is the lower bound of the edges
+	// in the graph. If empty, all edges are included.
+	MinEdgeName string
+	// SpecifiedPackageName limits the graph to a single package. If empty,
+	// all packages are included.
+	SpecifiedPackageName string
+	// DrawV0Semantics highlights the entries and edges that OLMv0 never
+	// upgrades from, because a later entry skips them.
+	DrawV0Semantics bool
+}
+
+type graphEdgeType string
+
+const (
+	skipGraphEdge      graphEdgeType = "skip"
+	skipRangeGraphEdge graphEdgeType = "skipRange"
+	replaceGraphEdge   graphEdgeType = "replace"
+)
+
+// channelGraph is the upgrade graph drawn by the graph writers, in the order
+// in which it is drawn.
+type channelGraph struct {
+	// channels are sorted by channel name.
+	channels           []graphChannel
+	deprecatedPackages sets.Set[string]
+}
+
+type graphChannel struct {
+	id         string
+	name       string
+	pkg        string
+	deprecated bool
+	// nodes are sorted by decreasing version.
+	nodes []graphNode
+}
+
+type graphNode struct {
+	id         string
+	name       string
+	deprecated bool
+	// skipped is set if an entry with a higher version skips this one.
+	skipped bool
+	// edges are the edges into this node, in drawing order.
+	edges []graphEdge
+}
+
+type graphEdge struct {
+	fromID    string
+	fromName  string
+	edgeType  graphEdgeType
+	skipRange string
+	// skipped is set if OLMv0 never takes this edge.
+	skipped bool
+}
+
+// buildChannelGraph returns the upgrade graph of the channels of cfg that
+// opts selects.
+func buildChannelGraph(cfg DeclarativeConfig, opts GraphOptions) (*channelGraph, error) {
+	channels := make([]Channel, len(cfg.Channels))
+	copy(channels, cfg.Channels)
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+
+	versionMap, err := getBundleVersions(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	releaseMap, err := getBundleReleases(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// establish a 'floor' version, either specified by user or entirely open
+	minVersion := semver.Version{Major: 0, Minor: 0, Patch: 0}
+
+	if opts.MinEdgeName != "" {
+		if _, ok := versionMap[opts.MinEdgeName]; !ok {
+			return nil, fmt.Errorf("unknown minimum edge name: %q", opts.MinEdgeName)
+		}
+		minVersion = versionMap[opts.MinEdgeName]
+	}
+
+	minEdgePackage := getMinEdgePackage(&cfg, opts.MinEdgeName)
+
+	g := &channelGraph{deprecatedPackages: sets.Set[string]{}}
+	depByChannel := sets.Set[string]{}
+	depByBundle := sets.Set[string]{}
+
+	for _, d := range cfg.Deprecations {
+		for _, e := range d.Entries {
+			switch e.Reference.Schema {
+			case SchemaPackage:
+				g.deprecatedPackages.Insert(d.Package)
+			case SchemaChannel:
+				depByChannel.Insert(e.Reference.Name)
+			case SchemaBundle:
+				depByBundle.Insert(e.Reference.Name)
+			}
+		}
+	}
+
+	for _, c := range channels {
+		filteredChannel := filterGraphChannel(&c, opts, versionMap, minVersion, minEdgePackage)
+		if filteredChannel == nil {
+			continue
+		}
+
+		channelID := fmt.Sprintf("%s-%s", filteredChannel.Package, filteredChannel.Name)
+		gc := graphChannel{
+			id:         channelID,
+			name:       filteredChannel.Name,
+			pkg:        filteredChannel.Package,
+			deprecated: depByChannel.Has(filteredChannel.Name),
+		}
+
+		// sort edges by decreasing version
+		sortedEntries := make([]*ChannelEntry, 0, len(filteredChannel.Entries))
+		for i := range filteredChannel.Entries {
+			sortedEntries = append(sortedEntries, &filteredChannel.Entries[i])
+		}
+		sort.Slice(sortedEntries, func(i, j int) bool {
+			// Sort by decreasing version, and then by decreasing release: greater version comes first
+			a, b := sortedEntries[i].Name, sortedEntries[j].Name
+			if versionMap[a].NE(versionMap[b]) {
+				return versionMap[a].GT(versionMap[b])
+			}
+			return releaseMap[a].Compare(releaseMap[b]) > 0
+		})
+
+		skippedEntities := sets.Set[string]{}
+
+		for _, ce := range sortedEntries {
+			entryID := fmt.Sprintf("%s-%s", channelID, ce.Name)
+			node := graphNode{
+				id:         entryID,
+				name:       ce.Name,
+				deprecated: depByBundle.Has(ce.Name),
+				skipped:    skippedEntities.Has(ce.Name),
+			}
+
+			for _, s := range ce.Skips {
+				edge := graphEdge{fromID: fmt.Sprintf("%s-%s", channelID, s), fromName: s, edgeType: skipGraphEdge}
+				if opts.DrawV0Semantics {
+					if skippedEntities.Has(s) {
+						edge.skipped = true
+					} else {
+						skippedEntities.Insert(s)
+					}
+				}
+				node.edges = append(node.edges, edge)
+			}
+			if len(ce.SkipRange) > 0 {
+				skipRange, err := semver.ParseRange(ce.SkipRange)
+				if err == nil {
+					for _, edgeName := range filteredChannel.Entries {
+						// a bundle never skips itself, even if its version is in its skipRange
+						if edgeName.Name != ce.Name && skipRange(versionMap[edgeName.Name]) {
+							node.edges = append(node.edges, graphEdge{
+								fromID:    fmt.Sprintf("%s-%s", channelID, edgeName.Name),
+								fromName:  edgeName.Name,
+								edgeType:  skipRangeGraphEdge,
+								skipRange: ce.SkipRange,
+								skipped:   opts.DrawV0Semantics && skippedEntities.Has(ce.Name),
+							})
+						}
+					}
+				} else {
+					fmt.Fprintf(os.Stderr, "warning: ignoring invalid SkipRange for package/edge %q/%q: %v\n", c.Package, ce.Name, err)
+				}
+			}
+			// have to process replaces last, because applicablity can be impacted by skips
+			if len(ce.Replaces) > 0 {
+				node.edges = append(node.edges, graphEdge{
+					fromID:   fmt.Sprintf("%s-%s", channelID, ce.Replaces),
+					fromName: ce.Replaces,
+					edgeType: replaceGraphEdge,
+					skipped:  opts.DrawV0Semantics && skippedEntities.Has(ce.Name),
+				})
+			}
+			gc.nodes = append(gc.nodes, node)
+		}
+		g.channels = append(g.channels, gc)
+	}
+	return g, nil
+}
@@ -0,0 +1,108 @@
+package declcfg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"io"
+)
+
+//go:embed write_html.tmpl
+var htmlGraphTemplate string
+
+var htmlGraph = template.Must(template.New("graph").Parse(htmlGraphTemplate))
+
+// HTMLWriter writes the channel edges of a declarative config as a
+// self-contained HTML page, which draws the upgrade graph of each channel in
+// the browser. The page needs no network access, so it can be shared as a
+// single file.
+type HTMLWriter struct {
+	GraphOptions
+}
+
+func NewHTMLWriter(opts GraphOptions) *HTMLWriter {
+	return &HTMLWriter{GraphOptions: opts}
+}
+
+type htmlGraphPackage struct {
+	Name       string             `json:"name"`
+	Deprecated bool               `json:"deprecated,omitempty"`
+	Channels   []htmlGraphChannel `json:"channels"`
+}
+
+type htmlGraphChannel struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Deprecated bool            `json:"deprecated,omitempty"`
+	Nodes      []htmlGraphNode `json:"nodes"`
+}
+
+type htmlGraphNode struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Deprecated bool            `json:"deprecated,omitempty"`
+	Skipped    bool            `json:"skipped,omitempty"`
+	Edges      []htmlGraphEdge `json:"edges,omitempty"`
+}
+
+type htmlGraphEdge struct {
+	From      string `json:"from"`
+	FromName  string `json:"fromName"`
+	Type      string `json:"type"`
+	SkipRange string `json:"skipRange,omitempty"`
+	Skipped   bool   `json:"skipped,omitempty"`
+}
+
+// WriteChannels writes the upgrade graph of cfg as an HTML page. The page
+// contains the same nodes and edges as the MermaidWriter output, with
+// deprecated packages, channels and bundles highlighted, and a toggle per
+// channel to show or hide its graph.
+func (writer *HTMLWriter) WriteChannels(cfg DeclarativeConfig, out io.Writer) error {
+	g, err := buildChannelGraph(cfg, writer.GraphOptions)
+	if err != nil {
+		return err
+	}
+
+	var pkgs []htmlGraphPackage
+	pkgIndex := map[string]int{}
+	for _, c := range g.channels {
+		i, ok := pkgIndex[c.pkg]
+		if !ok {
+			i = len(pkgs)
+			pkgIndex[c.pkg] = i
+			pkgs = append(pkgs, htmlGraphPackage{Name: c.pkg, Deprecated: g.deprecatedPackages.Has(c.pkg)})
+		}
+		hc := htmlGraphChannel{ID: c.id, Name: c.name, Deprecated: c.deprecated, Nodes: []htmlGraphNode{}}
+		for _, n := range c.nodes {
+			hn := htmlGraphNode{ID: n.id, Name: n.name, Deprecated: n.deprecated, Skipped: n.skipped}
+			for _, e := range n.edges {
+				hn.Edges = append(hn.Edges, htmlGraphEdge{
+					From:      e.fromID,
+					FromName:  e.fromName,
+					Type:      string(e.edgeType),
+					SkipRange: e.skipRange,
+					Skipped:   e.skipped,
+				})
+			}
+			hc.Nodes = append(hc.Nodes, hn)
+		}
+		pkgs[i].Channels = append(pkgs[i].Channels, hc)
+	}
+	if pkgs == nil {
+		pkgs = []htmlGraphPackage{}
+	}
+
+	// json.Marshal escapes '<', '>' and '&', so the data is safe to embed in
+	// a script element.
+	data, err := json.Marshal(pkgs)
+	if err != nil {
+		return err
+	}
+	return htmlGraph.Execute(out, struct {
+		Graph           template.JS
+		DrawV0Semantics bool
+	}{
+		Graph:           template.JS(data),
+		DrawV0Semantics: writer.DrawV0Semantics,
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/property"
 )
 
 func TestWriteJSON(t *testing.T) {
@@ -611,6 +613,40 @@ class anakin-dark-anakin.v0.1.0 skipped
       boba-fett-mando-boba-fett.v1.0.0["boba-fett.v1.0.0"]
     end
   end
+`,
+		},
+		{
+			name: "SuccessReleasesAndSkipRange",
+			cfg: DeclarativeConfig{
+				Packages: []Package{{Schema: SchemaPackage, Name: "a", DefaultChannel: "stable-v1"}},
+				Channels: []Channel{{Schema: SchemaChannel, Package: "a", Name: "stable-v1", Entries: []ChannelEntry{
+					{Name: "a-v1.0.0-1"},
+					{Name: "a-v1.0.0-2", Replaces: "a-v1.0.0-1"},
+					{Name: "a-v1.0.1", Replaces: "a-v1.0.0-2", SkipRange: ">=1.0.0 <1.0.1"},
+				}}},
+				Bundles: []Bundle{
+					{Schema: SchemaBundle, Package: "a", Name: "a-v1.0.0-2", Properties: []property.Property{property.MustBuildPackageRelease("a", "1.0.0", "2")}},
+					{Schema: SchemaBundle, Package: "a", Name: "a-v1.0.1", Properties: []property.Property{property.MustBuildPackage("a", "1.0.1")}},
+					{Schema: SchemaBundle, Package: "a", Name: "a-v1.0.0-1", Properties: []property.Property{property.MustBuildPackageRelease("a", "1.0.0", "1")}},
+				},
+			},
+			expected: `graph LR
+  classDef deprecated fill:#E8960F
+  classDef skipped stroke:#FF0000,stroke-width:4px
+  classDef deprecatedskipped fill:#E8960F,stroke:#FF0000,stroke-width:4px
+  %% package "a"
+  subgraph "a"
+    %% channel "stable-v1"
+    subgraph a-stable-v1["stable-v1"]
+      a-stable-v1-a-v1.0.1["a-v1.0.1"]
+      a-stable-v1-a-v1.0.0-1["a-v1.0.0-1"]-- "skipRange(>=1.0.0 <1.0.1)" --> a-stable-v1-a-v1.0.1["a-v1.0.1"]
+      a-stable-v1-a-v1.0.0-2["a-v1.0.0-2"]-- "skipRange(>=1.0.0 <1.0.1)" --> a-stable-v1-a-v1.0.1["a-v1.0.1"]
+      a-stable-v1-a-v1.0.0-2["a-v1.0.0-2"]-- replace --> a-stable-v1-a-v1.0.1["a-v1.0.1"]
+      a-stable-v1-a-v1.0.0-2["a-v1.0.0-2"]
+      a-stable-v1-a-v1.0.0-1["a-v1.0.0-1"]-- replace --> a-stable-v1-a-v1.0.0-2["a-v1.0.0-2"]
+      a-stable-v1-a-v1.0.0-1["a-v1.0.0-1"]
+    end
+  end
 `,
 		},
 	}
@@ -625,6 +661,153 @@ class anakin-dark-anakin.v0.1.0 skipped
 	}
 }
 
+func TestWriteDotChannels(t *testing.T) {
+	type spec struct {
+		name     string
+		cfg      DeclarativeConfig
+		opts     GraphOptions
+		expected string
+	}
+	specs := []spec{
+		{
+			name: "SuccessNoFilters",
+			cfg:  buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true}),
+			opts: GraphOptions{DrawV0Semantics: true},
+			expected: `digraph "catalog" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fillcolor="#FFFFFF"];
+  subgraph "cluster_anakin" {
+    label="anakin";
+    style=filled;
+    fillcolor="#989695";
+    subgraph "cluster_anakin-dark" {
+      label="dark";
+      "anakin-dark-anakin.v0.1.1" [label="anakin.v0.1.1"];
+      "anakin-dark-anakin.v0.1.0" [label="anakin.v0.1.0", color="#FF0000", penwidth=4];
+      "anakin-dark-anakin.v0.0.1" [label="anakin.v0.0.1", fillcolor="#E8960F"];
+      "anakin-dark-anakin.v0.1.0" -> "anakin-dark-anakin.v0.1.1" [label="skip", style=dashed];
+      "anakin-dark-anakin.v0.0.1" -> "anakin-dark-anakin.v0.1.1" [label="replace"];
+      "anakin-dark-anakin.v0.0.1" -> "anakin-dark-anakin.v0.1.0" [label="replace", color="#FF0000", penwidth=3];
+    }
+    subgraph "cluster_anakin-light" {
+      label="light";
+      style=filled;
+      fillcolor="#DCD0FF";
+      "anakin-light-anakin.v0.1.0" [label="anakin.v0.1.0"];
+      "anakin-light-anakin.v0.0.1" [label="anakin.v0.0.1", fillcolor="#E8960F"];
+      "anakin-light-anakin.v0.0.1" -> "anakin-light-anakin.v0.1.0" [label="replace"];
+    }
+  }
+  subgraph "cluster_boba-fett" {
+    label="boba-fett";
+    subgraph "cluster_boba-fett-mando" {
+      label="mando";
+      "boba-fett-mando-boba-fett.v2.0.0" [label="boba-fett.v2.0.0"];
+      "boba-fett-mando-boba-fett.v1.0.0" [label="boba-fett.v1.0.0"];
+      "boba-fett-mando-boba-fett.v1.0.0" -> "boba-fett-mando-boba-fett.v2.0.0" [label="replace"];
+    }
+  }
+}
+`,
+		},
+		{
+			name: "SuccessPackageNameFilter",
+			cfg:  buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true}),
+			opts: GraphOptions{SpecifiedPackageName: "boba-fett"},
+			expected: `digraph "catalog" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fillcolor="#FFFFFF"];
+  subgraph "cluster_boba-fett" {
+    label="boba-fett";
+    subgraph "cluster_boba-fett-mando" {
+      label="mando";
+      "boba-fett-mando-boba-fett.v2.0.0" [label="boba-fett.v2.0.0"];
+      "boba-fett-mando-boba-fett.v1.0.0" [label="boba-fett.v1.0.0"];
+      "boba-fett-mando-boba-fett.v1.0.0" -> "boba-fett-mando-boba-fett.v2.0.0" [label="replace"];
+    }
+  }
+}
+`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := NewDotWriter(s.opts)
+			err := writer.WriteChannels(s.cfg, &buf)
+			require.NoError(t, err)
+			require.Equal(t, s.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteHTMLChannels(t *testing.T) {
+	type spec struct {
+		name        string
+		cfg         DeclarativeConfig
+		opts        GraphOptions
+		expected    []string
+		notExpected []string
+		assertion   require.ErrorAssertionFunc
+	}
+	specs := []spec{
+		{
+			name: "SuccessNoFilters",
+			cfg:  buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true}),
+			opts: GraphOptions{DrawV0Semantics: true},
+			expected: []string{
+				`{"name":"anakin","deprecated":true,"channels":[{"id":"anakin-dark","name":"dark","nodes":[`,
+				`{"id":"anakin-dark-anakin.v0.1.0","name":"anakin.v0.1.0","skipped":true,"edges":[{"from":"anakin-dark-anakin.v0.0.1","fromName":"anakin.v0.0.1","type":"replace","skipped":true}]}`,
+				`{"id":"anakin-light","name":"light","deprecated":true,"nodes":[`,
+				`{"name":"boba-fett","channels":[`,
+				"never taken by OLMv0",
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "SuccessPackageNameFilter",
+			cfg:  buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true}),
+			opts: GraphOptions{SpecifiedPackageName: "boba-fett"},
+			expected: []string{
+				`[{"name":"boba-fett","channels":[{"id":"boba-fett-mando","name":"mando","nodes":[`,
+			},
+			notExpected: []string{"anakin", "never taken by OLMv0"},
+			assertion:   require.NoError,
+		},
+		{
+			name: "SuccessEscapesScript",
+			cfg: DeclarativeConfig{
+				Packages: []Package{{Schema: SchemaPackage, Name: "a", DefaultChannel: "</script><script>alert(1)</script>"}},
+				Channels: []Channel{{Schema: SchemaChannel, Package: "a", Name: "</script><script>alert(1)</script>", Entries: []ChannelEntry{{Name: "a.v1.0.0"}}}},
+				Bundles:  []Bundle{{Schema: SchemaBundle, Package: "a", Name: "a.v1.0.0", Properties: []property.Property{property.MustBuildPackage("a", "1.0.0")}}},
+			},
+			expected:    []string{`"name":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"`},
+			notExpected: []string{"<script>alert(1)"},
+			assertion:   require.NoError,
+		},
+		{
+			name:      "FailUnknownMinEdge",
+			cfg:       buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true}),
+			opts:      GraphOptions{MinEdgeName: "anakin.v9.9.9"},
+			assertion: require.Error,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := NewHTMLWriter(s.opts)
+			err := writer.WriteChannels(s.cfg, &buf)
+			s.assertion(t, err)
+			for _, e := range s.expected {
+				require.Contains(t, buf.String(), e)
+			}
+			for _, e := range s.notExpected {
+				require.NotContains(t, buf.String(), e)
+			}
+		})
+	}
+}
+
 func TestWriteFS(t *testing.T) {
 	cfg := buildValidDeclarativeConfig(validDeclarativeConfigSpec{IncludeUnrecognized: true, IncludeDeprecations: true})
 
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	APIVersionV1Alpha1 = "olm.operatorframework.io/filter/v1alpha1"
+	KindConfiguration  = "FilterConfiguration"
+)
+
+// Configuration selects the subset of a catalog to keep.
+//
+// Example:
+//
+//	apiVersion: olm.operatorframework.io/filter/v1alpha1
+//	kind: FilterConfiguration
+//	packages:
+//	  - name: foo
+//	    defaultChannel: stable
+//	    channels:
+//	      - name: stable
+//	        versionRange: ">=1.2.0 <2.0.0"
+//	      - name: candidate
+//	  - name: bar
+type Configuration struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Packages   []Package `json:"packages"`
+}
+
+type Package struct {
+	Name string `json:"name"`
+	// DefaultChannel overrides the default channel of the package. It must
+	// be one of the selected channels.
+	DefaultChannel string `json:"defaultChannel,omitempty"`
+	// Channels selects channels of the package. If empty, all channels are
+	// kept in full.
+	Channels []Channel `json:"channels,omitempty"`
+}
+
+type Channel struct {
+	Name string `json:"name"`
+	// VersionRange is a semver range of the bundles to keep in the channel,
+	// e.g. ">=1.2.0 <2.0.0". If empty, the channel is kept in full.
+	VersionRange string `json:"versionRange,omitempty"`
+
+	versionRange semver.Range
+}
+
+// LoadConfiguration reads a YAML or JSON filter configuration.
+func LoadConfiguration(r io.Reader) (*Configuration, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Configuration{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse filter configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration and parses its version ranges.
+func (c *Configuration) Validate() error {
+	if c.APIVersion != APIVersionV1Alpha1 || c.Kind != KindConfiguration {
+		return fmt.Errorf("invalid filter configuration: unsupported apiVersion %q and kind %q, expected %q and %q", c.APIVersion, c.Kind, APIVersionV1Alpha1, KindConfiguration)
+	}
+	if len(c.Packages) == 0 {
+		return fmt.Errorf("invalid filter configuration: no packages selected")
+	}
+	pkgNames := sets.New[string]()
+	for i := range c.Packages {
+		p := &c.Packages[i]
+		if p.Name == "" {
+			return fmt.Errorf("invalid filter configuration: package %d has no name", i)
+		}
+		if pkgNames.Has(p.Name) {
+			return fmt.Errorf("invalid filter configuration: duplicate package %q", p.Name)
+		}
+		pkgNames.Insert(p.Name)
+
+		chNames := sets.New[string]()
+		for j := range p.Channels {
+			ch := &p.Channels[j]
+			if ch.Name == "" {
+				return fmt.Errorf("invalid filter configuration: package %q: channel %d has no name", p.Name, j)
+			}
+			if chNames.Has(ch.Name) {
+				return fmt.Errorf("invalid filter configuration: package %q: duplicate channel %q", p.Name, ch.Name)
+			}
+			chNames.Insert(ch.Name)
+			if ch.VersionRange == "" {
+				continue
+			}
+			r, err := semver.ParseRange(ch.VersionRange)
+			if err != nil {
+				return fmt.Errorf("invalid filter configuration: package %q, channel %q: invalid version range %q: %v", p.Name, ch.Name, ch.VersionRange, err)
+			}
+			ch.versionRange = r
+		}
+		if p.DefaultChannel != "" && len(p.Channels) > 0 && !chNames.Has(p.DefaultChannel) {
+			return fmt.Errorf("invalid filter configuration: package %q: default channel %q is not selected", p.Name, p.DefaultChannel)
+		}
+	}
+	return nil
+}
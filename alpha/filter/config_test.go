@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfiguration(t *testing.T) {
+	type spec struct {
+		name          string
+		input         string
+		expected      *Configuration
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name: "Success/Valid",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+    defaultChannel: stable
+    channels:
+      - name: stable
+        versionRange: ">=1.0.0 <2.0.0"
+      - name: candidate
+  - name: bar
+`,
+			expected: &Configuration{
+				APIVersion: APIVersionV1Alpha1,
+				Kind:       KindConfiguration,
+				Packages: []Package{
+					{
+						Name:           "foo",
+						DefaultChannel: "stable",
+						Channels: []Channel{
+							{Name: "stable", VersionRange: ">=1.0.0 <2.0.0"},
+							{Name: "candidate"},
+						},
+					},
+					{Name: "bar"},
+				},
+			},
+		},
+		{
+			name: "Fail/UnsupportedAPIVersion",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v2
+kind: FilterConfiguration
+packages:
+  - name: foo
+`,
+			expectedError: `invalid filter configuration: unsupported apiVersion "olm.operatorframework.io/filter/v2" and kind "FilterConfiguration", expected "olm.operatorframework.io/filter/v1alpha1" and "FilterConfiguration"`,
+		},
+		{
+			name: "Fail/UnknownField",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+    version: 1.0.0
+`,
+			expectedError: `parse filter configuration: error unmarshaling JSON: while decoding JSON: json: unknown field "version"`,
+		},
+		{
+			name: "Fail/NoPackages",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+`,
+			expectedError: "invalid filter configuration: no packages selected",
+		},
+		{
+			name: "Fail/DuplicatePackage",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+  - name: foo
+`,
+			expectedError: `invalid filter configuration: duplicate package "foo"`,
+		},
+		{
+			name: "Fail/DuplicateChannel",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+    channels:
+      - name: stable
+      - name: stable
+`,
+			expectedError: `invalid filter configuration: package "foo": duplicate channel "stable"`,
+		},
+		{
+			name: "Fail/InvalidVersionRange",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+    channels:
+      - name: stable
+        versionRange: ">=one"
+`,
+			expectedError: `invalid filter configuration: package "foo", channel "stable": invalid version range ">=one": Could not get version from string: ">=one"`,
+		},
+		{
+			name: "Fail/DefaultChannelNotSelected",
+			input: `
+apiVersion: olm.operatorframework.io/filter/v1alpha1
+kind: FilterConfiguration
+packages:
+  - name: foo
+    defaultChannel: fast
+    channels:
+      - name: stable
+`,
+			expectedError: `invalid filter configuration: package "foo": default channel "fast" is not selected`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg, err := LoadConfiguration(strings.NewReader(s.input))
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for i := range cfg.Packages {
+				for j := range cfg.Packages[i].Channels {
+					cfg.Packages[i].Channels[j].versionRange = nil
+				}
+			}
+			require.Equal(t, s.expected, cfg)
+		})
+	}
+}
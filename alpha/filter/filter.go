@@ -0,0 +1,260 @@
+package filter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+)
+
+// FilterCatalog returns the subset of fbc selected by cfg. The result is
+// validated, and keeps the deprecations and custom schema blobs of the
+// selected objects.
+func FilterCatalog(fbc declcfg.DeclarativeConfig, cfg Configuration) (*declcfg.DeclarativeConfig, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	pkgNames := make([]string, 0, len(cfg.Packages))
+	for _, p := range cfg.Packages {
+		pkgNames = append(pkgNames, p.Name)
+	}
+	// Packages that are not selected are removed first, so that they do not
+	// need to be valid.
+	pruned, err := declcfg.PrunePackages(fbc, pkgNames)
+	if err != nil {
+		return nil, err
+	}
+	m, err := declcfg.ConvertToModel(*pruned)
+	if err != nil {
+		return nil, err
+	}
+	if err := FilterModel(m, cfg); err != nil {
+		return nil, err
+	}
+
+	out := declcfg.ConvertFromModel(m)
+	out.Deprecations = filterDeprecations(pruned.Deprecations, m)
+	out.Others = pruned.Others
+	return &out, nil
+}
+
+// FilterModel removes everything that cfg does not select from m, and
+// validates the result.
+//
+// Within a channel with a version range, the bundles in the range are kept,
+// along with the bundles on the replaces chain between them, so that the
+// upgrade graph stays connected. Bundles in the range that a kept bundle
+// skips, or covers with its skipRange, are kept too. Other bundles in the
+// range, which are only reachable through bundles that are filtered out, are
+// removed. If the default channel
+// of a package is filtered out, the selected channel whose head has the
+// highest version becomes the default channel.
+func FilterModel(m model.Model, cfg Configuration) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	selected := map[string]Package{}
+	for _, p := range cfg.Packages {
+		if _, ok := m[p.Name]; !ok {
+			return fmt.Errorf("package %q not found in catalog", p.Name)
+		}
+		selected[p.Name] = p
+	}
+	for name := range m {
+		if _, ok := selected[name]; !ok {
+			delete(m, name)
+		}
+	}
+	for name, p := range selected {
+		if err := filterPackage(m[name], p); err != nil {
+			return fmt.Errorf("package %q: %v", name, err)
+		}
+	}
+	return m.Validate()
+}
+
+func filterPackage(mpkg *model.Package, p Package) error {
+	if len(p.Channels) > 0 {
+		keep := sets.New[string]()
+		for _, ch := range p.Channels {
+			mch, ok := mpkg.Channels[ch.Name]
+			if !ok {
+				return fmt.Errorf("channel %q not found", ch.Name)
+			}
+			if ch.VersionRange != "" {
+				if err := filterChannel(mch, ch); err != nil {
+					return fmt.Errorf("channel %q: %v", ch.Name, err)
+				}
+			}
+			keep.Insert(ch.Name)
+		}
+		for name := range mpkg.Channels {
+			if !keep.Has(name) {
+				delete(mpkg.Channels, name)
+			}
+		}
+	}
+
+	if p.DefaultChannel != "" {
+		ch, ok := mpkg.Channels[p.DefaultChannel]
+		if !ok {
+			return fmt.Errorf("default channel %q not found", p.DefaultChannel)
+		}
+		mpkg.DefaultChannel = ch
+		return nil
+	}
+	if mpkg.DefaultChannel != nil {
+		if _, ok := mpkg.Channels[mpkg.DefaultChannel.Name]; ok {
+			return nil
+		}
+	}
+	return selectDefaultChannel(mpkg)
+}
+
+// filterChannel keeps the bundles of mch that are in the version range of
+// ch, and those needed to connect them on the channel's replaces chain.
+func filterChannel(mch *model.Channel, ch Channel) error {
+	head, err := mch.Head()
+	if err != nil {
+		return err
+	}
+
+	// Walk the replaces chain from the head, and find the highest and lowest
+	// bundles on it that are in range.
+	var chain []*model.Bundle
+	first, last := -1, -1
+	visited := sets.New[string]()
+	for b := head; b != nil && !visited.Has(b.Name); b = mch.Bundles[b.Replaces] {
+		visited.Insert(b.Name)
+		if ch.versionRange(b.Version) {
+			if first < 0 {
+				first = len(chain)
+			}
+			last = len(chain)
+		}
+		chain = append(chain, b)
+	}
+	if first < 0 {
+		return fmt.Errorf("no bundles on the upgrade graph match version range %q", ch.VersionRange)
+	}
+
+	keep := sets.New[string]()
+	for _, b := range chain[first : last+1] {
+		keep.Insert(b.Name)
+	}
+	// Bundles in range that are off the replaces chain are kept if a kept
+	// bundle skips them; otherwise, nothing would upgrade from them.
+	for _, b := range chain[first : last+1] {
+		for _, skip := range b.Skips {
+			if sb, ok := mch.Bundles[skip]; ok && ch.versionRange(sb.Version) {
+				keep.Insert(skip)
+			}
+		}
+	}
+	// Bundles in range that a kept bundle covers with its skipRange can also
+	// upgrade to it, so they are kept too.
+	skippedBy := map[string]*model.Bundle{}
+	for _, b := range chain[first : last+1] {
+		if b.SkipRange == "" {
+			continue
+		}
+		skipRange, err := semver.ParseRange(b.SkipRange)
+		if err != nil {
+			return fmt.Errorf("bundle %q: invalid skipRange %q: %v", b.Name, b.SkipRange, err)
+		}
+		for _, sb := range mch.Bundles {
+			if !keep.Has(sb.Name) && skipRange(sb.Version) && ch.versionRange(sb.Version) {
+				keep.Insert(sb.Name)
+				if _, ok := skippedBy[sb.Name]; !ok {
+					skippedBy[sb.Name] = b
+				}
+			}
+		}
+	}
+	for name := range mch.Bundles {
+		if !keep.Has(name) {
+			delete(mch.Bundles, name)
+		}
+	}
+	// A bundle kept for a skipRange alone was replaced or skipped by bundles
+	// that are filtered out, and would become a second channel head. The
+	// bundle covering it skips it instead, which is an upgrade edge its
+	// skipRange already provides.
+	incoming := sets.New[string]()
+	for _, b := range mch.Bundles {
+		incoming.Insert(b.Replaces)
+		incoming.Insert(b.Skips...)
+	}
+	for _, name := range slices.Sorted(maps.Keys(skippedBy)) {
+		if !incoming.Has(name) {
+			b := skippedBy[name]
+			b.Skips = append(b.Skips, name)
+		}
+	}
+	return nil
+}
+
+// selectDefaultChannel sets the default channel of mpkg to the channel whose
+// head has the highest version, breaking ties by channel name.
+func selectDefaultChannel(mpkg *model.Package) error {
+	names := make([]string, 0, len(mpkg.Channels))
+	for name := range mpkg.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var best *model.Channel
+	var bestHead *model.Bundle
+	for _, name := range names {
+		ch := mpkg.Channels[name]
+		head, err := ch.Head()
+		if err != nil {
+			return fmt.Errorf("channel %q: %v", name, err)
+		}
+		if best == nil || head.Version.GT(bestHead.Version) {
+			best, bestHead = ch, head
+		}
+	}
+	if best == nil {
+		return fmt.Errorf("no channels selected")
+	}
+	mpkg.DefaultChannel = best
+	return nil
+}
+
+// filterDeprecations returns the deprecations of the packages in m, without
+// the entries that refer to channels or bundles that are not in m.
+func filterDeprecations(deprecations []declcfg.Deprecation, m model.Model) []declcfg.Deprecation {
+	var out []declcfg.Deprecation
+	for _, d := range deprecations {
+		mpkg, ok := m[d.Package]
+		if !ok {
+			continue
+		}
+		d.Entries = slices.DeleteFunc(slices.Clone(d.Entries), func(e declcfg.DeprecationEntry) bool {
+			switch e.Reference.Schema {
+			case declcfg.SchemaChannel:
+				_, ok := mpkg.Channels[e.Reference.Name]
+				return !ok
+			case declcfg.SchemaBundle:
+				for _, ch := range mpkg.Channels {
+					if _, ok := ch.Bundles[e.Reference.Name]; ok {
+						return false
+					}
+				}
+				return true
+			}
+			return false
+		})
+		if len(d.Entries) > 0 {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+func testBundle(pkg, version string) declcfg.Bundle {
+	return declcfg.Bundle{
+		Schema:  declcfg.SchemaBundle,
+		Name:    fmt.Sprintf("%s.v%s", pkg, version),
+		Package: pkg,
+		Image:   fmt.Sprintf("quay.io/%s/bundle:v%s", pkg, version),
+		Properties: []property.Property{
+			property.MustBuildPackage(pkg, version),
+		},
+	}
+}
+
+// testCatalog returns a catalog with two packages:
+//
+//	foo/stable:    1.0.0 <- 1.1.0 <- 1.2.0 (skipRange >=1.1.0 <1.2.0) <- 2.0.0 (skips 1.1.1) <- 2.1.0
+//	foo/candidate: 2.0.0 <- 2.1.0 <- 3.0.0-rc.1
+//	bar/alpha:     0.1.0
+func testCatalog() declcfg.DeclarativeConfig {
+	return declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{
+			{Schema: declcfg.SchemaPackage, Name: "foo", DefaultChannel: "stable"},
+			{Schema: declcfg.SchemaPackage, Name: "bar", DefaultChannel: "alpha"},
+		},
+		Channels: []declcfg.Channel{
+			{Schema: declcfg.SchemaChannel, Package: "foo", Name: "stable", Entries: []declcfg.ChannelEntry{
+				{Name: "foo.v1.0.0"},
+				{Name: "foo.v1.1.0", Replaces: "foo.v1.0.0"},
+				{Name: "foo.v1.1.1", Replaces: "foo.v1.1.0"},
+				{Name: "foo.v1.2.0", Replaces: "foo.v1.1.0", SkipRange: ">=1.1.0 <1.2.0"},
+				{Name: "foo.v2.0.0", Replaces: "foo.v1.2.0", Skips: []string{"foo.v1.1.1"}},
+				{Name: "foo.v2.1.0", Replaces: "foo.v2.0.0"},
+			}},
+			{Schema: declcfg.SchemaChannel, Package: "foo", Name: "candidate", Entries: []declcfg.ChannelEntry{
+				{Name: "foo.v2.0.0"},
+				{Name: "foo.v2.1.0", Replaces: "foo.v2.0.0"},
+				{Name: "foo.v3.0.0-rc.1", Replaces: "foo.v2.1.0"},
+			}},
+			{Schema: declcfg.SchemaChannel, Package: "bar", Name: "alpha", Entries: []declcfg.ChannelEntry{
+				{Name: "bar.v0.1.0"},
+			}},
+		},
+		Bundles: []declcfg.Bundle{
+			testBundle("foo", "1.0.0"),
+			testBundle("foo", "1.1.0"),
+			testBundle("foo", "1.1.1"),
+			testBundle("foo", "1.2.0"),
+			testBundle("foo", "2.0.0"),
+			testBundle("foo", "2.1.0"),
+			testBundle("foo", "3.0.0-rc.1"),
+			testBundle("bar", "0.1.0"),
+		},
+		Deprecations: []declcfg.Deprecation{
+			{Schema: declcfg.SchemaDeprecation, Package: "foo", Entries: []declcfg.DeprecationEntry{
+				{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: "candidate"}, Message: "use stable"},
+				{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaBundle, Name: "foo.v1.0.0"}, Message: "upgrade"},
+			}},
+		},
+		Others: []declcfg.Meta{
+			{Schema: "custom", Package: "foo", Blob: json.RawMessage(`{"schema":"custom","package":"foo"}`)},
+			{Schema: "custom", Package: "bar", Blob: json.RawMessage(`{"schema":"custom","package":"bar"}`)},
+		},
+	}
+}
+
+func TestFilterCatalog(t *testing.T) {
+	type spec struct {
+		name                  string
+		cfg                   Configuration
+		expectedDefaults      map[string]string
+		expectedEntries       map[string][]string
+		expectedSkips         map[string][]string
+		expectedBundles       []string
+		expectedDeprecations  []string
+		expectedOtherPackages []string
+		expectedError         string
+	}
+	cfg := func(packages ...Package) Configuration {
+		return Configuration{APIVersion: APIVersionV1Alpha1, Kind: KindConfiguration, Packages: packages}
+	}
+	specs := []spec{
+		{
+			name:             "Success/WholePackage",
+			cfg:              cfg(Package{Name: "bar"}),
+			expectedDefaults: map[string]string{"bar": "alpha"},
+			expectedEntries: map[string][]string{
+				"bar/alpha": {"bar.v0.1.0"},
+			},
+			expectedBundles:       []string{"bar.v0.1.0"},
+			expectedOtherPackages: []string{"bar"},
+		},
+		{
+			name: "Success/VersionRangeKeepsGraphConnected",
+			cfg: cfg(Package{Name: "foo", Channels: []Channel{
+				{Name: "stable", VersionRange: ">=1.1.0 <2.0.0 || >=2.1.0"},
+			}}),
+			expectedDefaults: map[string]string{"foo": "stable"},
+			expectedEntries: map[string][]string{
+				"foo/stable": {"foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0", "foo.v2.0.0", "foo.v2.1.0"},
+			},
+			expectedBundles:       []string{"foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0", "foo.v2.0.0", "foo.v2.1.0"},
+			expectedOtherPackages: []string{"foo"},
+		},
+		{
+			name: "Success/VersionRangeDropsUnreachableSkippedBundle",
+			cfg: cfg(Package{Name: "foo", Channels: []Channel{
+				{Name: "stable", VersionRange: "<1.2.0"},
+			}}),
+			expectedDefaults: map[string]string{"foo": "stable"},
+			expectedEntries: map[string][]string{
+				"foo/stable": {"foo.v1.0.0", "foo.v1.1.0"},
+			},
+			expectedBundles:       []string{"foo.v1.0.0", "foo.v1.1.0"},
+			expectedDeprecations:  []string{"olm.bundle/foo.v1.0.0"},
+			expectedOtherPackages: []string{"foo"},
+		},
+		{
+			name: "Success/VersionRangeKeepsBundleInSkipRange",
+			cfg: cfg(Package{Name: "foo", Channels: []Channel{
+				{Name: "stable", VersionRange: ">=1.1.0 <2.0.0"},
+			}}),
+			expectedDefaults: map[string]string{"foo": "stable"},
+			expectedEntries: map[string][]string{
+				"foo/stable": {"foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0"},
+			},
+			expectedSkips: map[string][]string{
+				"foo.v1.2.0": {"foo.v1.1.1"},
+			},
+			expectedBundles:       []string{"foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0"},
+			expectedOtherPackages: []string{"foo"},
+		},
+		{
+			name: "Success/DefaultChannelRepointed",
+			cfg: cfg(Package{Name: "foo", Channels: []Channel{
+				{Name: "candidate", VersionRange: ">=2.1.0-0"},
+			}}),
+			expectedDefaults: map[string]string{"foo": "candidate"},
+			expectedEntries: map[string][]string{
+				"foo/candidate": {"foo.v2.1.0", "foo.v3.0.0-rc.1"},
+			},
+			expectedBundles:       []string{"foo.v2.1.0", "foo.v3.0.0-rc.1"},
+			expectedDeprecations:  []string{"olm.channel/candidate"},
+			expectedOtherPackages: []string{"foo"},
+		},
+		{
+			name: "Success/DefaultChannelOverridden",
+			cfg: cfg(
+				Package{Name: "foo", DefaultChannel: "candidate", Channels: []Channel{{Name: "stable"}, {Name: "candidate"}}},
+				Package{Name: "bar"},
+			),
+			expectedDefaults: map[string]string{"foo": "candidate", "bar": "alpha"},
+			expectedEntries: map[string][]string{
+				"foo/candidate": {"foo.v2.0.0", "foo.v2.1.0", "foo.v3.0.0-rc.1"},
+				"foo/stable":    {"foo.v1.0.0", "foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0", "foo.v2.0.0", "foo.v2.1.0"},
+				"bar/alpha":     {"bar.v0.1.0"},
+			},
+			expectedBundles:       []string{"bar.v0.1.0", "foo.v1.0.0", "foo.v1.1.0", "foo.v1.1.1", "foo.v1.2.0", "foo.v2.0.0", "foo.v2.1.0", "foo.v3.0.0-rc.1"},
+			expectedDeprecations:  []string{"olm.channel/candidate", "olm.bundle/foo.v1.0.0"},
+			expectedOtherPackages: []string{"foo", "bar"},
+		},
+		{
+			name:          "Fail/UnknownPackage",
+			cfg:           cfg(Package{Name: "baz"}),
+			expectedError: "packages not found in catalog: baz",
+		},
+		{
+			name:          "Fail/UnknownChannel",
+			cfg:           cfg(Package{Name: "foo", Channels: []Channel{{Name: "fast"}}}),
+			expectedError: `package "foo": channel "fast" not found`,
+		},
+		{
+			name: "Fail/NoBundlesInRange",
+			cfg: cfg(Package{Name: "foo", Channels: []Channel{
+				{Name: "stable", VersionRange: ">=5.0.0"},
+			}}),
+			expectedError: `package "foo": channel "stable": no bundles on the upgrade graph match version range ">=5.0.0"`,
+		},
+		{
+			name:          "Fail/InvalidConfiguration",
+			cfg:           Configuration{Packages: []Package{{Name: "foo"}}},
+			expectedError: `invalid filter configuration: unsupported apiVersion "" and kind "", expected "olm.operatorframework.io/filter/v1alpha1" and "FilterConfiguration"`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			out, err := FilterCatalog(testCatalog(), s.cfg)
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			defaults := map[string]string{}
+			for _, p := range out.Packages {
+				defaults[p.Name] = p.DefaultChannel
+			}
+			require.Equal(t, s.expectedDefaults, defaults)
+
+			entries := map[string][]string{}
+			for _, ch := range out.Channels {
+				key := ch.Package + "/" + ch.Name
+				for _, e := range ch.Entries {
+					entries[key] = append(entries[key], e.Name)
+				}
+			}
+			require.Equal(t, s.expectedEntries, entries)
+			if s.expectedSkips != nil {
+				skips := map[string][]string{}
+				for _, ch := range out.Channels {
+					for _, e := range ch.Entries {
+						if len(e.Skips) > 0 {
+							skips[e.Name] = e.Skips
+						}
+					}
+				}
+				require.Equal(t, s.expectedSkips, skips)
+			}
+
+			var bundles []string
+			for _, b := range out.Bundles {
+				bundles = append(bundles, b.Name)
+			}
+			require.Equal(t, s.expectedBundles, bundles)
+
+			var deprecations []string
+			for _, d := range out.Deprecations {
+				for _, e := range d.Entries {
+					deprecations = append(deprecations, e.Reference.Schema+"/"+e.Reference.Name)
+				}
+			}
+			require.Equal(t, s.expectedDeprecations, deprecations)
+
+			var otherPackages []string
+			for _, o := range out.Others {
+				otherPackages = append(otherPackages, o.Package)
+			}
+			require.Equal(t, s.expectedOtherPackages, otherPackages)
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func (s Severity) validate() error {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	}
+	return fmt.Errorf("unknown severity %q, expected one of (%s|%s|%s)", s, SeverityError, SeverityWarning, SeverityInfo)
+}
+
+// Location identifies the catalog object a finding applies to. Empty fields
+// mean that the finding is not specific to an object at that level.
+type Location struct {
+	Package string `json:"package,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Bundle  string `json:"bundle,omitempty"`
+}
+
+func (l Location) String() string {
+	var parts []string
+	if l.Package != "" {
+		parts = append(parts, fmt.Sprintf("package %q", l.Package))
+	}
+	if l.Channel != "" {
+		parts = append(parts, fmt.Sprintf("channel %q", l.Channel))
+	}
+	if l.Bundle != "" {
+		parts = append(parts, fmt.Sprintf("bundle %q", l.Bundle))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	RuleID   string   `json:"ruleID"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Location Location `json:"location"`
+	// File is the file that the object at Location was loaded from, if it
+	// is known.
+	File string `json:"file,omitempty"`
+}
+
+func (f Finding) String() string {
+	if loc := f.Location.String(); loc != "" {
+		return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.RuleID, loc, f.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s", f.Severity, f.RuleID, f.Message)
+}
+
+// Rule checks a declarative config for one kind of problem.
+type Rule interface {
+	// ID is a stable identifier for the rule, used in lint configuration and output.
+	ID() string
+	Description() string
+	DefaultSeverity() Severity
+	// Check returns the problems found by the rule. Only the Message and
+	// Location of the returned findings are used; the linter sets the rule
+	// ID and the configured severity.
+	Check(ctx context.Context, in *Input) []Finding
+}
+
+// Input is the catalog being linted. It caches derived data that is shared
+// between rules, so rules should use its accessors rather than recomputing it.
+type Input struct {
+	Config *declcfg.DeclarativeConfig
+
+	packageConfigsOnce sync.Once
+	packageConfigs     map[string]declcfg.DeclarativeConfig
+
+	modelIssuesOnce sync.Once
+	modelIssues     []modelIssue
+}
+
+// PackageConfigs returns the objects of the config that belong to each
+// package, keyed by package name. Objects without a package name are keyed
+// by the empty string.
+func (in *Input) PackageConfigs() map[string]declcfg.DeclarativeConfig {
+	in.packageConfigsOnce.Do(func() {
+		in.packageConfigs = map[string]declcfg.DeclarativeConfig{}
+		add := func(name string, fn func(*declcfg.DeclarativeConfig)) {
+			c := in.packageConfigs[name]
+			fn(&c)
+			in.packageConfigs[name] = c
+		}
+		for _, p := range in.Config.Packages {
+			add(p.Name, func(c *declcfg.DeclarativeConfig) { c.Packages = append(c.Packages, p) })
+		}
+		for _, ch := range in.Config.Channels {
+			add(ch.Package, func(c *declcfg.DeclarativeConfig) { c.Channels = append(c.Channels, ch) })
+		}
+		for _, b := range in.Config.Bundles {
+			add(b.Package, func(c *declcfg.DeclarativeConfig) { c.Bundles = append(c.Bundles, b) })
+		}
+		for _, d := range in.Config.Deprecations {
+			add(d.Package, func(c *declcfg.DeclarativeConfig) { c.Deprecations = append(c.Deprecations, d) })
+		}
+		for _, o := range in.Config.Others {
+			add(o.Package, func(c *declcfg.DeclarativeConfig) { c.Others = append(c.Others, o) })
+		}
+	})
+	return in.packageConfigs
+}
+
+// Linter runs a set of registered rules against declarative configs.
+type Linter struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewLinter creates a linter with all built-in rules registered.
+func NewLinter() *Linter {
+	l := &Linter{rules: map[string]Rule{}}
+	for _, r := range builtinRules() {
+		l.Register(r)
+	}
+	return l
+}
+
+// Register adds a rule to the linter, replacing any rule with the same ID.
+func (l *Linter) Register(r Rule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules[r.ID()] = r
+}
+
+// Rules returns the registered rules, sorted by ID.
+func (l *Linter) Rules() []Rule {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	rules := make([]Rule, 0, len(l.rules))
+	for _, r := range l.rules {
+		rules = append(rules, r)
+	}
+	slices.SortFunc(rules, func(a, b Rule) int { return strings.Compare(a.ID(), b.ID()) })
+	return rules
+}
+
+// Lint runs all enabled rules against cfg. A nil lintConfig runs all rules
+// with their default severities.
+func (l *Linter) Lint(ctx context.Context, cfg *declcfg.DeclarativeConfig, lintConfig *Config) (*Result, error) {
+	if lintConfig == nil {
+		lintConfig = &Config{}
+	}
+	rules := l.Rules()
+	if err := lintConfig.validate(rules); err != nil {
+		return nil, err
+	}
+
+	in := &Input{Config: cfg}
+	result := &Result{Findings: []Finding{}}
+	for _, r := range rules {
+		settings := lintConfig.Rules[r.ID()]
+		if settings.Enabled != nil && !*settings.Enabled {
+			continue
+		}
+		severity := r.DefaultSeverity()
+		if settings.Severity != "" {
+			severity = settings.Severity
+		}
+		result.Rules = append(result.Rules, RuleInfo{ID: r.ID(), Description: r.Description(), Severity: severity})
+		for _, f := range r.Check(ctx, in) {
+			f.RuleID = r.ID()
+			f.Severity = severity
+			result.Findings = append(result.Findings, f)
+		}
+	}
+	slices.SortStableFunc(result.Findings, compareFindings)
+	return result, nil
+}
+
+// LintFS loads the declarative config in root and lints it like Lint. Each
+// finding records the file, relative to root, that the object it applies to
+// was loaded from.
+func (l *Linter) LintFS(ctx context.Context, root fs.FS, lintConfig *Config) (*Result, error) {
+	cfg := &declcfg.DeclarativeConfig{}
+	files := sources{}
+	if err := declcfg.WalkFS(root, func(path string, fileCfg *declcfg.DeclarativeConfig, err error) error {
+		if err != nil {
+			return err
+		}
+		files.add(path, fileCfg)
+		cfg.Merge(fileCfg)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	result, err := l.Lint(ctx, cfg, lintConfig)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Findings {
+		result.Findings[i].File = files.file(result.Findings[i].Location)
+	}
+	return result, nil
+}
+
+// sources maps the packages, channels and bundles of a catalog to the files
+// they were loaded from. Channels are keyed without a bundle, and bundles
+// without a channel.
+type sources map[Location]string
+
+func (s sources) add(path string, cfg *declcfg.DeclarativeConfig) {
+	for _, p := range cfg.Packages {
+		s[Location{Package: p.Name}] = path
+	}
+	for _, ch := range cfg.Channels {
+		s[Location{Package: ch.Package, Channel: ch.Name}] = path
+	}
+	for _, b := range cfg.Bundles {
+		s[Location{Package: b.Package, Bundle: b.Name}] = path
+	}
+}
+
+// file returns the file of the most specific object at l whose file is
+// known.
+func (s sources) file(l Location) string {
+	if l.Bundle != "" {
+		if path, ok := s[Location{Package: l.Package, Bundle: l.Bundle}]; ok {
+			return path
+		}
+	}
+	if l.Channel != "" {
+		if path, ok := s[Location{Package: l.Package, Channel: l.Channel}]; ok {
+			return path
+		}
+	}
+	return s[Location{Package: l.Package}]
+}
+
+func compareFindings(a, b Finding) int {
+	for _, c := range []int{
+		strings.Compare(a.Location.Package, b.Location.Package),
+		strings.Compare(a.Location.Channel, b.Location.Channel),
+		strings.Compare(a.Location.Bundle, b.Location.Bundle),
+		strings.Compare(a.RuleID, b.RuleID),
+	} {
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Config configures which rules are run and with what severity.
+//
+// Example:
+//
+//	rules:
+//	  package-icon-missing:
+//	    enabled: false
+//	  default-channel-deprecated:
+//	    severity: error
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules,omitempty"`
+}
+
+type RuleConfig struct {
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Severity overrides the rule's default severity.
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// LoadConfig reads a YAML or JSON lint configuration.
+func LoadConfig(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse lint config: %v", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate(rules []Rule) error {
+	for id, rc := range c.Rules {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID() == id }) {
+			return fmt.Errorf("invalid lint config: unknown rule %q", id)
+		}
+		if rc.Severity == "" {
+			continue
+		}
+		if err := rc.Severity.validate(); err != nil {
+			return fmt.Errorf("invalid lint config for rule %q: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// RuleInfo describes a rule that was run, with its effective severity.
+type RuleInfo struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+}
+
+// Result holds the findings of a lint run.
+type Result struct {
+	Rules    []RuleInfo `json:"rules"`
+	Findings []Finding  `json:"findings"`
+}
+
+// HasErrors returns true if any finding has error severity.
+func (r *Result) HasErrors() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+// Err returns an error summarizing all findings with error severity, or nil
+// if there are none.
+func (r *Result) Err() error {
+	var msgs []string
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			msgs = append(msgs, f.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("catalog has %d lint error(s):\n%s", len(msgs), strings.Join(msgs, "\n"))
+}
+
+// WriteText writes one line per finding.
+func (r *Result) WriteText(w io.Writer) error {
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintln(w, f.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Result) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
@@ -0,0 +1,259 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+func testBundle(pkg, version string) declcfg.Bundle {
+	return declcfg.Bundle{
+		Schema:     declcfg.SchemaBundle,
+		Name:       pkg + ".v" + version,
+		Package:    pkg,
+		Image:      "example.com/" + pkg + "-bundle:v" + version,
+		Properties: []property.Property{property.MustBuildPackage(pkg, version)},
+	}
+}
+
+func testConfig() *declcfg.DeclarativeConfig {
+	return &declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{
+			{Schema: declcfg.SchemaPackage, Name: "foo", DefaultChannel: "stable", Icon: &declcfg.Icon{Data: []byte("<svg/>"), MediaType: "image/svg+xml"}},
+			{Schema: declcfg.SchemaPackage, Name: "bar", DefaultChannel: "stable"},
+			{Schema: declcfg.SchemaPackage, Name: "baz", DefaultChannel: "stable"},
+		},
+		Channels: []declcfg.Channel{
+			{Schema: declcfg.SchemaChannel, Package: "foo", Name: "stable", Entries: []declcfg.ChannelEntry{
+				{Name: "foo.v0.1.0"},
+				{Name: "foo.v0.2.0", Replaces: "foo.v0.1.0"},
+			}},
+			{Schema: declcfg.SchemaChannel, Package: "bar", Name: "stable", Entries: []declcfg.ChannelEntry{
+				{Name: "bar.v0.1.0"},
+				{Name: "bar.v0.2.0", SkipRange: "invalid"},
+			}},
+			{Schema: declcfg.SchemaChannel, Package: "baz", Name: "stable", Entries: []declcfg.ChannelEntry{
+				{Name: "baz.v0.1.0"},
+			}},
+		},
+		Bundles: []declcfg.Bundle{
+			testBundle("foo", "0.1.0"),
+			testBundle("foo", "0.2.0"),
+			testBundle("bar", "0.1.0"),
+			testBundle("bar", "0.2.0"),
+		},
+		Deprecations: []declcfg.Deprecation{
+			{Schema: declcfg.SchemaDeprecation, Package: "foo", Entries: []declcfg.DeprecationEntry{
+				{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: "stable"}, Message: "use fast"},
+			}},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	type spec struct {
+		name        string
+		config      string
+		expected    []Finding
+		expectedErr string
+	}
+	specs := []spec{
+		{
+			name: "Success/DefaultConfig",
+			expected: []Finding{
+				{RuleID: "package-icon-missing", Severity: SeverityInfo, Message: "package has no icon", Location: Location{Package: "bar"}},
+				{RuleID: "channel-invalid", Severity: SeverityError, Message: "multiple channel heads found in graph: bar.v0.1.0, bar.v0.2.0", Location: Location{Package: "bar", Channel: "stable"}},
+				{RuleID: "bundle-invalid", Severity: SeverityError, Message: `invalid skipRange "invalid": Could not get version from string: "invalid"`, Location: Location{Package: "bar", Channel: "stable", Bundle: "bar.v0.2.0"}},
+				{RuleID: "catalog-structure", Severity: SeverityError, Message: `no olm.bundle blobs found in package "baz" for olm.channel entries [baz.v0.1.0]`, Location: Location{Package: "baz"}},
+				{RuleID: "package-icon-missing", Severity: SeverityInfo, Message: "package has no icon", Location: Location{Package: "baz"}},
+				{RuleID: "default-channel-deprecated", Severity: SeverityWarning, Message: "default channel is deprecated: use fast", Location: Location{Package: "foo", Channel: "stable"}},
+			},
+		},
+		{
+			name: "Success/CustomConfig",
+			config: `
+rules:
+  package-icon-missing:
+    enabled: false
+  catalog-structure:
+    enabled: false
+  default-channel-deprecated:
+    severity: error
+  channel-invalid:
+    severity: warning
+`,
+			expected: []Finding{
+				{RuleID: "channel-invalid", Severity: SeverityWarning, Message: "multiple channel heads found in graph: bar.v0.1.0, bar.v0.2.0", Location: Location{Package: "bar", Channel: "stable"}},
+				{RuleID: "bundle-invalid", Severity: SeverityError, Message: `invalid skipRange "invalid": Could not get version from string: "invalid"`, Location: Location{Package: "bar", Channel: "stable", Bundle: "bar.v0.2.0"}},
+				{RuleID: "default-channel-deprecated", Severity: SeverityError, Message: "default channel is deprecated: use fast", Location: Location{Package: "foo", Channel: "stable"}},
+			},
+		},
+		{
+			name:        "Error/UnknownRule",
+			config:      `{"rules": {"no-such-rule": {"enabled": false}}}`,
+			expectedErr: `invalid lint config: unknown rule "no-such-rule"`,
+		},
+		{
+			name:        "Error/UnknownSeverity",
+			config:      `{"rules": {"bundle-invalid": {"severity": "fatal"}}}`,
+			expectedErr: `invalid lint config for rule "bundle-invalid": unknown severity "fatal", expected one of (error|warning|info)`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			var lintConfig *Config
+			if s.config != "" {
+				var err error
+				lintConfig, err = LoadConfig(strings.NewReader(s.config))
+				require.NoError(t, err)
+			}
+			result, err := NewLinter().Lint(context.Background(), testConfig(), lintConfig)
+			if s.expectedErr != "" {
+				require.EqualError(t, err, s.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expected, result.Findings)
+		})
+	}
+}
+
+type noDescriptionRule struct{}
+
+func (noDescriptionRule) ID() string                { return "package-description-missing" }
+func (noDescriptionRule) Description() string       { return "Packages should have a description" }
+func (noDescriptionRule) DefaultSeverity() Severity { return SeverityWarning }
+func (noDescriptionRule) Check(_ context.Context, in *Input) []Finding {
+	var findings []Finding
+	for _, p := range in.Config.Packages {
+		if p.Description == "" {
+			findings = append(findings, Finding{Message: "package has no description", Location: Location{Package: p.Name}})
+		}
+	}
+	return findings
+}
+
+func TestLintCustomSchemas(t *testing.T) {
+	v, err := model.JSONSchemaValidator([]byte(`{"type":"object","required":["tier"],"properties":{"tier":{"type":"string","enum":["premium","standard"]}}}`))
+	require.NoError(t, err)
+	model.RegisterSchema("test.lint.support-tier", v)
+
+	cfg := testConfig()
+	cfg.Others = []declcfg.Meta{
+		{Schema: "test.lint.support-tier", Package: "foo", Blob: json.RawMessage(`{"schema":"test.lint.support-tier","package":"foo","tier":"gold"}`)},
+		{Schema: "test.lint.support-tier", Package: "qux", Blob: json.RawMessage(`{"schema":"test.lint.support-tier","package":"qux","tier":"premium"}`)},
+		{Schema: "test.lint.unregistered", Package: "foo", Blob: json.RawMessage(`{"schema":"test.lint.unregistered","package":"foo"}`)},
+	}
+
+	disabled := false
+	result, err := NewLinter().Lint(context.Background(), cfg, &Config{Rules: map[string]RuleConfig{
+		"package-icon-missing":       {Enabled: &disabled},
+		"default-channel-deprecated": {Enabled: &disabled},
+		"channel-invalid":            {Enabled: &disabled},
+		"bundle-invalid":             {Enabled: &disabled},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []Finding{
+		{RuleID: "catalog-structure", Severity: SeverityError, Message: `no olm.bundle blobs found in package "baz" for olm.channel entries [baz.v0.1.0]`, Location: Location{Package: "baz"}},
+		{RuleID: "custom-schema-invalid", Severity: SeverityError, Message: `invalid "test.lint.support-tier" blob: tier in body should be one of [premium standard]`, Location: Location{Package: "foo"}},
+		{RuleID: "catalog-structure", Severity: SeverityError, Message: `unknown package "qux" for "test.lint.support-tier" blob`, Location: Location{Package: "qux"}},
+	}, result.Findings)
+}
+
+func TestLintCustomRule(t *testing.T) {
+	l := NewLinter()
+	l.Register(noDescriptionRule{})
+	cfg := testConfig()
+	cfg.Packages[0].Description = "foo operator"
+
+	result, err := l.Lint(context.Background(), cfg, &Config{Rules: map[string]RuleConfig{
+		"package-description-missing": {Severity: SeverityError},
+	}})
+	require.NoError(t, err)
+
+	var found []string
+	for _, f := range result.Findings {
+		if f.RuleID == "package-description-missing" {
+			found = append(found, f.Location.Package)
+			require.Equal(t, SeverityError, f.Severity)
+		}
+	}
+	require.Equal(t, []string{"bar", "baz"}, found)
+	require.True(t, result.HasErrors())
+}
+
+func TestResultWriteSARIF(t *testing.T) {
+	result, err := NewLinter().Lint(context.Background(), testConfig(), nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, result.WriteSARIF(buf))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, len(NewLinter().Rules()))
+	require.Len(t, log.Runs[0].Results, len(result.Findings))
+
+	bundleResult := log.Runs[0].Results[2]
+	require.Equal(t, "bundle-invalid", bundleResult.RuleID)
+	require.Equal(t, "error", bundleResult.Level)
+	require.Equal(t, "bundle-invalid", log.Runs[0].Tool.Driver.Rules[bundleResult.RuleIndex].ID)
+	require.Equal(t, []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+		Name:               "bar.v0.2.0",
+		FullyQualifiedName: "bar/stable/bar.v0.2.0",
+		Kind:               "bundle",
+	}}}}, bundleResult.Locations)
+}
+
+func TestLintFS(t *testing.T) {
+	// Each package is in its own file, except for the bundles of bar.
+	root := fstest.MapFS{}
+	for pkg, cfg := range (&Input{Config: testConfig()}).PackageConfigs() {
+		var bundles []declcfg.Bundle
+		if pkg == "bar" {
+			bundles, cfg.Bundles = cfg.Bundles, nil
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, declcfg.WriteJSON(cfg, buf))
+		root[pkg+"/catalog.json"] = &fstest.MapFile{Data: buf.Bytes()}
+		if bundles != nil {
+			buf := &bytes.Buffer{}
+			require.NoError(t, declcfg.WriteJSON(declcfg.DeclarativeConfig{Bundles: bundles}, buf))
+			root[pkg+"/bundles.json"] = &fstest.MapFile{Data: buf.Bytes()}
+		}
+	}
+
+	result, err := NewLinter().LintFS(context.Background(), root, nil)
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range result.Findings {
+		files[f.RuleID+" "+f.Location.String()] = f.File
+	}
+	require.Equal(t, map[string]string{
+		`package-icon-missing package "bar"`:                                  "bar/catalog.json",
+		`channel-invalid package "bar", channel "stable"`:                     "bar/catalog.json",
+		`bundle-invalid package "bar", channel "stable", bundle "bar.v0.2.0"`: "bar/bundles.json",
+		`catalog-structure package "baz"`:                                     "baz/catalog.json",
+		`package-icon-missing package "baz"`:                                  "baz/catalog.json",
+		`default-channel-deprecated package "foo", channel "stable"`:          "foo/catalog.json",
+	}, files)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, result.WriteSARIF(buf))
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	bundleResult := log.Runs[0].Results[2]
+	require.Equal(t, "bundle-invalid", bundleResult.RuleID)
+	require.Equal(t, &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "bar/bundles.json"}}, bundleResult.Locations[0].PhysicalLocation)
+}
@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"context"
+	"sort"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+)
+
+func builtinRules() []Rule {
+	return []Rule{
+		modelRule{
+			id:          "catalog-structure",
+			description: "Objects must reference known packages, channels, and bundles, and must not be duplicated",
+			kind:        modelIssueStructure,
+		},
+		modelRule{
+			id:          "package-invalid",
+			description: "Packages must have a name, a default channel that exists, and unique bundle versions",
+			kind:        modelIssuePackage,
+		},
+		modelRule{
+			id:          "channel-invalid",
+			description: "Channels must have a single head and an upgrade graph with no cycles or stranded bundles",
+			kind:        modelIssueChannel,
+		},
+		modelRule{
+			id:          "bundle-invalid",
+			description: "Bundles must have a valid name, image, properties, skips, and skipRange",
+			kind:        modelIssueBundle,
+		},
+		modelRule{
+			id:          "custom-schema-invalid",
+			description: "Blobs of custom schemas must pass the validator registered for their schema",
+			kind:        modelIssueCustomSchema,
+		},
+		defaultChannelDeprecatedRule{},
+		packageIconMissingRule{},
+	}
+}
+
+type modelIssueKind int
+
+const (
+	modelIssueStructure modelIssueKind = iota
+	modelIssuePackage
+	modelIssueChannel
+	modelIssueBundle
+	modelIssueCustomSchema
+)
+
+type modelIssue struct {
+	kind    modelIssueKind
+	finding Finding
+}
+
+// modelIssuesByKind converts each package to the model independently, so that a
+// problem in one package does not hide problems in others, and attributes
+// each conversion and validation problem to the object it was found in.
+func (in *Input) modelIssuesByKind() []modelIssue {
+	in.modelIssuesOnce.Do(func() {
+		pkgCfgs := in.PackageConfigs()
+		names := make([]string, 0, len(pkgCfgs))
+		for name := range pkgCfgs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			m, err := declcfg.ConvertToModel(pkgCfgs[name])
+			if err == nil {
+				err = declcfg.ValidateOthers(pkgCfgs[name], m)
+			}
+			if err == nil {
+				continue
+			}
+			issues, ok := model.ValidationIssues(err)
+			if !ok {
+				in.modelIssues = append(in.modelIssues, modelIssue{
+					kind:    modelIssueStructure,
+					finding: Finding{Message: err.Error(), Location: Location{Package: name}},
+				})
+				continue
+			}
+			for _, issue := range issues {
+				mi := modelIssue{
+					kind: modelIssuePackage,
+					finding: Finding{
+						Message:  issue.Message,
+						Location: Location{Package: issue.Package, Channel: issue.Channel, Bundle: issue.Bundle},
+					},
+				}
+				switch {
+				case issue.Schema != "":
+					mi.kind = modelIssueCustomSchema
+				case issue.Bundle != "":
+					mi.kind = modelIssueBundle
+				case issue.Channel != "":
+					mi.kind = modelIssueChannel
+				}
+				in.modelIssues = append(in.modelIssues, mi)
+			}
+		}
+	})
+	return in.modelIssues
+}
+
+// modelRule reports the problems found by converting the config to a
+// model.Model and validating it.
+type modelRule struct {
+	id          string
+	description string
+	kind        modelIssueKind
+}
+
+func (r modelRule) ID() string                { return r.id }
+func (r modelRule) Description() string       { return r.description }
+func (r modelRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r modelRule) Check(_ context.Context, in *Input) []Finding {
+	var findings []Finding
+	for _, mi := range in.modelIssuesByKind() {
+		if mi.kind == r.kind {
+			findings = append(findings, mi.finding)
+		}
+	}
+	return findings
+}
+
+type defaultChannelDeprecatedRule struct{}
+
+func (defaultChannelDeprecatedRule) ID() string { return "default-channel-deprecated" }
+func (defaultChannelDeprecatedRule) Description() string {
+	return "The default channel of a package that is not itself deprecated should not be deprecated"
+}
+func (defaultChannelDeprecatedRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (defaultChannelDeprecatedRule) Check(_ context.Context, in *Input) []Finding {
+	defaultChannels := map[string]string{}
+	for _, p := range in.Config.Packages {
+		defaultChannels[p.Name] = p.DefaultChannel
+	}
+
+	var findings []Finding
+	for _, d := range in.Config.Deprecations {
+		packageDeprecated := false
+		var deprecatedDefault *declcfg.DeprecationEntry
+		for i, e := range d.Entries {
+			switch {
+			case e.Reference.Schema == declcfg.SchemaPackage:
+				packageDeprecated = true
+			case e.Reference.Schema == declcfg.SchemaChannel && e.Reference.Name == defaultChannels[d.Package]:
+				deprecatedDefault = &d.Entries[i]
+			}
+		}
+		if deprecatedDefault != nil && !packageDeprecated {
+			findings = append(findings, Finding{
+				Message:  "default channel is deprecated: " + deprecatedDefault.Message,
+				Location: Location{Package: d.Package, Channel: deprecatedDefault.Reference.Name},
+			})
+		}
+	}
+	return findings
+}
+
+type packageIconMissingRule struct{}
+
+func (packageIconMissingRule) ID() string { return "package-icon-missing" }
+func (packageIconMissingRule) Description() string {
+	return "Packages should have an icon for display in user interfaces"
+}
+func (packageIconMissingRule) DefaultSeverity() Severity { return SeverityInfo }
+
+func (packageIconMissingRule) Check(_ context.Context, in *Input) []Finding {
+	var findings []Finding
+	for _, p := range in.Config.Packages {
+		if p.Icon == nil || len(p.Icon.Data) == 0 {
+			findings = append(findings, Finding{Message: "package has no icon", Location: Location{Package: p.Name}})
+		}
+	}
+	return findings
+}
@@ -10,12 +10,33 @@ import (
 type validationError struct {
 	message   string
 	subErrors []error
+
+	// kind and name identify the model object that was validated, if any.
+	// They are used to attribute issues to a location in ValidationIssues.
+	kind objectKind
+	name string
 }
 
+type objectKind string
+
+const (
+	kindModel   objectKind = "model"
+	kindPackage objectKind = "package"
+	kindChannel objectKind = "channel"
+	kindBundle  objectKind = "bundle"
+	kindOther   objectKind = "other"
+)
+
 func newValidationError(message string) *validationError {
 	return &validationError{message: message}
 }
 
+func (v *validationError) forObject(kind objectKind, name string) *validationError {
+	v.kind = kind
+	v.name = name
+	return v
+}
+
 func (v *validationError) orNil() error {
 	if len(v.subErrors) == 0 {
 		return nil
@@ -67,3 +88,71 @@ func (v *validationError) errorPrefix(prefix []rune, last bool, seen []error) st
 	}
 	return errMsg.String()
 }
+
+// ValidationIssue is a single problem found by model validation, along with
+// the package, channel, and bundle in which it was found. Schema is set for
+// problems found in the blobs of a custom schema.
+type ValidationIssue struct {
+	Package string
+	Channel string
+	Bundle  string
+	Schema  string
+	Message string
+}
+
+// ValidationIssues flattens an error returned by the Validate method of a
+// Model, Package, Channel, or Bundle into its individual issues. It returns
+// false if err was not produced by model validation.
+func ValidationIssues(err error) ([]ValidationIssue, bool) {
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		return nil, false
+	}
+	var issues []ValidationIssue
+	collectIssues(verr, ValidationIssue{}, "", nil, &issues)
+	return issues, true
+}
+
+func collectIssues(err error, loc ValidationIssue, prefix string, seen []error, issues *[]ValidationIssue) {
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		loc.Message = prefix + err.Error()
+		*issues = append(*issues, loc)
+		return
+	}
+	for _, s := range seen {
+		if errors.Is(verr, s) {
+			return
+		}
+	}
+	seen = append(seen, verr)
+	switch verr.kind {
+	case kindModel:
+	case kindPackage:
+		loc.Package = verr.name
+	case kindChannel:
+		loc.Channel = verr.name
+	case kindBundle:
+		loc.Bundle = verr.name
+	case kindOther:
+		loc.Schema = verr.name
+		// Blobs are identified by their message, so keep it for their sub-errors.
+		if len(verr.subErrors) > 0 {
+			prefix = prefix + verr.message + ": "
+		}
+	default:
+		// Validation errors for parts of an object (e.g. an icon) carry
+		// useful context in their message, so keep it for their sub-errors.
+		if len(verr.subErrors) > 0 {
+			prefix = prefix + verr.message + ": "
+		}
+	}
+	if len(verr.subErrors) == 0 {
+		loc.Message = prefix + verr.message
+		*issues = append(*issues, loc)
+		return
+	}
+	for _, serr := range verr.subErrors {
+		collectIssues(serr, loc, prefix, seen, issues)
+	}
+}
@@ -127,3 +127,29 @@ func TestValidationError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationIssues(t *testing.T) {
+	_, ok := ValidationIssues(fmt.Errorf("not a validation error"))
+	require.False(t, ok)
+
+	pkg := &Package{Name: "foo", Icon: &Icon{}}
+	ch := &Channel{Package: pkg, Name: "stable", Bundles: map[string]*Bundle{}}
+	pkg.Channels = map[string]*Channel{"stable": ch}
+	ch.Bundles["foo.v1.0.0"] = &Bundle{Package: pkg, Channel: ch, Name: "foo.v1.0.0", Image: "foo-bundle:v1.0.0", SkipRange: "invalid"}
+
+	issues, ok := ValidationIssues(Model{"foo": pkg}.Validate())
+	require.True(t, ok)
+	require.Equal(t, []ValidationIssue{
+		{Package: "foo", Message: "default channel must be set"},
+		{Package: "foo", Channel: "stable", Bundle: "foo.v1.0.0", Message: `invalid skipRange "invalid": Could not get version from string: "invalid"`},
+		{Package: "foo", Channel: "stable", Bundle: "foo.v1.0.0", Message: `must be exactly one property with type "olm.package"`},
+	}, issues)
+
+	recursiveErr := &validationError{message: "l1"}
+	recursiveErr.subErrors = []error{
+		&validationError{message: "l2", subErrors: []error{recursiveErr, fmt.Errorf("err1")}},
+	}
+	issues, ok = ValidationIssues(recursiveErr)
+	require.True(t, ok)
+	require.Equal(t, []ValidationIssue{{Message: "l1: l2: err1"}}, issues)
+}
@@ -0,0 +1,252 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+type EdgeType string
+
+const (
+	EdgeReplaces  EdgeType = "replaces"
+	EdgeSkips     EdgeType = "skips"
+	EdgeSkipRange EdgeType = "skipRange"
+)
+
+// Edge is a single upgrade edge in a channel's upgrade graph. An edge always
+// points from the bundle being upgraded (From) to the bundle that upgrades it
+// (To).
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Type EdgeType `json:"type"`
+}
+
+// Graph is the upgrade graph of a single channel, built from the replaces,
+// skips and skipRange fields of the channel's bundles. Only bundles that are
+// members of the channel are nodes in the graph; replaces and skips that
+// reference bundles outside the channel do not produce edges.
+type Graph struct {
+	Channel *Channel
+
+	// outgoing holds, for each bundle name, the edges to the bundles that can
+	// upgrade it, sorted by destination bundle name and edge type.
+	outgoing map[string][]Edge
+}
+
+// NoPathError is returned when no upgrade path exists between two bundles.
+type NoPathError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *NoPathError) Error() string {
+	return fmt.Sprintf("no upgrade path from %q to %q: %s", e.From, e.To, e.Reason)
+}
+
+// Graph builds the upgrade graph of the channel.
+func (c *Channel) Graph() *Graph {
+	g := &Graph{
+		Channel:  c,
+		outgoing: make(map[string][]Edge, len(c.Bundles)),
+	}
+	// An edge may be implied more than once (e.g. via both replaces and
+	// skipRange), so keep only the first edge type seen for each pair, in
+	// order of precedence.
+	seen := map[[2]string]struct{}{}
+	addEdge := func(from, to string, typ EdgeType) {
+		if _, ok := c.Bundles[from]; !ok || from == to {
+			return
+		}
+		key := [2]string{from, to}
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		g.outgoing[from] = append(g.outgoing[from], Edge{From: from, To: to, Type: typ})
+	}
+
+	for _, b := range c.Bundles {
+		if b.Replaces != "" {
+			addEdge(b.Replaces, b.Name, EdgeReplaces)
+		}
+	}
+	for _, b := range c.Bundles {
+		for _, skip := range b.Skips {
+			addEdge(skip, b.Name, EdgeSkips)
+		}
+	}
+	for _, b := range c.Bundles {
+		if b.SkipRange == "" {
+			continue
+		}
+		inRange, err := semver.ParseRange(b.SkipRange)
+		if err != nil {
+			// Invalid skipRanges are reported by bundle validation.
+			continue
+		}
+		for _, candidate := range c.Bundles {
+			if inRange(candidate.Version) {
+				addEdge(candidate.Name, b.Name, EdgeSkipRange)
+			}
+		}
+	}
+
+	for from := range g.outgoing {
+		edges := g.outgoing[from]
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].To != edges[j].To {
+				return edges[i].To < edges[j].To
+			}
+			return edges[i].Type < edges[j].Type
+		})
+	}
+	return g
+}
+
+// Edges returns all edges in the graph, sorted by source and destination bundle name.
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+	for _, from := range g.sortedBundleNames() {
+		edges = append(edges, g.outgoing[from]...)
+	}
+	return edges
+}
+
+// UpgradesFrom returns the edges leading out of the named bundle, i.e. the
+// bundles that the named bundle can be directly upgraded to.
+func (g *Graph) UpgradesFrom(name string) []Edge {
+	return append([]Edge(nil), g.outgoing[name]...)
+}
+
+// Reachable returns the sorted names of all bundles that can be reached by
+// one or more upgrades from the named bundle. The named bundle itself is not
+// included.
+func (g *Graph) Reachable(from string) ([]string, error) {
+	if _, ok := g.Channel.Bundles[from]; !ok {
+		return nil, fmt.Errorf("bundle %q not found in channel %q", from, g.Channel.Name)
+	}
+	visited := map[string]struct{}{from: {}}
+	queue := []string{from}
+	var reachable []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range g.outgoing[cur] {
+			if _, ok := visited[e.To]; ok {
+				continue
+			}
+			visited[e.To] = struct{}{}
+			reachable = append(reachable, e.To)
+			queue = append(queue, e.To)
+		}
+	}
+	sort.Strings(reachable)
+	return reachable, nil
+}
+
+// ShortestPath returns the shortest sequence of upgrade edges leading from
+// one bundle to another. If from and to are the same bundle, the path is
+// empty. If no path exists, the returned error is a *NoPathError that
+// explains why.
+//
+// When several shortest paths exist, the one that visits lexicographically
+// smaller bundle names first is returned, so results are deterministic.
+func (g *Graph) ShortestPath(from, to string) ([]Edge, error) {
+	if _, ok := g.Channel.Bundles[from]; !ok {
+		return nil, &NoPathError{From: from, To: to, Reason: fmt.Sprintf("bundle %q is not in channel %q", from, g.Channel.Name)}
+	}
+	if _, ok := g.Channel.Bundles[to]; !ok {
+		return nil, &NoPathError{From: from, To: to, Reason: fmt.Sprintf("bundle %q is not in channel %q", to, g.Channel.Name)}
+	}
+	if from == to {
+		return []Edge{}, nil
+	}
+
+	via := map[string]Edge{}
+	visited := map[string]struct{}{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range g.outgoing[cur] {
+			if _, ok := visited[e.To]; ok {
+				continue
+			}
+			visited[e.To] = struct{}{}
+			via[e.To] = e
+			if e.To == to {
+				return pathTo(via, from, to), nil
+			}
+			queue = append(queue, e.To)
+		}
+	}
+	return nil, &NoPathError{From: from, To: to, Reason: g.explainNoPath(from, to, visited)}
+}
+
+// PathToHead returns the shortest upgrade path from the named bundle to the
+// channel head. See ShortestPath for details.
+func (g *Graph) PathToHead(from string) ([]Edge, error) {
+	head, err := g.Channel.Head()
+	if err != nil {
+		return nil, &NoPathError{From: from, Reason: fmt.Sprintf("channel %q has no unique head: %v", g.Channel.Name, err)}
+	}
+	return g.ShortestPath(from, head.Name)
+}
+
+func pathTo(via map[string]Edge, from, to string) []Edge {
+	var path []Edge
+	for cur := to; cur != from; cur = via[cur].From {
+		path = append(path, via[cur])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// explainNoPath describes why the destination is not reachable, given the
+// set of bundles that are reachable from the source.
+func (g *Graph) explainNoPath(from, to string, reachable map[string]struct{}) string {
+	if len(g.outgoing[from]) == 0 {
+		return fmt.Sprintf("no bundle in channel %q replaces, skips, or has a skipRange that includes %q", g.Channel.Name, from)
+	}
+
+	var deadEnds []string
+	for name := range reachable {
+		if len(g.outgoing[name]) == 0 {
+			deadEnds = append(deadEnds, name)
+		}
+	}
+	sort.Strings(deadEnds)
+
+	fromBundle, toBundle := g.Channel.Bundles[from], g.Channel.Bundles[to]
+	if toBundle.Compare(fromBundle) < 0 {
+		return fmt.Sprintf("%q (%s) is older than %q (%s) and is not reachable by upgrades", to, toBundle.VersionString(), from, fromBundle.VersionString())
+	}
+	if len(deadEnds) == 0 {
+		return fmt.Sprintf("the %d bundle(s) reachable from %q only upgrade to each other", len(reachable)-1, from)
+	}
+	return fmt.Sprintf("upgrades from %q end at %s, which no bundle in channel %q upgrades", from, strings.Join(quoteAll(deadEnds), ", "), g.Channel.Name)
+}
+
+func (g *Graph) sortedBundleNames() []string {
+	names := make([]string, 0, len(g.Channel.Bundles))
+	for name := range g.Channel.Bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func quoteAll(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		out = append(out, fmt.Sprintf("%q", s))
+	}
+	return out
+}
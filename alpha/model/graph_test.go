@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newGraphTestChannel(bundles ...*Bundle) *Channel {
+	ch := &Channel{Name: "stable", Bundles: map[string]*Bundle{}}
+	for _, b := range bundles {
+		b.Channel = ch
+		ch.Bundles[b.Name] = b
+	}
+	return ch
+}
+
+func graphTestBundle(version, replaces, skipRange string, skips ...string) *Bundle {
+	return &Bundle{
+		Name:      "foo.v" + version,
+		Version:   semver.MustParse(version),
+		Replaces:  replaces,
+		Skips:     skips,
+		SkipRange: skipRange,
+	}
+}
+
+func TestGraph(t *testing.T) {
+	// 1.0.0 -> 1.1.0 -> 1.2.0 -> 2.0.0 (skipRange <2.0.0, skips 1.1.1)
+	//            \-----> 1.1.1 (skips 1.1.0)
+	ch := newGraphTestChannel(
+		graphTestBundle("1.0.0", "", ""),
+		graphTestBundle("1.1.0", "foo.v1.0.0", ""),
+		graphTestBundle("1.1.1", "", "", "foo.v1.1.0"),
+		graphTestBundle("1.2.0", "foo.v1.1.0", ""),
+		graphTestBundle("2.0.0", "foo.v1.2.0", "<2.0.0", "foo.v1.1.1"),
+	)
+	g := ch.Graph()
+
+	t.Run("Edges", func(t *testing.T) {
+		require.Equal(t, []Edge{
+			{From: "foo.v1.0.0", To: "foo.v1.1.0", Type: EdgeReplaces},
+			{From: "foo.v1.0.0", To: "foo.v2.0.0", Type: EdgeSkipRange},
+			{From: "foo.v1.1.0", To: "foo.v1.1.1", Type: EdgeSkips},
+			{From: "foo.v1.1.0", To: "foo.v1.2.0", Type: EdgeReplaces},
+			{From: "foo.v1.1.0", To: "foo.v2.0.0", Type: EdgeSkipRange},
+			{From: "foo.v1.1.1", To: "foo.v2.0.0", Type: EdgeSkips},
+			{From: "foo.v1.2.0", To: "foo.v2.0.0", Type: EdgeReplaces},
+		}, g.Edges())
+	})
+
+	t.Run("Reachable", func(t *testing.T) {
+		reachable, err := g.Reachable("foo.v1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, []string{"foo.v1.1.1", "foo.v1.2.0", "foo.v2.0.0"}, reachable)
+
+		_, err = g.Reachable("foo.v9.9.9")
+		require.EqualError(t, err, `bundle "foo.v9.9.9" not found in channel "stable"`)
+	})
+
+	t.Run("PathToHead", func(t *testing.T) {
+		path, err := g.PathToHead("foo.v1.0.0")
+		require.NoError(t, err)
+		require.Equal(t, []Edge{{From: "foo.v1.0.0", To: "foo.v2.0.0", Type: EdgeSkipRange}}, path)
+
+		path, err = g.PathToHead("foo.v2.0.0")
+		require.NoError(t, err)
+		require.Empty(t, path)
+	})
+
+	t.Run("ShortestPath", func(t *testing.T) {
+		path, err := g.ShortestPath("foo.v1.0.0", "foo.v1.2.0")
+		require.NoError(t, err)
+		require.Equal(t, []Edge{
+			{From: "foo.v1.0.0", To: "foo.v1.1.0", Type: EdgeReplaces},
+			{From: "foo.v1.1.0", To: "foo.v1.2.0", Type: EdgeReplaces},
+		}, path)
+	})
+
+	t.Run("NoPath", func(t *testing.T) {
+		_, err := g.ShortestPath("foo.v2.0.0", "foo.v1.0.0")
+		var npe *NoPathError
+		require.ErrorAs(t, err, &npe)
+		require.EqualError(t, err, `no upgrade path from "foo.v2.0.0" to "foo.v1.0.0": no bundle in channel "stable" replaces, skips, or has a skipRange that includes "foo.v2.0.0"`)
+
+		_, err = g.ShortestPath("foo.v1.1.1", "foo.v1.2.0")
+		require.EqualError(t, err, `no upgrade path from "foo.v1.1.1" to "foo.v1.2.0": upgrades from "foo.v1.1.1" end at "foo.v2.0.0", which no bundle in channel "stable" upgrades`)
+
+		_, err = g.ShortestPath("foo.v1.2.0", "foo.v1.1.1")
+		require.EqualError(t, err, `no upgrade path from "foo.v1.2.0" to "foo.v1.1.1": "foo.v1.1.1" (1.1.1) is older than "foo.v1.2.0" (1.2.0) and is not reachable by upgrades`)
+
+		_, err = g.ShortestPath("foo.v0.0.1", "foo.v1.2.0")
+		require.EqualError(t, err, `no upgrade path from "foo.v0.0.1" to "foo.v1.2.0": bundle "foo.v0.0.1" is not in channel "stable"`)
+	})
+
+	t.Run("NoUniqueHead", func(t *testing.T) {
+		ch := newGraphTestChannel(
+			graphTestBundle("1.0.0", "", ""),
+			graphTestBundle("1.1.0", "", ""),
+		)
+		_, err := ch.Graph().PathToHead("foo.v1.0.0")
+		require.EqualError(t, err, `no upgrade path from "foo.v1.0.0" to "": channel "stable" has no unique head: multiple channel heads found in graph: foo.v1.0.0, foo.v1.1.0`)
+	})
+}
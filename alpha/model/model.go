@@ -31,7 +31,7 @@ func init() {
 type Model map[string]*Package
 
 func (m Model) Validate() error {
-	result := newValidationError("invalid index")
+	result := newValidationError("invalid index").forObject(kindModel, "")
 
 	for name, pkg := range m {
 		if name != pkg.Name {
@@ -51,10 +51,12 @@ type Package struct {
 	DefaultChannel *Channel
 	Channels       map[string]*Channel
 	Deprecation    *Deprecation
+	// Others are the blobs of custom schemas that belong to the package.
+	Others []Other
 }
 
 func (p *Package) Validate() error {
-	result := newValidationError(fmt.Sprintf("invalid package %q", p.Name))
+	result := newValidationError(fmt.Sprintf("invalid package %q", p.Name)).forObject(kindPackage, p.Name)
 
 	if p.Name == "" {
 		result.subErrors = append(result.subErrors, errors.New("package name must not be empty"))
@@ -223,7 +225,7 @@ func (c Channel) Head() (*Bundle, error) {
 }
 
 func (c *Channel) Validate() error {
-	result := newValidationError(fmt.Sprintf("invalid channel %q", c.Name))
+	result := newValidationError(fmt.Sprintf("invalid channel %q", c.Name)).forObject(kindChannel, c.Name)
 
 	if c.Name == "" {
 		result.subErrors = append(result.subErrors, errors.New("channel name must not be empty"))
@@ -378,7 +380,7 @@ func (b *Bundle) Compare(other *Bundle) int {
 }
 
 func (b *Bundle) Validate() error {
-	result := newValidationError(fmt.Sprintf("invalid bundle %q", b.Name))
+	result := newValidationError(fmt.Sprintf("invalid bundle %q", b.Name)).forObject(kindBundle, b.Name)
 
 	if b.Name == "" {
 		result.subErrors = append(result.subErrors, errors.New("name must be set"))
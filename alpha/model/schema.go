@@ -0,0 +1,144 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// Other is a blob of a custom schema that belongs to a package.
+type Other struct {
+	Schema string
+	Name   string
+	Blob   json.RawMessage
+}
+
+// ValidateOthers checks the blobs of custom schemas of each package with the
+// validators registered for their schema. Unlike Validate, which checks the
+// structure of the catalog, it is only run by catalog validation and linting,
+// so that catalogs with blobs that do not pass their validator can still be
+// served and transformed.
+func (m Model) ValidateOthers() error {
+	result := newValidationError("invalid index").forObject(kindModel, "")
+
+	for _, pkg := range m {
+		pkgErr := newValidationError(fmt.Sprintf("invalid package %q", pkg.Name)).forObject(kindPackage, pkg.Name)
+		for _, o := range pkg.Others {
+			if err := o.Validate(); err != nil {
+				pkgErr.subErrors = append(pkgErr.subErrors, err)
+			}
+		}
+		if err := pkgErr.orNil(); err != nil {
+			result.subErrors = append(result.subErrors, err)
+		}
+	}
+	return result.orNil()
+}
+
+// Validate checks the blob with the validator registered for its schema, if
+// any. Blobs of schemas without a registered validator are always valid.
+func (o Other) Validate() error {
+	message := fmt.Sprintf("invalid %q blob", o.Schema)
+	if o.Name != "" {
+		message = fmt.Sprintf("invalid %q blob %q", o.Schema, o.Name)
+	}
+	result := newValidationError(message).forObject(kindOther, o.Schema)
+	if v := schemaValidator(o.Schema); v != nil {
+		if err := v.ValidateBlob(o.Blob); err != nil {
+			result.subErrors = append(result.subErrors, err)
+		}
+	}
+	return result.orNil()
+}
+
+// BlobValidator validates the blobs of a custom schema.
+type BlobValidator interface {
+	ValidateBlob(blob json.RawMessage) error
+}
+
+// BlobValidatorFunc adapts a function to a BlobValidator.
+type BlobValidatorFunc func(blob json.RawMessage) error
+
+func (f BlobValidatorFunc) ValidateBlob(blob json.RawMessage) error {
+	return f(blob)
+}
+
+var (
+	schemaValidatorsMu sync.RWMutex
+	schemaValidators   = map[string]BlobValidator{}
+)
+
+// RegisterSchema registers the validator of the blobs of a custom schema.
+// Registered schemas are validated by Model.ValidateOthers, and therefore by
+// opm validate and the custom-schema-invalid lint rule. It panics if the
+// schema is already registered.
+func RegisterSchema(schema string, v BlobValidator) {
+	schemaValidatorsMu.Lock()
+	defer schemaValidatorsMu.Unlock()
+	if _, ok := schemaValidators[schema]; ok {
+		panic(fmt.Sprintf("schema %q is already registered", schema))
+	}
+	schemaValidators[schema] = v
+}
+
+func schemaValidator(schema string) BlobValidator {
+	schemaValidatorsMu.RLock()
+	defer schemaValidatorsMu.RUnlock()
+	return schemaValidators[schema]
+}
+
+// JSONSchemaValidator returns a validator of blobs against a JSON Schema
+// document, in the dialect of OpenAPI v2 schemas.
+func JSONSchemaValidator(jsonSchema []byte) (BlobValidator, error) {
+	s := &spec.Schema{}
+	if err := json.Unmarshal(jsonSchema, s); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %v", err)
+	}
+	v := validate.NewSchemaValidator(s, nil, "", strfmt.Default)
+	return BlobValidatorFunc(func(blob json.RawMessage) error {
+		var data interface{}
+		if err := json.Unmarshal(blob, &data); err != nil {
+			return err
+		}
+		if result := v.Validate(data); !result.IsValid() {
+			return errors.Join(result.Errors...)
+		}
+		return nil
+	}), nil
+}
+
+// TypeValidator returns a validator that decodes blobs into a T, rejecting
+// unknown fields. The schema and package keys of blobs are not decoded, so T
+// need not declare them. If *T has a Validate() error method, it is called
+// on the decoded value.
+func TypeValidator[T any]() BlobValidator {
+	return BlobValidatorFunc(func(blob json.RawMessage) error {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(blob, &fields); err != nil {
+			return err
+		}
+		delete(fields, "schema")
+		delete(fields, "package")
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+
+		var t T
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&t); err != nil {
+			return err
+		}
+		if v, ok := any(&t).(interface{ Validate() error }); ok {
+			return v.Validate()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testOwner struct {
+	Name  string `json:"name,omitempty"`
+	Team  string `json:"team"`
+	Email string `json:"email,omitempty"`
+}
+
+func (o *testOwner) Validate() error {
+	if o.Team == "" {
+		return errors.New("team is required")
+	}
+	return nil
+}
+
+func TestTypeValidator(t *testing.T) {
+	type spec struct {
+		name          string
+		blob          string
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name: "Success",
+			blob: `{"schema":"example.owner","package":"foo","team":"storage","email":"storage@example.com"}`,
+		},
+		{
+			name:          "Fail/UnknownField",
+			blob:          `{"schema":"example.owner","package":"foo","team":"storage","pager":"none"}`,
+			expectedError: `json: unknown field "pager"`,
+		},
+		{
+			name:          "Fail/WrongType",
+			blob:          `{"schema":"example.owner","package":"foo","team":1}`,
+			expectedError: "cannot unmarshal number",
+		},
+		{
+			name:          "Fail/Validate",
+			blob:          `{"schema":"example.owner","package":"foo"}`,
+			expectedError: "team is required",
+		},
+	}
+	v := TypeValidator[testOwner]()
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			err := v.ValidateBlob(json.RawMessage(s.blob))
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestJSONSchemaValidator(t *testing.T) {
+	type spec struct {
+		name          string
+		blob          string
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name: "Success",
+			blob: `{"schema":"example.tier","package":"foo","tier":"premium"}`,
+		},
+		{
+			name:          "Fail/Enum",
+			blob:          `{"schema":"example.tier","package":"foo","tier":"gold"}`,
+			expectedError: "tier in body should be one of [premium standard]",
+		},
+		{
+			name:          "Fail/Required",
+			blob:          `{"schema":"example.tier","package":"foo"}`,
+			expectedError: "tier in body is required",
+		},
+	}
+	v, err := JSONSchemaValidator([]byte(`{
+  "type": "object",
+  "required": ["tier"],
+  "properties": {
+    "tier": {"type": "string", "enum": ["premium", "standard"]}
+  }
+}`))
+	require.NoError(t, err)
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			err := v.ValidateBlob(json.RawMessage(s.blob))
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	_, err = JSONSchemaValidator([]byte(`{"type": 1}`))
+	require.ErrorContains(t, err, "parse JSON schema")
+}
+
+func TestModelValidateOthers(t *testing.T) {
+	RegisterSchema("test.model.owner", TypeValidator[testOwner]())
+	require.Panics(t, func() { RegisterSchema("test.model.owner", TypeValidator[testOwner]()) })
+
+	pkg := &Package{Name: "foo", Others: []Other{
+		{Schema: "test.model.owner", Name: "storage", Blob: json.RawMessage(`{"schema":"test.model.owner","package":"foo","name":"storage","team":"storage"}`)},
+		{Schema: "test.model.owner", Blob: json.RawMessage(`{"schema":"test.model.owner","package":"foo"}`)},
+		{Schema: "test.model.unregistered", Blob: json.RawMessage(`{"schema":"test.model.unregistered","package":"foo","anything":"goes"}`)},
+	}}
+	ch := &Channel{Package: pkg, Name: "alpha", Bundles: map[string]*Bundle{}}
+	pkg.Channels = map[string]*Channel{"alpha": ch}
+	pkg.DefaultChannel = ch
+
+	// Custom schemas are not checked by Validate.
+	issues, _ := ValidationIssues(pkg.Validate())
+	for _, issue := range issues {
+		require.Empty(t, issue.Schema)
+	}
+
+	issues, ok := ValidationIssues(Model{"foo": pkg}.ValidateOthers())
+	require.True(t, ok)
+	require.Contains(t, issues, ValidationIssue{
+		Package: "foo",
+		Schema:  "test.model.owner",
+		Message: `invalid "test.model.owner" blob: team is required`,
+	})
+	for _, issue := range issues {
+		require.NotEqual(t, "test.model.unregistered", issue.Schema)
+		require.NotContains(t, issue.Message, `"storage"`)
+	}
+}
@@ -0,0 +1,240 @@
+package composite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/template/api"
+)
+
+// Schema
+const schema string = "olm.template.composite"
+
+// Template types
+
+// CompositeTemplateData lists the templates whose rendered catalogs are merged
+// into one catalog.
+type CompositeTemplateData struct {
+	Schema     string      `json:"schema"`
+	Components []Component `json:"components"`
+}
+
+// Component is a template that is part of a composite template.
+type Component struct {
+	// Path is the path of the template file. Relative paths are relative to
+	// the directory of the composite template.
+	Path string `json:"path"`
+	// Type is the template type, e.g. basic, semver or substitutes. If empty,
+	// it is detected from the schema of the template.
+	Type string `json:"type,omitempty"`
+}
+
+// Creator creates component templates. template.Registry implements it.
+type Creator interface {
+	CreateTemplateBySchema(reader io.Reader, renderBundle api.BundleRenderer) (api.Template, io.Reader, error)
+	CreateTemplateByType(templateType string, renderBundle api.BundleRenderer) (api.Template, error)
+}
+
+type compositeTemplate struct {
+	renderBundle api.BundleRenderer
+	creator      Creator
+	baseDir      string
+	concurrency  int
+}
+
+// Template functions
+
+// RenderBundle expands the bundle image reference into a DeclarativeConfig fragment.
+func (t *compositeTemplate) RenderBundle(ctx context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	return t.renderBundle(ctx, image)
+}
+
+// Render renders all component templates concurrently, and merges their
+// catalogs in the order of the components. It fails if more than one
+// component defines the same package, channel or bundle.
+func (t *compositeTemplate) Render(ctx context.Context, reader io.Reader) (*declcfg.DeclarativeConfig, error) {
+	ct, err := parseSpec(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgs := make([]*declcfg.DeclarativeConfig, len(ct.Components))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(t.concurrency)
+	for i, c := range ct.Components {
+		eg.Go(func() error {
+			cfg, err := t.renderComponent(ctx, c)
+			if err != nil {
+				return fmt.Errorf("render component %q: %v", c.Path, err)
+			}
+			cfgs[i] = cfg
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	if err := checkConflicts(ct.Components, cfgs); err != nil {
+		return nil, err
+	}
+	out := &declcfg.DeclarativeConfig{}
+	for _, cfg := range cfgs {
+		out.Merge(cfg)
+	}
+	return out, nil
+}
+
+// Schema returns the schema identifier for this template type
+func (t *compositeTemplate) Schema() string {
+	return schema
+}
+
+// Type returns the registration type for this template
+func (t *compositeTemplate) Type() string {
+	return api.TypeFromSchema(schema)
+}
+
+// Helper functions
+
+func (t *compositeTemplate) renderComponent(ctx context.Context, c Component) (*declcfg.DeclarativeConfig, error) {
+	path := c.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.baseDir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		tmpl         api.Template
+		renderReader io.Reader = f
+	)
+	if c.Type != "" {
+		tmpl, err = t.creator.CreateTemplateByType(c.Type, t.renderBundle)
+	} else {
+		tmpl, renderReader, err = t.creator.CreateTemplateBySchema(f, t.renderBundle)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.Schema() == schema {
+		return nil, errors.New("composite templates cannot be nested")
+	}
+	return tmpl.Render(ctx, renderReader)
+}
+
+func parseSpec(reader io.Reader) (*CompositeTemplateData, error) {
+	ct := &CompositeTemplateData{}
+	ctDoc := json.RawMessage{}
+	ctDecoder := yaml.NewYAMLOrJSONDecoder(reader, 4096)
+	if err := ctDecoder.Decode(&ctDoc); err != nil {
+		return nil, fmt.Errorf("decoding template schema: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(ctDoc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(ct); err != nil {
+		return nil, fmt.Errorf("unmarshalling template: %v", err)
+	}
+
+	if ct.Schema != schema {
+		return nil, fmt.Errorf("template has unknown schema (%q), should be %q", ct.Schema, schema)
+	}
+	if len(ct.Components) == 0 {
+		return nil, fmt.Errorf("template has no components")
+	}
+	for i, c := range ct.Components {
+		if c.Path == "" {
+			return nil, fmt.Errorf("component %d has no path", i)
+		}
+	}
+	return ct, nil
+}
+
+// checkConflicts returns an error that lists every package, channel, bundle
+// and package deprecation that is rendered by more than one component.
+func checkConflicts(components []Component, cfgs []*declcfg.DeclarativeConfig) error {
+	owners := map[string]int{}
+	var errs []error
+	claim := func(key string, i int) {
+		owner, ok := owners[key]
+		if !ok {
+			owners[key] = i
+			return
+		}
+		if owner != i {
+			errs = append(errs, fmt.Errorf("%s is rendered by components %q and %q", key, components[owner].Path, components[i].Path))
+		}
+	}
+	for i, cfg := range cfgs {
+		for _, p := range cfg.Packages {
+			claim(fmt.Sprintf("package %q", p.Name), i)
+		}
+		for _, ch := range cfg.Channels {
+			claim(fmt.Sprintf("channel %q of package %q", ch.Name, ch.Package), i)
+		}
+		for _, b := range cfg.Bundles {
+			claim(fmt.Sprintf("bundle %q of package %q", b.Name, b.Package), i)
+		}
+		for _, d := range cfg.Deprecations {
+			claim(fmt.Sprintf("deprecations of package %q", d.Package), i)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("conflicting components: %v", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Factory types
+
+// Factory creates composite templates.
+type Factory struct {
+	// Creator creates the component templates.
+	Creator Creator
+	// BaseDir is the directory that relative component paths are relative
+	// to. If empty, it is the working directory.
+	BaseDir string
+	// Concurrency is the maximum number of components that are rendered at
+	// once. If zero, it is the number of CPUs.
+	Concurrency int
+}
+
+// Factory functions
+
+// CreateTemplate creates a new template instance with the given RenderBundle function
+func (f *Factory) CreateTemplate(renderBundle api.BundleRenderer) api.Template {
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	return &compositeTemplate{
+		renderBundle: renderBundle,
+		creator:      f.Creator,
+		baseDir:      f.BaseDir,
+		concurrency:  concurrency,
+	}
+}
+
+// Schema returns the schema supported by this factory
+func (f *Factory) Schema() string {
+	return schema
+}
+
+// Type returns the registration type for this factory
+func (f *Factory) Type() string {
+	return api.TypeFromSchema(schema)
+}
@@ -0,0 +1,192 @@
+package composite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/alpha/template"
+	"github.com/operator-framework/operator-registry/alpha/template/composite"
+)
+
+// renderTestBundle renders images named "<package>:v<version>".
+func renderTestBundle(_ context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	pkg, version, _ := strings.Cut(image, ":v")
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{{
+		Schema:     declcfg.SchemaBundle,
+		Name:       pkg + ".v" + version,
+		Package:    pkg,
+		Image:      image,
+		Properties: []property.Property{property.MustBuildPackage(pkg, version)},
+	}}}, nil
+}
+
+const fooBasic = `schema: olm.template.basic
+entries:
+  - schema: olm.package
+    name: foo
+    defaultChannel: stable
+  - schema: olm.channel
+    package: foo
+    name: stable
+    entries:
+      - name: foo.v0.1.0
+  - schema: olm.bundle
+    image: foo:v0.1.0
+`
+
+const barSemver = `schema: olm.semver
+stable:
+  bundles:
+    - image: bar:v1.0.0
+    - image: bar:v1.1.0
+`
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestRender(t *testing.T) {
+	type spec struct {
+		name             string
+		files            map[string]string
+		template         string
+		expectedPackages []string
+		expectedBundles  []string
+		expectedError    string
+	}
+	specs := []spec{
+		{
+			name:  "Success/MixedTypes",
+			files: map[string]string{"foo/basic.yaml": fooBasic, "bar/template.yaml": barSemver},
+			template: `schema: olm.template.composite
+components:
+  - path: foo/basic.yaml
+  - path: bar/template.yaml
+    type: semver
+`,
+			expectedPackages: []string{"foo", "bar"},
+			expectedBundles:  []string{"foo.v0.1.0", "bar.v1.0.0", "bar.v1.1.0"},
+		},
+		{
+			name:  "Fail/DuplicatePackage",
+			files: map[string]string{"foo/basic.yaml": fooBasic, "foo/copy.yaml": fooBasic},
+			template: `schema: olm.template.composite
+components:
+  - path: foo/basic.yaml
+  - path: foo/copy.yaml
+`,
+			expectedError: `conflicting components: package "foo" is rendered by components "foo/basic.yaml" and "foo/copy.yaml"
+channel "stable" of package "foo" is rendered by components "foo/basic.yaml" and "foo/copy.yaml"
+bundle "foo.v0.1.0" of package "foo" is rendered by components "foo/basic.yaml" and "foo/copy.yaml"`,
+		},
+		{
+			name:  "Fail/Nested",
+			files: map[string]string{"nested.yaml": "schema: olm.template.composite\ncomponents:\n  - path: foo.yaml\n"},
+			template: `schema: olm.template.composite
+components:
+  - path: nested.yaml
+`,
+			expectedError: `render component "nested.yaml": composite templates cannot be nested`,
+		},
+		{
+			name: "Fail/MissingComponent",
+			template: `schema: olm.template.composite
+components:
+  - path: missing.yaml
+`,
+			expectedError: `render component "missing.yaml": open`,
+		},
+		{
+			name: "Fail/UnknownField",
+			template: `schema: olm.template.composite
+components:
+  - file: foo.yaml
+`,
+			expectedError: `unmarshalling template: json: unknown field "file"`,
+		},
+		{
+			name:          "Fail/NoComponents",
+			template:      "schema: olm.template.composite\n",
+			expectedError: "template has no components",
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			dir := writeFiles(t, s.files)
+			tr := template.NewRegistry()
+			tr.Register(&composite.Factory{Creator: tr, BaseDir: dir})
+			tmpl, r, err := tr.CreateTemplateBySchema(strings.NewReader(s.template), renderTestBundle)
+			require.NoError(t, err)
+			cfg, err := tmpl.Render(context.Background(), r)
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			var packages, bundles []string
+			for _, p := range cfg.Packages {
+				packages = append(packages, p.Name)
+			}
+			for _, b := range cfg.Bundles {
+				bundles = append(bundles, b.Name)
+			}
+			require.Equal(t, s.expectedPackages, packages)
+			require.ElementsMatch(t, s.expectedBundles, bundles)
+			_, err = declcfg.ConvertToModel(*cfg)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRenderConcurrently(t *testing.T) {
+	files := map[string]string{}
+	var components strings.Builder
+	components.WriteString("schema: olm.template.composite\ncomponents:\n")
+	for _, pkg := range []string{"a", "b", "c", "d"} {
+		files[pkg+".yaml"] = strings.ReplaceAll(barSemver, "bar", pkg)
+		components.WriteString("  - path: " + pkg + ".yaml\n")
+	}
+	dir := writeFiles(t, files)
+
+	// Each bundle render waits until all components are rendering, which only
+	// finishes if the components are rendered at the same time. The bundles of
+	// a component may be rendered in any order, so a component counts as
+	// rendering from its first bundle on.
+	var (
+		started   sync.Map
+		rendering atomic.Int32
+	)
+	release := make(chan struct{})
+	renderBundle := func(ctx context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+		pkg, _, _ := strings.Cut(image, ":v")
+		if _, loaded := started.LoadOrStore(pkg, struct{}{}); !loaded && rendering.Add(1) == 4 {
+			close(release)
+		}
+		<-release
+		return renderTestBundle(ctx, image)
+	}
+
+	tr := template.NewRegistry()
+	tmpl := (&composite.Factory{Creator: tr, BaseDir: dir, Concurrency: 4}).CreateTemplate(renderBundle)
+	cfg, err := tmpl.Render(context.Background(), strings.NewReader(components.String()))
+	require.NoError(t, err)
+	require.Len(t, cfg.Packages, 4)
+	require.Len(t, cfg.Bundles, 8)
+}
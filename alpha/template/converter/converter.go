@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/operator-framework/operator-registry/alpha/template/basic"
+	"github.com/operator-framework/operator-registry/alpha/template/semver"
 	"github.com/operator-framework/operator-registry/alpha/template/substitutes"
 	"github.com/operator-framework/operator-registry/pkg/image"
 )
@@ -18,6 +19,10 @@ type Converter struct {
 	OutputFormat            string
 	Registry                image.Registry
 	DestinationTemplateType string // TODO: when we have a template factory, we can pass it here
+	// Report, if set, receives a description of how the catalog rendered
+	// from the generated template differs from the input, for template types
+	// that cannot always reproduce their input.
+	Report io.Writer
 }
 
 func (c *Converter) Convert() error {
@@ -44,6 +49,22 @@ func (c *Converter) Convert() error {
 		if err != nil {
 			return err
 		}
+	case "semver":
+		var st *semver.SemverTemplateData
+		var report *semver.ConversionReport
+		st, report, err = semver.FromReader(c.FbcReader)
+		if err != nil {
+			return err
+		}
+		b, err = json.MarshalIndent(st, "", "    ")
+		if err != nil {
+			return err
+		}
+		if c.Report != nil {
+			if _, err := fmt.Fprint(c.Report, report); err != nil {
+				return err
+			}
+		}
 	default:
 		// usage pattern prevents us from getting here, so if we do it's a programmer failure and we should panic
 		panic(fmt.Sprintf("unknown template type %q", c.DestinationTemplateType))
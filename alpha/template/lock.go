@@ -0,0 +1,260 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+const LockSchema = "olm.template.lock"
+
+// Lock records the digest that each bundle reference of a template resolved
+// to, and the name of the bundle it rendered, so that the template renders
+// the same catalog until the lock is updated.
+type Lock struct {
+	Schema  string         `json:"schema"`
+	Bundles []LockedBundle `json:"bundles"`
+}
+
+type LockedBundle struct {
+	// Image is the bundle reference, as written in the template.
+	Image string `json:"image"`
+	// Digest is the manifest digest that Image resolved to.
+	Digest digest.Digest `json:"digest"`
+	// Name is the name of the bundle rendered from Image.
+	Name string `json:"name"`
+}
+
+// DigestResolver returns the manifest digest that an image reference
+// currently points to.
+type DigestResolver func(ctx context.Context, imageRef string) (digest.Digest, error)
+
+// StaleLockError is returned when a lock does not match the template it is
+// used with.
+type StaleLockError struct {
+	Reasons []string
+}
+
+func (e *StaleLockError) Error() string {
+	return fmt.Sprintf("template lock is stale: %s", strings.Join(e.Reasons, "; "))
+}
+
+// LoadLock reads a YAML or JSON lock.
+func LoadLock(r io.Reader) (*Lock, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lock := &Lock{}
+	if err := yaml.UnmarshalStrict(data, lock); err != nil {
+		return nil, fmt.Errorf("parse template lock: %v", err)
+	}
+	if lock.Schema != LockSchema {
+		return nil, fmt.Errorf("template lock has unknown schema (%q), should be %q", lock.Schema, LockSchema)
+	}
+	seen := map[string]struct{}{}
+	for _, b := range lock.Bundles {
+		if _, ok := seen[b.Image]; ok {
+			return nil, fmt.Errorf("template lock has duplicate image %q", b.Image)
+		}
+		seen[b.Image] = struct{}{}
+		if err := b.Digest.Validate(); err != nil {
+			return nil, fmt.Errorf("template lock has invalid digest for image %q: %v", b.Image, err)
+		}
+	}
+	return lock, nil
+}
+
+// LoadLockFile reads the lock at path. If the file does not exist, it
+// returns nil and no error.
+func LoadLockFile(path string) (*Lock, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadLock(f)
+}
+
+// WriteLock writes lock as YAML, with its bundles sorted by image.
+func WriteLock(lock Lock, w io.Writer) error {
+	lock.Schema = LockSchema
+	lock.Bundles = slices.Clone(lock.Bundles)
+	slices.SortFunc(lock.Bundles, func(a, b LockedBundle) int {
+		return strings.Compare(a.Image, b.Image)
+	})
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	data, err = yaml.JSONToYAML(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LockingRenderer renders bundles by the digests recorded in a lock.
+//
+// Without update, every bundle reference that a template renders must be in
+// the lock, and must render a bundle with the locked name; the reference is
+// never resolved again. With update, each reference is resolved to its
+// current digest, and the resulting lock only contains the references that
+// were rendered.
+//
+// In both cases, bundles are rendered from their digest references, so the
+// rendered bundle images do not depend on where tags point.
+type LockingRenderer struct {
+	lock         map[string]LockedBundle
+	resolve      DigestResolver
+	renderBundle BundleRenderer
+	update       bool
+
+	mu       sync.Mutex
+	rendered map[string]LockedBundle
+	stale    []string
+}
+
+// NewLockingRenderer returns a LockingRenderer that renders bundles with
+// renderBundle. A nil lock is treated as an empty lock.
+func NewLockingRenderer(lock *Lock, resolve DigestResolver, renderBundle BundleRenderer, update bool) *LockingRenderer {
+	r := &LockingRenderer{
+		lock:         map[string]LockedBundle{},
+		resolve:      resolve,
+		renderBundle: renderBundle,
+		update:       update,
+		rendered:     map[string]LockedBundle{},
+	}
+	if lock != nil {
+		for _, b := range lock.Bundles {
+			r.lock[b.Image] = b
+		}
+	}
+	return r
+}
+
+// RenderBundle is a BundleRenderer.
+func (r *LockingRenderer) RenderBundle(ctx context.Context, imageRef string) (*declcfg.DeclarativeConfig, error) {
+	r.mu.Lock()
+	locked, ok := r.lock[imageRef]
+	r.mu.Unlock()
+
+	var dgst digest.Digest
+	switch {
+	case r.update:
+		var err error
+		dgst, err = r.resolve(ctx, imageRef)
+		if err != nil {
+			return nil, fmt.Errorf("resolve digest of %q: %v", imageRef, err)
+		}
+	case ok:
+		dgst = locked.Digest
+	default:
+		r.markStale(fmt.Sprintf("image %q is not locked", imageRef))
+		return nil, r.StaleError()
+	}
+
+	pinned, err := pinnedReference(imageRef, dgst)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := r.renderBundle(ctx, pinned)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Bundles) != 1 {
+		return nil, fmt.Errorf("expected image %q to render exactly one bundle, got %d", imageRef, len(cfg.Bundles))
+	}
+	name := cfg.Bundles[0].Name
+	if !r.update && name != locked.Name {
+		r.markStale(fmt.Sprintf("image %q rendered bundle %q, locked as %q", imageRef, name, locked.Name))
+		return nil, r.StaleError()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rendered[imageRef] = LockedBundle{Image: imageRef, Digest: dgst, Name: name}
+	return cfg, nil
+}
+
+// Lock returns the lock for the bundles rendered so far. Without update, it
+// fails if the lock has entries that were not rendered.
+func (r *LockingRenderer) Lock() (*Lock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.update {
+		for image := range r.lock {
+			if _, ok := r.rendered[image]; !ok {
+				r.stale = append(r.stale, fmt.Sprintf("locked image %q is not in the template", image))
+			}
+		}
+		if len(r.stale) > 0 {
+			return nil, r.staleErrorLocked()
+		}
+	}
+	lock := &Lock{Schema: LockSchema}
+	for _, b := range r.rendered {
+		lock.Bundles = append(lock.Bundles, b)
+	}
+	slices.SortFunc(lock.Bundles, func(a, b LockedBundle) int {
+		return strings.Compare(a.Image, b.Image)
+	})
+	return lock, nil
+}
+
+func (r *LockingRenderer) markStale(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stale = append(r.stale, reason)
+}
+
+// StaleError returns a *StaleLockError if rendering found that the lock does
+// not match the template, and nil otherwise. Templates may wrap the errors of
+// their bundle renderer, so this is the reliable way to detect a stale lock
+// after a failed render.
+func (r *LockingRenderer) StaleError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.stale) == 0 {
+		return nil
+	}
+	return r.staleErrorLocked()
+}
+
+func (r *LockingRenderer) staleErrorLocked() error {
+	reasons := slices.Clone(r.stale)
+	slices.Sort(reasons)
+	return &StaleLockError{Reasons: slices.Compact(reasons)}
+}
+
+// pinnedReference replaces the tag of imageRef, if any, with dgst.
+func pinnedReference(imageRef string, dgst digest.Digest) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference %q: %v", imageRef, err)
+	}
+	if canonical, ok := named.(reference.Canonical); ok && canonical.Digest() != dgst {
+		return "", fmt.Errorf("image reference %q does not match digest %s", imageRef, dgst)
+	}
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return "", err
+	}
+	return reference.FamiliarString(pinned), nil
+}
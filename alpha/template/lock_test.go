@@ -0,0 +1,162 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+// lockTestRegistry maps tags to digests, and digest references to bundle names.
+type lockTestRegistry struct {
+	tags    map[string]digest.Digest
+	bundles map[string]string
+}
+
+func (r *lockTestRegistry) resolve(_ context.Context, ref string) (digest.Digest, error) {
+	if dgst, ok := r.tags[ref]; ok {
+		return dgst, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (r *lockTestRegistry) render(_ context.Context, ref string) (*declcfg.DeclarativeConfig, error) {
+	name, ok := r.bundles[ref]
+	if !ok {
+		return nil, fmt.Errorf("render %q: not found", ref)
+	}
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{{Schema: declcfg.SchemaBundle, Name: name, Image: ref}}}, nil
+}
+
+func TestLockingRenderer(t *testing.T) {
+	d1 := digest.FromString("foo.v1")
+	d2 := digest.FromString("foo.v2")
+	d3 := digest.FromString("bar.v1")
+	registry := &lockTestRegistry{
+		tags: map[string]digest.Digest{
+			"quay.io/foo/bundle:latest": d2,
+			"quay.io/bar/bundle:v1":     d3,
+		},
+		bundles: map[string]string{
+			"quay.io/foo/bundle@" + d1.String(): "foo.v1",
+			"quay.io/foo/bundle@" + d2.String(): "foo.v2",
+			"quay.io/bar/bundle@" + d3.String(): "bar.v1",
+		},
+	}
+	lock := &Lock{Schema: LockSchema, Bundles: []LockedBundle{
+		{Image: "quay.io/foo/bundle:latest", Digest: d1, Name: "foo.v1"},
+	}}
+
+	type spec struct {
+		name          string
+		lock          *Lock
+		update        bool
+		refs          []string
+		expectedNames []string
+		expectedLock  *Lock
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name:          "Success/FirstRenderRecordsLock",
+			update:        true,
+			refs:          []string{"quay.io/foo/bundle:latest", "quay.io/bar/bundle:v1"},
+			expectedNames: []string{"foo.v2", "bar.v1"},
+			expectedLock: &Lock{Schema: LockSchema, Bundles: []LockedBundle{
+				{Image: "quay.io/bar/bundle:v1", Digest: d3, Name: "bar.v1"},
+				{Image: "quay.io/foo/bundle:latest", Digest: d2, Name: "foo.v2"},
+			}},
+		},
+		{
+			name:          "Success/LockedDigestIsReused",
+			lock:          lock,
+			refs:          []string{"quay.io/foo/bundle:latest"},
+			expectedNames: []string{"foo.v1"},
+			expectedLock:  lock,
+		},
+		{
+			name:          "Success/UpdateResolvesAgain",
+			lock:          lock,
+			update:        true,
+			refs:          []string{"quay.io/foo/bundle:latest"},
+			expectedNames: []string{"foo.v2"},
+			expectedLock: &Lock{Schema: LockSchema, Bundles: []LockedBundle{
+				{Image: "quay.io/foo/bundle:latest", Digest: d2, Name: "foo.v2"},
+			}},
+		},
+		{
+			name:          "Fail/ImageNotLocked",
+			lock:          lock,
+			refs:          []string{"quay.io/foo/bundle:latest", "quay.io/bar/bundle:v1"},
+			expectedError: `template lock is stale: image "quay.io/bar/bundle:v1" is not locked`,
+		},
+		{
+			name:          "Fail/LockedImageNotInTemplate",
+			lock:          lock,
+			refs:          []string{},
+			expectedError: `template lock is stale: locked image "quay.io/foo/bundle:latest" is not in the template`,
+		},
+		{
+			name: "Fail/BundleNameChanged",
+			lock: &Lock{Schema: LockSchema, Bundles: []LockedBundle{
+				{Image: "quay.io/foo/bundle:latest", Digest: d1, Name: "foo.v0"},
+			}},
+			refs:          []string{"quay.io/foo/bundle:latest"},
+			expectedError: `template lock is stale: image "quay.io/foo/bundle:latest" rendered bundle "foo.v1", locked as "foo.v0"`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			r := NewLockingRenderer(s.lock, registry.resolve, registry.render, s.update)
+			var names []string
+			var err error
+			for _, ref := range s.refs {
+				var cfg *declcfg.DeclarativeConfig
+				cfg, err = r.RenderBundle(context.Background(), ref)
+				if err != nil {
+					break
+				}
+				require.True(t, strings.Contains(cfg.Bundles[0].Image, "@sha256:"), "rendered image %q is not pinned", cfg.Bundles[0].Image)
+				names = append(names, cfg.Bundles[0].Name)
+			}
+			var actual *Lock
+			if err == nil {
+				actual, err = r.Lock()
+			}
+			if s.expectedError != "" {
+				require.EqualError(t, err, s.expectedError)
+				var staleErr *StaleLockError
+				require.ErrorAs(t, r.StaleError(), &staleErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, r.StaleError())
+			require.Equal(t, s.expectedNames, names)
+			require.Equal(t, s.expectedLock, actual)
+		})
+	}
+}
+
+func TestLockRoundTrip(t *testing.T) {
+	lock := Lock{Bundles: []LockedBundle{
+		{Image: "quay.io/foo/bundle:v2", Digest: digest.FromString("foo.v2"), Name: "foo.v2"},
+		{Image: "quay.io/foo/bundle:v1", Digest: digest.FromString("foo.v1"), Name: "foo.v1"},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteLock(lock, &buf))
+	actual, err := LoadLock(&buf)
+	require.NoError(t, err)
+	require.Equal(t, &Lock{Schema: LockSchema, Bundles: []LockedBundle{lock.Bundles[1], lock.Bundles[0]}}, actual)
+
+	_, err = LoadLock(strings.NewReader("schema: olm.template.basic\n"))
+	require.EqualError(t, err, `template lock has unknown schema ("olm.template.basic"), should be "olm.template.lock"`)
+	_, err = LoadLock(strings.NewReader("schema: olm.template.lock\nbundles:\n- image: foo\n  digest: bad\n  name: foo\n"))
+	require.EqualError(t, err, `template lock has invalid digest for image "foo": invalid checksum digest format`)
+}
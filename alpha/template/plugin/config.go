@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	APIVersionV1Alpha1 = "olm.operatorframework.io/template-plugins/v1alpha1"
+	KindConfiguration  = "TemplatePluginConfiguration"
+
+	// ExecutablePrefix is the name prefix of the executables that Discover
+	// finds, e.g. opm-template-calendar.
+	ExecutablePrefix = "opm-template-"
+)
+
+// Configuration names template plugin executables.
+//
+// Example:
+//
+//	apiVersion: olm.operatorframework.io/template-plugins/v1alpha1
+//	kind: TemplatePluginConfiguration
+//	plugins:
+//	  - path: ./bin/release-calendar
+//	  - path: /usr/local/bin/channel-policy
+type Configuration struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Plugins    []Plugin `json:"plugins"`
+}
+
+type Plugin struct {
+	// Path is the path of the plugin executable. Relative paths are relative
+	// to the directory of the configuration file.
+	Path string `json:"path"`
+}
+
+// LoadConfiguration reads a YAML or JSON plugin configuration.
+func LoadConfiguration(r io.Reader) (*Configuration, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Configuration{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse template plugin configuration: %v", err)
+	}
+	if cfg.APIVersion != APIVersionV1Alpha1 || cfg.Kind != KindConfiguration {
+		return nil, fmt.Errorf("invalid template plugin configuration: unsupported apiVersion %q and kind %q, expected %q and %q", cfg.APIVersion, cfg.Kind, APIVersionV1Alpha1, KindConfiguration)
+	}
+	for i, p := range cfg.Plugins {
+		if p.Path == "" {
+			return nil, fmt.Errorf("invalid template plugin configuration: plugin %d has no path", i)
+		}
+	}
+	return cfg, nil
+}
+
+// LoadConfigurationFile reads the plugin configuration at path, and resolves
+// the relative plugin paths in it against the directory of path.
+func LoadConfigurationFile(path string) (*Configuration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, err := LoadConfiguration(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range cfg.Plugins {
+		if !filepath.IsAbs(p.Path) {
+			cfg.Plugins[i].Path = filepath.Join(filepath.Dir(path), p.Path)
+		}
+	}
+	return cfg, nil
+}
+
+// Factories describes the configured plugins.
+func (c *Configuration) Factories(ctx context.Context) ([]*Factory, error) {
+	paths := make([]string, 0, len(c.Plugins))
+	for _, p := range c.Plugins {
+		paths = append(paths, p.Path)
+	}
+	return newFactories(ctx, paths)
+}
+
+// Discover describes the executables in dirs whose names start with
+// ExecutablePrefix. Directories that do not exist are skipped.
+func Discover(ctx context.Context, dirs []string) ([]*Factory, error) {
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("discover template plugins: %v", err)
+		}
+		for _, e := range entries {
+			if !strings.HasPrefix(e.Name(), ExecutablePrefix) {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			// Follow symlinks, so that linked executables are found.
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	return newFactories(ctx, paths)
+}
+
+// newFactories describes the plugins at paths. Each schema may only be
+// rendered by one plugin.
+func newFactories(ctx context.Context, paths []string) ([]*Factory, error) {
+	var factories []*Factory
+	bySchema := map[string]*Factory{}
+	for _, path := range paths {
+		f, err := NewFactory(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := bySchema[f.Schema()]; ok {
+			return nil, fmt.Errorf("template plugins %q and %q both render schema %q", other.Path(), f.Path(), f.Schema())
+		}
+		bySchema[f.Schema()] = f
+		factories = append(factories, f)
+	}
+	sort.Slice(factories, func(i, j int) bool {
+		return factories[i].Schema() < factories[j].Schema()
+	})
+	return factories, nil
+}
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfiguration(t *testing.T) {
+	type spec struct {
+		name          string
+		input         string
+		expectedPaths []string
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name: "Success",
+			input: `apiVersion: olm.operatorframework.io/template-plugins/v1alpha1
+kind: TemplatePluginConfiguration
+plugins:
+  - path: ./bin/release-calendar
+  - path: /usr/local/bin/channel-policy
+`,
+			expectedPaths: []string{"./bin/release-calendar", "/usr/local/bin/channel-policy"},
+		},
+		{
+			name:          "Fail/Kind",
+			input:         "apiVersion: olm.operatorframework.io/template-plugins/v1alpha1\nkind: FilterConfiguration\n",
+			expectedError: "unsupported apiVersion",
+		},
+		{
+			name:          "Fail/NoPath",
+			input:         "apiVersion: olm.operatorframework.io/template-plugins/v1alpha1\nkind: TemplatePluginConfiguration\nplugins:\n  - {}\n",
+			expectedError: "plugin 0 has no path",
+		},
+		{
+			name:          "Fail/UnknownField",
+			input:         "apiVersion: olm.operatorframework.io/template-plugins/v1alpha1\nkind: TemplatePluginConfiguration\nplugins:\n  - name: foo\n",
+			expectedError: `unknown field "name"`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg, err := LoadConfiguration(strings.NewReader(s.input))
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var paths []string
+			for _, p := range cfg.Plugins {
+				paths = append(paths, p.Path)
+			}
+			require.Equal(t, s.expectedPaths, paths)
+		})
+	}
+}
+
+func TestLoadConfigurationFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0700))
+	require.NoError(t, os.Symlink(testExecutable(t), filepath.Join(dir, "bin", "list")))
+	configPath := filepath.Join(dir, "plugins.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`apiVersion: olm.operatorframework.io/template-plugins/v1alpha1
+kind: TemplatePluginConfiguration
+plugins:
+  - path: bin/list
+`), 0600))
+
+	cfg, err := LoadConfigurationFile(configPath)
+	require.NoError(t, err)
+	factories, err := cfg.Factories(context.Background())
+	require.NoError(t, err)
+	require.Len(t, factories, 1)
+	require.Equal(t, filepath.Join(dir, "bin", "list"), factories[0].Path())
+	require.Equal(t, testPluginSchema, factories[0].Schema())
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	exe := testExecutable(t)
+	require.NoError(t, os.Symlink(exe, filepath.Join(dir, "opm-template-list")))
+	// Not executable, or not named like a plugin
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "opm-template-readme"), []byte("not a plugin"), 0600))
+	require.NoError(t, os.Symlink(exe, filepath.Join(dir, "list")))
+
+	factories, err := Discover(context.Background(), []string{dir, filepath.Join(dir, "missing")})
+	require.NoError(t, err)
+	require.Len(t, factories, 1)
+	require.Equal(t, filepath.Join(dir, "opm-template-list"), factories[0].Path())
+
+	// Two plugins for the same schema
+	other := t.TempDir()
+	require.NoError(t, os.Symlink(exe, filepath.Join(other, "opm-template-list2")))
+	_, err = Discover(context.Background(), []string{dir, other})
+	require.ErrorContains(t, err, `both render schema "test.template.list"`)
+}
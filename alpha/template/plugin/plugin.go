@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/template/api"
+)
+
+// Factory creates templates that are rendered by a plugin executable.
+type Factory struct {
+	path   string
+	schema string
+	// Stderr receives the standard error of the plugin. If nil, it is
+	// os.Stderr.
+	Stderr io.Writer
+}
+
+// NewFactory runs the describe command of the plugin at path, and returns a
+// Factory for the schema it renders.
+func NewFactory(ctx context.Context, path string) (*Factory, error) {
+	cmd := exec.CommandContext(ctx, path, describeCommand)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("describe template plugin %q: %v: %s", path, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	var d Description
+	if err := json.Unmarshal(out, &d); err != nil {
+		return nil, fmt.Errorf("describe template plugin %q: parse description: %v", path, err)
+	}
+	if d.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("template plugin %q uses unsupported protocol version %q, expected %q", path, d.ProtocolVersion, ProtocolVersion)
+	}
+	if d.Schema == "" {
+		return nil, fmt.Errorf("template plugin %q has no schema", path)
+	}
+	return &Factory{path: path, schema: d.Schema}, nil
+}
+
+// Path returns the path of the plugin executable.
+func (f *Factory) Path() string {
+	return f.path
+}
+
+// CreateTemplate creates a new template instance with the given RenderBundle function
+func (f *Factory) CreateTemplate(renderBundle api.BundleRenderer) api.Template {
+	stderr := f.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	return &pluginTemplate{
+		path:         f.path,
+		schema:       f.schema,
+		stderr:       stderr,
+		renderBundle: renderBundle,
+	}
+}
+
+// Schema returns the schema supported by this factory
+func (f *Factory) Schema() string {
+	return f.schema
+}
+
+// Type returns the registration type for this factory
+func (f *Factory) Type() string {
+	return api.TypeFromSchema(f.schema)
+}
+
+type pluginTemplate struct {
+	path         string
+	schema       string
+	stderr       io.Writer
+	renderBundle api.BundleRenderer
+}
+
+// RenderBundle expands the bundle image reference into a DeclarativeConfig fragment.
+func (t *pluginTemplate) RenderBundle(ctx context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	return t.renderBundle(ctx, image)
+}
+
+// Render runs the plugin to render the template, and renders the bundles
+// that the plugin asks for.
+func (t *pluginTemplate) Render(ctx context.Context, reader io.Reader) (*declcfg.DeclarativeConfig, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, t.path, renderCommand)
+	cmd.Stderr = t.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start template plugin %q: %v", t.path, err)
+	}
+
+	var (
+		encMu sync.Mutex
+		enc   = json.NewEncoder(stdin)
+	)
+	send := func(msg Message) error {
+		encMu.Lock()
+		defer encMu.Unlock()
+		return enc.Encode(msg)
+	}
+
+	result, exchangeErr := t.exchange(ctx, stdout, send, string(data))
+	_ = stdin.Close()
+	waitErr := cmd.Wait()
+
+	switch {
+	case exchangeErr != nil && waitErr != nil:
+		return nil, fmt.Errorf("template plugin %q: %v: %v", t.path, exchangeErr, waitErr)
+	case exchangeErr != nil:
+		return nil, fmt.Errorf("template plugin %q: %v", t.path, exchangeErr)
+	case result.Error != "":
+		return nil, fmt.Errorf("template plugin %q: %s", t.path, result.Error)
+	case waitErr != nil:
+		return nil, fmt.Errorf("template plugin %q: %v", t.path, waitErr)
+	}
+	return fromMetas(result.Catalog)
+}
+
+// exchange sends the template to the plugin, and serves its renderBundle
+// requests until it sends its result.
+func (t *pluginTemplate) exchange(ctx context.Context, stdout io.Reader, send func(Message) error, template string) (*Message, error) {
+	if err := send(Message{Type: RenderMessageType, Template: template}); err != nil {
+		return nil, fmt.Errorf("send template: %v", err)
+	}
+
+	// Bundles that are still being rendered when the plugin sends its result
+	// are not needed anymore.
+	bundleCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	dec := json.NewDecoder(stdout)
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("exited without a result")
+			}
+			return nil, fmt.Errorf("read message: %v", err)
+		}
+		switch msg.Type {
+		case RenderBundleMessageType:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Failures to send are detected by the plugin, which is
+				// left without an answer.
+				_ = send(t.bundleMessage(bundleCtx, msg))
+			}()
+		case ResultMessageType:
+			return &msg, nil
+		default:
+			return nil, fmt.Errorf("unexpected message type %q", msg.Type)
+		}
+	}
+}
+
+func (t *pluginTemplate) bundleMessage(ctx context.Context, req Message) Message {
+	resp := Message{Type: BundleMessageType, ID: req.ID}
+	cfg, err := t.renderBundle(ctx, req.Image)
+	if err == nil {
+		resp.Catalog, err = toMetas(*cfg)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// Schema returns the schema identifier for this template type
+func (t *pluginTemplate) Schema() string {
+	return t.schema
+}
+
+// Type returns the registration type for this template
+func (t *pluginTemplate) Type() string {
+	return api.TypeFromSchema(t.schema)
+}
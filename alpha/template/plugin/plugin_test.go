@@ -0,0 +1,231 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/alpha/template/api"
+)
+
+const (
+	testPluginEnv    = "OPM_TEST_TEMPLATE_PLUGIN"
+	testPluginSchema = "test.template.list"
+)
+
+// TestMain runs the test binary as a template plugin when it is started by
+// a test.
+func TestMain(m *testing.M) {
+	if schema := os.Getenv(testPluginEnv); schema != "" {
+		Main(schema, renderList)
+	}
+	os.Setenv(testPluginEnv, testPluginSchema)
+	os.Exit(m.Run())
+}
+
+// listTemplate is rendered by the test plugin into a package with one
+// channel of the listed bundles, in order.
+type listTemplate struct {
+	Schema  string   `json:"schema"`
+	Package string   `json:"package"`
+	Bundles []string `json:"bundles"`
+	Fail    string   `json:"fail,omitempty"`
+}
+
+func renderList(ctx context.Context, template []byte, renderBundle api.BundleRenderer) (*declcfg.DeclarativeConfig, error) {
+	var lt listTemplate
+	if err := yaml.UnmarshalStrict(template, &lt); err != nil {
+		return nil, err
+	}
+	if lt.Fail != "" {
+		return nil, errors.New(lt.Fail)
+	}
+
+	// Render the bundles concurrently, to exercise matching of responses.
+	rendered := make([]*declcfg.DeclarativeConfig, len(lt.Bundles))
+	errs := make([]error, len(lt.Bundles))
+	done := make(chan struct{})
+	for i, image := range lt.Bundles {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			rendered[i], errs[i] = renderBundle(ctx, image)
+		}()
+	}
+	for range lt.Bundles {
+		<-done
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	cfg := &declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{{Schema: declcfg.SchemaPackage, Name: lt.Package, DefaultChannel: "stable"}},
+		Channels: []declcfg.Channel{{Schema: declcfg.SchemaChannel, Package: lt.Package, Name: "stable"}},
+	}
+	for _, r := range rendered {
+		b := r.Bundles[0]
+		entry := declcfg.ChannelEntry{Name: b.Name}
+		if n := len(cfg.Bundles); n > 0 {
+			entry.Replaces = cfg.Bundles[n-1].Name
+		}
+		cfg.Channels[0].Entries = append(cfg.Channels[0].Entries, entry)
+		cfg.Bundles = append(cfg.Bundles, b)
+	}
+	return cfg, nil
+}
+
+// renderTestBundle renders images named "<package>:v<version>".
+func renderTestBundle(_ context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	pkg, version, ok := strings.Cut(image, ":v")
+	if !ok {
+		return nil, fmt.Errorf("image %q not found", image)
+	}
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{{
+		Schema:     declcfg.SchemaBundle,
+		Name:       pkg + ".v" + version,
+		Package:    pkg,
+		Image:      image,
+		Properties: []property.Property{property.MustBuildPackage(pkg, version)},
+	}}}, nil
+}
+
+func testExecutable(t *testing.T) string {
+	t.Helper()
+	path, err := os.Executable()
+	require.NoError(t, err)
+	return path
+}
+
+func TestRender(t *testing.T) {
+	type spec struct {
+		name            string
+		template        string
+		expectedBundles []string
+		expectedError   string
+	}
+	specs := []spec{
+		{
+			name: "Success",
+			template: `schema: test.template.list
+package: foo
+bundles: ["foo:v0.1.0", "foo:v0.2.0", "foo:v0.3.0"]
+`,
+			expectedBundles: []string{"foo.v0.1.0", "foo.v0.2.0", "foo.v0.3.0"},
+		},
+		{
+			name:          "Fail/RenderError",
+			template:      "schema: test.template.list\npackage: foo\nfail: no release calendar\n",
+			expectedError: "no release calendar",
+		},
+		{
+			name:          "Fail/BundleRenderError",
+			template:      "schema: test.template.list\npackage: foo\nbundles: [foo]\n",
+			expectedError: `image "foo" not found`,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			f, err := NewFactory(context.Background(), testExecutable(t))
+			require.NoError(t, err)
+			require.Equal(t, testPluginSchema, f.Schema())
+			require.Equal(t, "list", f.Type())
+
+			tmpl := f.CreateTemplate(renderTestBundle)
+			cfg, err := tmpl.Render(context.Background(), strings.NewReader(s.template))
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var bundles []string
+			for _, b := range cfg.Bundles {
+				bundles = append(bundles, b.Name)
+			}
+			require.Equal(t, s.expectedBundles, bundles)
+			_, err = declcfg.ConvertToModel(*cfg)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewFactory(t *testing.T) {
+	type spec struct {
+		name          string
+		description   string
+		expectedError string
+	}
+	specs := []spec{
+		{
+			name:        "Success",
+			description: `{"protocolVersion":"v1","schema":"example.calendar"}`,
+		},
+		{
+			name:          "Fail/NoSchema",
+			description:   `{"protocolVersion":"v1"}`,
+			expectedError: "has no schema",
+		},
+		{
+			name:          "Fail/ProtocolVersion",
+			description:   `{"protocolVersion":"v2","schema":"example.calendar"}`,
+			expectedError: `unsupported protocol version "v2"`,
+		},
+		{
+			name:          "Fail/NotAPlugin",
+			description:   "hello",
+			expectedError: "parse description",
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plugin")
+			require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho '"+s.description+"'\n"), 0700))
+			f, err := NewFactory(context.Background(), path)
+			if s.expectedError != "" {
+				require.ErrorContains(t, err, s.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "example.calendar", f.Schema())
+			require.Equal(t, "calendar", f.Type())
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	// A plugin that asks for a bundle, against a host that answers with an
+	// error, reports the error in its result.
+	hostIn, pluginOut := io.Pipe()
+	pluginIn, hostOut := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Serve(context.Background(), []string{renderCommand}, pluginIn, pluginOut, testPluginSchema, renderList)
+		pluginOut.Close()
+	}()
+
+	host := &pluginTemplate{path: "test", renderBundle: func(context.Context, string) (*declcfg.DeclarativeConfig, error) {
+		return nil, errors.New("registry unavailable")
+	}}
+	var mu sync.Mutex
+	send := func(msg Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return json.NewEncoder(hostOut).Encode(msg)
+	}
+	result, err := host.exchange(context.Background(), hostIn, send, "schema: test.template.list\npackage: foo\nbundles: [foo:v0.1.0]\n")
+	require.NoError(t, err)
+	require.Equal(t, ResultMessageType, result.Type)
+	require.Contains(t, result.Error, "registry unavailable")
+	require.NoError(t, <-errCh)
+}
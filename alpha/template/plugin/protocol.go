@@ -0,0 +1,101 @@
+// Package plugin runs catalog templates that are implemented by external
+// executables.
+//
+// A template plugin is an executable that opm runs with a single command
+// argument:
+//
+//	<plugin> describe
+//
+// prints a Description as JSON, which tells opm the template schema that the
+// plugin renders.
+//
+//	<plugin> render
+//
+// renders one template. opm and the plugin exchange JSON Messages, one per
+// line: opm writes to the plugin's standard input and the plugin writes to
+// its standard output. The plugin's standard error is passed through.
+//
+//  1. opm sends a "render" message with the template.
+//  2. The plugin may send any number of "renderBundle" messages, each with a
+//     unique ID and a bundle image reference. opm renders each bundle,
+//     concurrently, and answers with a "bundle" message with the same ID that
+//     holds the rendered catalog or an error.
+//  3. The plugin sends a "result" message with the rendered catalog or an
+//     error, and exits.
+//
+// Catalogs are sent as arrays of file-based catalog objects. Plugins written
+// in Go can use Main to implement the protocol.
+package plugin
+
+import (
+	"bytes"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+// ProtocolVersion is the version of the protocol described in the package
+// documentation.
+const ProtocolVersion = "v1"
+
+const (
+	describeCommand = "describe"
+	renderCommand   = "render"
+)
+
+// Description is the output of the describe command of a plugin.
+type Description struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	// Schema is the schema of the templates that the plugin renders.
+	Schema string `json:"schema"`
+}
+
+type MessageType string
+
+const (
+	// RenderMessageType is sent by opm to start rendering Template.
+	RenderMessageType MessageType = "render"
+	// RenderBundleMessageType is sent by the plugin to render Image.
+	RenderBundleMessageType MessageType = "renderBundle"
+	// BundleMessageType is sent by opm with the Catalog rendered for the
+	// renderBundle message with the same ID, or an Error.
+	BundleMessageType MessageType = "bundle"
+	// ResultMessageType is sent by the plugin with the rendered Catalog, or
+	// an Error.
+	ResultMessageType MessageType = "result"
+)
+
+// Message is exchanged by opm and plugins while rendering a template.
+type Message struct {
+	Type     MessageType    `json:"type"`
+	ID       int64          `json:"id,omitempty"`
+	Template string         `json:"template,omitempty"`
+	Image    string         `json:"image,omitempty"`
+	Catalog  []declcfg.Meta `json:"catalog,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+func toMetas(cfg declcfg.DeclarativeConfig) ([]declcfg.Meta, error) {
+	var buf bytes.Buffer
+	if err := declcfg.WriteJSON(cfg, &buf); err != nil {
+		return nil, err
+	}
+	var metas []declcfg.Meta
+	if err := declcfg.WalkMetasReader(&buf, func(meta *declcfg.Meta, err error) error {
+		if err != nil {
+			return err
+		}
+		metas = append(metas, *meta)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return metas, nil
+}
+
+func fromMetas(metas []declcfg.Meta) (*declcfg.DeclarativeConfig, error) {
+	ptrs := make([]*declcfg.Meta, 0, len(metas))
+	for i := range metas {
+		ptrs = append(ptrs, &metas[i])
+	}
+	return declcfg.LoadSlice(ptrs)
+}
@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/template/api"
+)
+
+// RenderFunc renders a template. renderBundle asks opm to render a bundle
+// image reference, and may be called concurrently.
+type RenderFunc func(ctx context.Context, template []byte, renderBundle api.BundleRenderer) (*declcfg.DeclarativeConfig, error)
+
+// Main implements a template plugin for schema with render. It runs the
+// command in os.Args and exits.
+func Main(schema string, render RenderFunc) {
+	if err := Serve(context.Background(), os.Args[1:], os.Stdin, os.Stdout, schema, render); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Serve runs the plugin command in args, reading messages from in and
+// writing them to out. Errors returned by render are sent to opm, not
+// returned.
+func Serve(ctx context.Context, args []string, in io.Reader, out io.Writer, schema string, render RenderFunc) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one argument, %q or %q", describeCommand, renderCommand)
+	}
+	enc := json.NewEncoder(out)
+	switch args[0] {
+	case describeCommand:
+		return enc.Encode(Description{ProtocolVersion: ProtocolVersion, Schema: schema})
+	case renderCommand:
+		return serveRender(ctx, json.NewDecoder(in), enc, render)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func serveRender(ctx context.Context, dec *json.Decoder, enc *json.Encoder, render RenderFunc) error {
+	var req Message
+	if err := dec.Decode(&req); err != nil {
+		return fmt.Errorf("read template: %v", err)
+	}
+	if req.Type != RenderMessageType {
+		return fmt.Errorf("unexpected message type %q, expected %q", req.Type, RenderMessageType)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := &bundleClient{enc: enc, pending: map[int64]chan Message{}, done: make(chan struct{})}
+	go c.receive(dec)
+
+	result := Message{Type: ResultMessageType}
+	cfg, err := render(ctx, []byte(req.Template), c.renderBundle)
+	if err == nil {
+		result.Catalog, err = toMetas(*cfg)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return c.send(result)
+}
+
+// bundleClient sends renderBundle messages, and matches the bundle messages
+// that answer them by ID.
+type bundleClient struct {
+	encMu sync.Mutex
+	enc   *json.Encoder
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan Message
+	err     error
+	done    chan struct{}
+}
+
+func (c *bundleClient) send(msg Message) error {
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+	return c.enc.Encode(msg)
+}
+
+func (c *bundleClient) renderBundle(ctx context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	ch := make(chan Message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(Message{Type: RenderBundleMessageType, ID: id, Image: image}); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.err
+	case resp := <-ch:
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+		return fromMetas(resp.Catalog)
+	}
+}
+
+func (c *bundleClient) receive(dec *json.Decoder) {
+	err := func() error {
+		for {
+			var msg Message
+			if err := dec.Decode(&msg); err != nil {
+				if errors.Is(err, io.EOF) {
+					return errors.New("opm closed the connection")
+				}
+				return fmt.Errorf("read message: %v", err)
+			}
+			if msg.Type != BundleMessageType {
+				return fmt.Errorf("unexpected message type %q", msg.Type)
+			}
+			c.mu.Lock()
+			ch, ok := c.pending[msg.ID]
+			c.mu.Unlock()
+			if !ok {
+				return fmt.Errorf("unexpected bundle message ID %d", msg.ID)
+			}
+			ch <- msg
+		}
+	}()
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	close(c.done)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/operator-framework/operator-registry/alpha/template/api"
 	"github.com/operator-framework/operator-registry/alpha/template/basic"
+	"github.com/operator-framework/operator-registry/alpha/template/composite"
 	"github.com/operator-framework/operator-registry/alpha/template/semver"
 	"github.com/operator-framework/operator-registry/alpha/template/substitutes"
 )
@@ -46,6 +47,7 @@ func NewRegistry() Registry {
 	r.Register(&basic.Factory{})
 	r.Register(&semver.Factory{})
 	r.Register(&substitutes.Factory{})
+	r.Register(&composite.Factory{Creator: r})
 	return r
 }
 
@@ -0,0 +1,327 @@
+package semver
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+// EdgeType is the kind of channel graph element that an EdgeChange refers to.
+type EdgeType string
+
+const (
+	// EntryEdgeType is the presence of a bundle in a channel.
+	EntryEdgeType     EdgeType = "entry"
+	ReplacesEdgeType  EdgeType = "replaces"
+	SkipsEdgeType     EdgeType = "skips"
+	SkipRangeEdgeType EdgeType = "skipRange"
+)
+
+// EdgeChange is an element of a channel graph that is in the catalog rendered
+// from a generated template, but not in the catalog it was generated from
+// (Added), or the reverse.
+type EdgeChange struct {
+	Channel string
+	Bundle  string
+	Type    EdgeType
+	// Target is the replaced or skipped bundle, or the skipped range. It is
+	// empty for entries.
+	Target string
+	Added  bool
+}
+
+func (c EdgeChange) String() string {
+	sign := "-"
+	if c.Added {
+		sign = "+"
+	}
+	if c.Type == EntryEdgeType {
+		return fmt.Sprintf("%s %s: %s", sign, c.Channel, c.Bundle)
+	}
+	return fmt.Sprintf("%s %s: %s %s %s", sign, c.Channel, c.Bundle, c.Type, c.Target)
+}
+
+// ConversionReport describes how the catalog rendered from a semver template
+// generated by FromDeclarativeConfig differs from the catalog it was
+// generated from.
+type ConversionReport struct {
+	GenerateMajorChannels bool
+	GenerateMinorChannels bool
+	// UnmappedChannels are the channels whose names do not identify them as
+	// candidate, fast or stable channels. Their bundles are only in the
+	// template if they are also in a mapped channel.
+	UnmappedChannels []string
+	// DefaultChannel is the default channel of the input package, and
+	// GeneratedDefaultChannel the default channel of the rendered template.
+	DefaultChannel          string
+	GeneratedDefaultChannel string
+	// Changes are all the channel graph elements that differ, ordered by
+	// channel and bundle.
+	Changes []EdgeChange
+}
+
+// Reproduces returns true if the template renders the same channels and
+// default channel as the input catalog.
+func (r ConversionReport) Reproduces() bool {
+	return len(r.Changes) == 0 && r.DefaultChannel == r.GeneratedDefaultChannel
+}
+
+func (r ConversionReport) String() string {
+	var generated []string
+	if r.GenerateMajorChannels {
+		generated = append(generated, "major")
+	}
+	if r.GenerateMinorChannels {
+		generated = append(generated, "minor")
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "generating %s version channels ", strings.Join(generated, " and "))
+	if r.Reproduces() {
+		sb.WriteString("reproduces the input channels\n")
+		return sb.String()
+	}
+	sb.WriteString("does not reproduce the input channels:\n")
+	for _, ch := range r.UnmappedChannels {
+		fmt.Fprintf(&sb, "  channel %q is not a candidate, fast or stable channel\n", ch)
+	}
+	if r.DefaultChannel != r.GeneratedDefaultChannel {
+		fmt.Fprintf(&sb, "  default channel changes from %q to %q\n", r.DefaultChannel, r.GeneratedDefaultChannel)
+	}
+	for _, c := range r.Changes {
+		fmt.Fprintf(&sb, "  %s\n", c)
+	}
+	return sb.String()
+}
+
+// channelNameRegexp matches the channel names that the semver template
+// generates, and unversioned archetype names.
+var channelNameRegexp = regexp.MustCompile(`^(candidate|fast|stable)(?:-v(\d+)(\.\d+)?)?$`)
+
+// FromReader reads FBC for a single package from a reader and generates a
+// SemverTemplateData from it. See FromDeclarativeConfig.
+func FromReader(r io.Reader) (*SemverTemplateData, *ConversionReport, error) {
+	cfg, err := declcfg.LoadReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return FromDeclarativeConfig(*cfg)
+}
+
+// FromDeclarativeConfig generates a SemverTemplateData for the single package
+// in cfg.
+//
+// Bundles are listed as Candidate, Fast or Stable bundles according to the
+// names of the channels they are in. Major and minor version channel
+// generation are each tried, alone and together, and the option that renders
+// the fewest differences from cfg is used. The returned report lists those
+// differences.
+func FromDeclarativeConfig(cfg declcfg.DeclarativeConfig) (*SemverTemplateData, *ConversionReport, error) {
+	if len(cfg.Packages) != 1 {
+		return nil, nil, fmt.Errorf("expected exactly one package, found %d", len(cfg.Packages))
+	}
+	pkg := cfg.Packages[0]
+
+	versions := map[string]semver.Version{}
+	releases := map[string]model.Release{}
+	images := map[string]string{}
+	for _, b := range cfg.Bundles {
+		if b.Package != pkg.Name {
+			continue
+		}
+		props, err := property.Parse(b.Properties)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse properties for bundle %q: %v", b.Name, err)
+		}
+		if len(props.Packages) != 1 {
+			return nil, nil, fmt.Errorf("bundle %q has %d %q properties, expected exactly 1", b.Name, len(props.Packages), property.TypePackage)
+		}
+		v, err := semver.Parse(props.Packages[0].Version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("bundle %q has invalid version %q: %v", b.Name, props.Packages[0].Version, err)
+		}
+		if props.Packages[0].Release != "" {
+			r, err := model.NewRelease(props.Packages[0].Release)
+			if err != nil {
+				return nil, nil, fmt.Errorf("bundle %q has invalid release %q: %v", b.Name, props.Packages[0].Release, err)
+			}
+			releases[b.Name] = r
+		}
+		if b.Image == "" {
+			return nil, nil, fmt.Errorf("bundle %q has no image", b.Name)
+		}
+		versions[b.Name] = v
+		images[b.Name] = b.Image
+	}
+
+	// Assign bundles to archetypes by the names of their channels
+	archetypeBundles := map[channelArchetype]sets.Set[string]{}
+	var unmapped []string
+	var inputChannels []declcfg.Channel
+	defaultKind := MinorStreamType
+	for _, ch := range cfg.Channels {
+		if ch.Package != pkg.Name {
+			continue
+		}
+		inputChannels = append(inputChannels, ch)
+		m := channelNameRegexp.FindStringSubmatch(ch.Name)
+		if m == nil {
+			unmapped = append(unmapped, ch.Name)
+			continue
+		}
+		if ch.Name == pkg.DefaultChannel && m[2] != "" && m[3] == "" {
+			defaultKind = MajorStreamType
+		}
+		arch := channelArchetype(m[1])
+		if archetypeBundles[arch] == nil {
+			archetypeBundles[arch] = sets.New[string]()
+		}
+		for _, e := range ch.Entries {
+			if _, ok := versions[e.Name]; !ok {
+				return nil, nil, fmt.Errorf("channel %q entry %q has no bundle", ch.Name, e.Name)
+			}
+			archetypeBundles[arch].Insert(e.Name)
+		}
+	}
+	slices.Sort(unmapped)
+
+	bv := bundleVersions{}
+	for arch, names := range archetypeBundles {
+		bv[arch] = map[string]semver.Version{}
+		for name := range names {
+			bv[arch][name] = versions[name]
+		}
+	}
+	if err := withoutBuildMetadataConflict(&versions, releases); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		best       *SemverTemplateData
+		bestReport *ConversionReport
+	)
+	for _, mode := range []struct{ major, minor bool }{{false, true}, {true, false}, {true, true}} {
+		sv := &SemverTemplateData{
+			Schema:                schema,
+			GenerateMajorChannels: mode.major,
+			GenerateMinorChannels: mode.minor,
+			pkg:                   pkg.Name,
+			releases:              releases,
+		}
+		switch {
+		case !mode.major:
+			sv.DefaultChannelTypePreference = MinorStreamType
+		case !mode.minor:
+			sv.DefaultChannelTypePreference = MajorStreamType
+		default:
+			sv.DefaultChannelTypePreference = defaultKind
+		}
+		generated := sv.generateChannels(&bv)
+
+		report := &ConversionReport{
+			GenerateMajorChannels:   mode.major,
+			GenerateMinorChannels:   mode.minor,
+			UnmappedChannels:        unmapped,
+			DefaultChannel:          pkg.DefaultChannel,
+			GeneratedDefaultChannel: sv.defaultChannel,
+			Changes:                 diffChannelEdges(inputChannels, generated),
+		}
+		if bestReport == nil || reportCost(report) < reportCost(bestReport) {
+			best, bestReport = sv, report
+		}
+	}
+
+	for _, arch := range []struct {
+		archetype channelArchetype
+		bundles   *channelBundles
+	}{
+		{candidateChannelArchetype, &best.Candidate},
+		{fastChannelArchetype, &best.Fast},
+		{stableChannelArchetype, &best.Stable},
+	} {
+		names := sets.List(archetypeBundles[arch.archetype])
+		slices.SortFunc(names, func(a, b string) int {
+			return compareVersions(versions[a], releases[a], versions[b], releases[b])
+		})
+		for _, name := range names {
+			arch.bundles.Bundles = append(arch.bundles.Bundles, bundleEntry{Image: images[name]})
+		}
+	}
+	return best, bestReport, nil
+}
+
+func reportCost(r *ConversionReport) int {
+	cost := len(r.Changes)
+	if r.DefaultChannel != r.GeneratedDefaultChannel {
+		cost++
+	}
+	return cost
+}
+
+// diffChannelEdges returns the channel graph elements that are only in one of
+// from and to, sorted by channel, bundle, type and target.
+func diffChannelEdges(from, to []declcfg.Channel) []EdgeChange {
+	fromEdges := channelEdges(from)
+	toEdges := channelEdges(to)
+	var changes []EdgeChange
+	for e := range fromEdges.Difference(toEdges) {
+		changes = append(changes, e)
+	}
+	for e := range toEdges.Difference(fromEdges) {
+		e.Added = true
+		changes = append(changes, e)
+	}
+	slices.SortFunc(changes, func(a, b EdgeChange) int {
+		return cmp.Or(
+			cmp.Compare(a.Channel, b.Channel),
+			cmp.Compare(a.Bundle, b.Bundle),
+			cmp.Compare(edgeTypeOrder(a.Type), edgeTypeOrder(b.Type)),
+			cmp.Compare(a.Target, b.Target),
+			compareBool(a.Added, b.Added),
+		)
+	})
+	return changes
+}
+
+func channelEdges(channels []declcfg.Channel) sets.Set[EdgeChange] {
+	edges := sets.New[EdgeChange]()
+	for _, ch := range channels {
+		for _, e := range ch.Entries {
+			edges.Insert(EdgeChange{Channel: ch.Name, Bundle: e.Name, Type: EntryEdgeType})
+			if e.Replaces != "" {
+				edges.Insert(EdgeChange{Channel: ch.Name, Bundle: e.Name, Type: ReplacesEdgeType, Target: e.Replaces})
+			}
+			for _, s := range e.Skips {
+				edges.Insert(EdgeChange{Channel: ch.Name, Bundle: e.Name, Type: SkipsEdgeType, Target: s})
+			}
+			if e.SkipRange != "" {
+				edges.Insert(EdgeChange{Channel: ch.Name, Bundle: e.Name, Type: SkipRangeEdgeType, Target: e.SkipRange})
+			}
+		}
+	}
+	return edges
+}
+
+func edgeTypeOrder(t EdgeType) int {
+	return slices.Index([]EdgeType{EntryEdgeType, ReplacesEdgeType, SkipsEdgeType, SkipRangeEdgeType}, t)
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
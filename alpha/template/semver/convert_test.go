@@ -0,0 +1,180 @@
+package semver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+// convertTestRenderer renders images named "quay.io/foo/olm:testoperator.v<version>".
+func convertTestRenderer(_ context.Context, image string) (*declcfg.DeclarativeConfig, error) {
+	version := strings.TrimPrefix(image, "quay.io/foo/olm:testoperator.v")
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{{
+		Schema:     declcfg.SchemaBundle,
+		Name:       "testoperator.v" + version,
+		Package:    "testoperator",
+		Image:      image,
+		Properties: []property.Property{property.MustBuildPackage("testoperator", version)},
+	}}}, nil
+}
+
+func renderConvertTestTemplate(t *testing.T, generate string) declcfg.DeclarativeConfig {
+	t.Helper()
+	tmpl := fmt.Sprintf(`---
+schema: olm.semver
+%s
+candidate:
+    bundles:
+        - image: quay.io/foo/olm:testoperator.v0.1.0
+        - image: quay.io/foo/olm:testoperator.v0.1.1
+        - image: quay.io/foo/olm:testoperator.v0.2.0
+        - image: quay.io/foo/olm:testoperator.v1.0.0
+fast:
+    bundles:
+        - image: quay.io/foo/olm:testoperator.v0.1.1
+        - image: quay.io/foo/olm:testoperator.v0.2.0
+        - image: quay.io/foo/olm:testoperator.v1.0.0
+stable:
+    bundles:
+        - image: quay.io/foo/olm:testoperator.v0.2.0
+        - image: quay.io/foo/olm:testoperator.v1.0.0
+`, generate)
+	cfg, err := new(convertTestRenderer).Render(context.Background(), strings.NewReader(tmpl))
+	require.NoError(t, err)
+	return *cfg
+}
+
+func templateImages(bundles channelBundles) []string {
+	var images []string
+	for _, b := range bundles.Bundles {
+		images = append(images, strings.TrimPrefix(b.Image, "quay.io/foo/olm:testoperator."))
+	}
+	return images
+}
+
+func TestFromDeclarativeConfig(t *testing.T) {
+	type spec struct {
+		name            string
+		cfg             func(t *testing.T) declcfg.DeclarativeConfig
+		expectMajor     bool
+		expectMinor     bool
+		expectReproduce bool
+		expectReport    *ConversionReport
+		expectError     string
+	}
+	specs := []spec{
+		{
+			name: "Success/ReproducesMinorChannels",
+			cfg: func(t *testing.T) declcfg.DeclarativeConfig {
+				return renderConvertTestTemplate(t, "generateMinorChannels: true")
+			},
+			expectMinor:     true,
+			expectReproduce: true,
+		},
+		{
+			name: "Success/ReproducesMajorChannels",
+			cfg: func(t *testing.T) declcfg.DeclarativeConfig {
+				return renderConvertTestTemplate(t, "generateMajorChannels: true")
+			},
+			expectMajor:     true,
+			expectReproduce: true,
+		},
+		{
+			name: "Success/ReproducesMajorAndMinorChannels",
+			cfg: func(t *testing.T) declcfg.DeclarativeConfig {
+				return renderConvertTestTemplate(t, "generateMajorChannels: true\ngenerateMinorChannels: true\ndefaultChannelTypePreference: major")
+			},
+			expectMajor:     true,
+			expectMinor:     true,
+			expectReproduce: true,
+		},
+		{
+			name: "Success/ReportsChangedEdges",
+			cfg: func(t *testing.T) declcfg.DeclarativeConfig {
+				cfg := renderConvertTestTemplate(t, "generateMajorChannels: true")
+				for i := range cfg.Channels {
+					switch cfg.Channels[i].Name {
+					case "stable-v1":
+						cfg.Channels[i].Entries[0].SkipRange = "<1.0.0"
+					case "candidate-v0":
+						cfg.Channels[i].Name = "preview"
+					}
+				}
+				return cfg
+			},
+			expectMajor: true,
+			expectReport: &ConversionReport{
+				GenerateMajorChannels:   true,
+				UnmappedChannels:        []string{"preview"},
+				DefaultChannel:          "stable-v1",
+				GeneratedDefaultChannel: "stable-v1",
+				Changes: []EdgeChange{
+					{Channel: "preview", Bundle: "testoperator.v0.1.0", Type: EntryEdgeType},
+					{Channel: "preview", Bundle: "testoperator.v0.1.1", Type: EntryEdgeType},
+					{Channel: "preview", Bundle: "testoperator.v0.1.1", Type: SkipsEdgeType, Target: "testoperator.v0.1.0"},
+					{Channel: "preview", Bundle: "testoperator.v0.2.0", Type: EntryEdgeType},
+					{Channel: "preview", Bundle: "testoperator.v0.2.0", Type: ReplacesEdgeType, Target: "testoperator.v0.1.1"},
+					{Channel: "preview", Bundle: "testoperator.v0.2.0", Type: SkipsEdgeType, Target: "testoperator.v0.1.0"},
+					{Channel: "stable-v1", Bundle: "testoperator.v1.0.0", Type: SkipRangeEdgeType, Target: "<1.0.0"},
+				},
+			},
+		},
+		{
+			name: "Fail/MultiplePackages",
+			cfg: func(t *testing.T) declcfg.DeclarativeConfig {
+				return declcfg.DeclarativeConfig{Packages: []declcfg.Package{{Name: "foo"}, {Name: "bar"}}}
+			},
+			expectError: "expected exactly one package, found 2",
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			cfg := s.cfg(t)
+			sv, report, err := FromDeclarativeConfig(cfg)
+			if s.expectError != "" {
+				require.EqualError(t, err, s.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expectMajor, sv.GenerateMajorChannels)
+			require.Equal(t, s.expectMinor, sv.GenerateMinorChannels)
+			require.Equal(t, s.expectReproduce, report.Reproduces(), report.String())
+			if s.expectReport != nil {
+				require.Equal(t, s.expectReport, report)
+			}
+			if !s.expectReproduce {
+				return
+			}
+			require.Equal(t, []string{"v0.1.0", "v0.1.1", "v0.2.0", "v1.0.0"}, templateImages(sv.Candidate))
+			require.Equal(t, []string{"v0.1.1", "v0.2.0", "v1.0.0"}, templateImages(sv.Fast))
+			require.Equal(t, []string{"v0.2.0", "v1.0.0"}, templateImages(sv.Stable))
+		})
+	}
+}
+
+func TestConversionReportString(t *testing.T) {
+	report := ConversionReport{
+		GenerateMinorChannels:   true,
+		UnmappedChannels:        []string{"preview"},
+		DefaultChannel:          "stable",
+		GeneratedDefaultChannel: "stable-v1.0",
+		Changes: []EdgeChange{
+			{Channel: "stable", Bundle: "foo.v1.0.0", Type: EntryEdgeType},
+			{Channel: "stable-v1.0", Bundle: "foo.v1.0.1", Type: ReplacesEdgeType, Target: "foo.v1.0.0", Added: true},
+		},
+	}
+	require.Equal(t, `generating minor version channels does not reproduce the input channels:
+  channel "preview" is not a candidate, fast or stable channel
+  default channel changes from "stable" to "stable-v1.0"
+  - stable: foo.v1.0.0
+  + stable-v1.0: foo.v1.0.1 replaces foo.v1.0.0
+`, report.String())
+	require.Equal(t, "generating major and minor version channels reproduces the input channels\n",
+		ConversionReport{GenerateMajorChannels: true, GenerateMinorChannels: true}.String())
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
 	"slices"
 	"sort"
 
@@ -15,6 +16,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
 	"github.com/operator-framework/operator-registry/alpha/property"
 	"github.com/operator-framework/operator-registry/alpha/template/api"
 	"github.com/operator-framework/operator-registry/pkg/registry"
@@ -37,6 +39,26 @@ const (
 	MajorStreamType StreamType = "major"
 )
 
+// EdgeStrategy selects how the bundles of a version stream are linked
+type EdgeStrategy string
+
+const (
+	// DefaultEdgeStrategy represents an unspecified edge strategy, which is SkipsEdgeStrategy
+	DefaultEdgeStrategy EdgeStrategy = ""
+	// SkipsEdgeStrategy links only the head of each minor version stream, which replaces the head of the previous
+	// minor version stream and skips all other earlier bundles of the major version
+	SkipsEdgeStrategy EdgeStrategy = "skips"
+	// ReplacesEdgeStrategy links every bundle of a minor version stream to its predecessor with a replaces edge
+	ReplacesEdgeStrategy EdgeStrategy = "replaces"
+)
+
+// streamOptions overrides template options for the minor version streams of a major ("1") or minor ("1.2") version.
+// Options for a minor version take precedence over those for its major version.
+type streamOptions struct {
+	Version      string       `json:"version"`
+	EdgeStrategy EdgeStrategy `json:"edgeStrategy,omitempty"`
+}
+
 type bundleEntry struct {
 	Image string `json:"image,omitempty"`
 }
@@ -53,9 +75,16 @@ type SemverTemplateData struct {
 	Candidate                    channelBundles `json:"candidate,omitempty"`
 	Fast                         channelBundles `json:"fast,omitempty"`
 	Stable                       channelBundles `json:"stable,omitempty"`
+	// EdgeStrategy is the edge strategy of all version streams without stream options
+	EdgeStrategy EdgeStrategy `json:"edgeStrategy,omitempty"`
+	// GenerateSkipRange adds a skipRange to the head of each minor version stream, so that all earlier patch versions
+	// of the stream, including those no longer in the catalog, can upgrade directly to the head
+	GenerateSkipRange bool            `json:"generateSkipRange,omitempty"`
+	Streams           []streamOptions `json:"streams,omitempty"`
 
-	pkg            string `json:"-"` // the derived package name
-	defaultChannel string `json:"-"` // detected "most stable" channel head
+	pkg            string                   `json:"-"` // the derived package name
+	defaultChannel string                   `json:"-"` // detected "most stable" channel head
+	releases       map[string]model.Release `json:"-"` // release versions of the bundles that have one
 }
 
 // semverTemplate implements the common template interface
@@ -201,9 +230,15 @@ type entryTuple struct {
 	parent  string
 	name    string
 	version semver.Version
+	release model.Release
 	index   int
 }
 
+// compareVersions orders bundles by version, and bundles of the same version by release
+func compareVersions(va semver.Version, ra model.Release, vb semver.Version, rb model.Release) int {
+	return cmp.Or(va.Compare(vb), ra.Compare(rb))
+}
+
 func buildBundleList(t SemverTemplateData) map[string]string {
 	dict := make(map[string]string)
 	for _, bl := range []channelBundles{t.Candidate, t.Fast, t.Stable} {
@@ -258,9 +293,58 @@ func readFile(reader io.Reader) (*SemverTemplateData, error) {
 		return nil, fmt.Errorf("unknown DefaultChannelTypePreference: %q\nValid values are 'major' or 'minor'", sv.DefaultChannelTypePreference)
 	}
 
+	if err := validateEdgeStrategy(sv.EdgeStrategy); err != nil {
+		return nil, err
+	}
+	streams := sets.New[string]()
+	for _, so := range sv.Streams {
+		if !streamVersionRegexp.MatchString(so.Version) {
+			return nil, fmt.Errorf("invalid stream version %q: must be a major (\"1\") or minor (\"1.2\") version", so.Version)
+		}
+		if streams.Has(so.Version) {
+			return nil, fmt.Errorf("duplicate options for stream version %q", so.Version)
+		}
+		streams.Insert(so.Version)
+		if err := validateEdgeStrategy(so.EdgeStrategy); err != nil {
+			return nil, fmt.Errorf("stream version %q: %v", so.Version, err)
+		}
+	}
+
 	return &sv, nil
 }
 
+var streamVersionRegexp = regexp.MustCompile(`^(0|[1-9]\d*)(\.(0|[1-9]\d*))?$`)
+
+func validateEdgeStrategy(strategy EdgeStrategy) error {
+	switch strategy {
+	case DefaultEdgeStrategy, SkipsEdgeStrategy, ReplacesEdgeStrategy:
+		return nil
+	default:
+		return fmt.Errorf("unknown EdgeStrategy: %q\nValid values are 'skips' or 'replaces'", strategy)
+	}
+}
+
+// edgeStrategy returns the edge strategy of the minor version stream of v
+func (sv *SemverTemplateData) edgeStrategy(v semver.Version) EdgeStrategy {
+	strategy := sv.EdgeStrategy
+	for _, so := range sv.Streams {
+		switch so.Version {
+		case fmt.Sprintf("%d.%d", v.Major, v.Minor):
+			if so.EdgeStrategy != DefaultEdgeStrategy {
+				return so.EdgeStrategy
+			}
+		case fmt.Sprintf("%d", v.Major):
+			if so.EdgeStrategy != DefaultEdgeStrategy {
+				strategy = so.EdgeStrategy
+			}
+		}
+	}
+	if strategy == DefaultEdgeStrategy {
+		return SkipsEdgeStrategy
+	}
+	return strategy
+}
+
 func (sv *SemverTemplateData) getVersionsFromStandardChannels(cfg *declcfg.DeclarativeConfig, bundleDict map[string]string) (*bundleVersions, error) {
 	versions := bundleVersions{}
 
@@ -268,7 +352,7 @@ func (sv *SemverTemplateData) getVersionsFromStandardChannels(cfg *declcfg.Decla
 	if err != nil {
 		return nil, err
 	}
-	if err = validateVersions(&bdm); err != nil {
+	if err = validateVersions(&bdm, sv.releases); err != nil {
 		return nil, err
 	}
 	versions[candidateChannelArchetype] = bdm
@@ -277,7 +361,7 @@ func (sv *SemverTemplateData) getVersionsFromStandardChannels(cfg *declcfg.Decla
 	if err != nil {
 		return nil, err
 	}
-	if err = validateVersions(&bdm); err != nil {
+	if err = validateVersions(&bdm, sv.releases); err != nil {
 		return nil, err
 	}
 	versions[fastChannelArchetype] = bdm
@@ -286,7 +370,7 @@ func (sv *SemverTemplateData) getVersionsFromStandardChannels(cfg *declcfg.Decla
 	if err != nil {
 		return nil, err
 	}
-	if err = validateVersions(&bdm); err != nil {
+	if err = validateVersions(&bdm, sv.releases); err != nil {
 		return nil, err
 	}
 	versions[stableChannelArchetype] = bdm
@@ -326,6 +410,16 @@ func (sv *SemverTemplateData) getVersionsFromChannel(semverBundles []bundleEntry
 		if err != nil {
 			return nil, fmt.Errorf("bundle %q has invalid version %q: %v", b.Name, props.Packages[0].Version, err)
 		}
+		if props.Packages[0].Release != "" {
+			r, err := model.NewRelease(props.Packages[0].Release)
+			if err != nil {
+				return nil, fmt.Errorf("bundle %q has invalid release %q: %v", b.Name, props.Packages[0].Release, err)
+			}
+			if sv.releases == nil {
+				sv.releases = map[string]model.Release{}
+			}
+			sv.releases[b.Name] = r
+		}
 
 		// package name detection
 		if sv.pkg != "" {
@@ -385,8 +479,8 @@ func (sv *SemverTemplateData) generateChannels(semverChannels *bundleVersions) [
 		for b := range bundles {
 			bundleNamesByVersion = append(bundleNamesByVersion, b)
 		}
-		sort.Slice(bundleNamesByVersion, func(i, j int) bool {
-			return bundles[bundleNamesByVersion[i]].LT(bundles[bundleNamesByVersion[j]])
+		slices.SortFunc(bundleNamesByVersion, func(a, b string) int {
+			return compareVersions(bundles[a], sv.releases[a], bundles[b], sv.releases[b])
 		})
 
 		// for each bundle (by version):
@@ -419,7 +513,7 @@ func (sv *SemverTemplateData) generateChannels(semverChannels *bundleVersions) [
 					}
 				}
 				ch.Entries = append(ch.Entries, declcfg.ChannelEntry{Name: bundleName})
-				unassociatedEdges = append(unassociatedEdges, entryTuple{arch: archetype, kind: cKey, parent: cName, name: bundleName, version: bundles[bundleName], index: len(ch.Entries) - 1})
+				unassociatedEdges = append(unassociatedEdges, entryTuple{arch: archetype, kind: cKey, parent: cName, name: bundleName, version: bundles[bundleName], release: sv.releases[bundleName], index: len(ch.Entries) - 1})
 			}
 		}
 	}
@@ -479,86 +573,80 @@ func (sv *SemverTemplateData) populatePackageMetadata(cfg *declcfg.DeclarativeCo
 	}
 }
 
+// linkChannels adds the edges between the entries of the channels. Entries are partitioned into segments of the same
+// archetype, stream type and major version, and each segment into minor version streams. Within a segment:
+//   - with the skips strategy, the head of a stream replaces the head of the previous stream and skips all other earlier
+//     entries of the segment
+//   - with the replaces strategy, each entry of a stream replaces the previous entry, and the first entry replaces the
+//     head of the previous stream
+//   - with GenerateSkipRange, the head of a stream also skips all earlier patch versions of the stream
 func (sv *SemverTemplateData) linkChannels(unlinkedChannels map[string]*declcfg.Channel, entries []entryTuple) []declcfg.Channel {
 	channels := make([]declcfg.Channel, 0, len(unlinkedChannels))
 
 	// sort to force partitioning by archetype --> kind --> semver
-	sort.Slice(entries, func(i, j int) bool {
-		if channelPriorities[entries[i].arch] != channelPriorities[entries[j].arch] {
-			return channelPriorities[entries[i].arch] < channelPriorities[entries[j].arch]
+	slices.SortStableFunc(entries, func(a, b entryTuple) int {
+		return cmp.Or(
+			cmp.Compare(channelPriorities[a.arch], channelPriorities[b.arch]),
+			cmp.Compare(streamTypePriorities[a.kind], streamTypePriorities[b.kind]),
+			compareVersions(a.version, a.release, b.version, b.release),
+		)
+	})
+
+	entryFor := func(e entryTuple) *declcfg.ChannelEntry {
+		return &unlinkedChannels[e.parent].Entries[e.index]
+	}
+
+	// the head of the previous stream in the segment, and all earlier entries of the segment
+	prevHead := ""
+	var earlier []string
+	for start := 0; start < len(entries); {
+		first := entries[start]
+		end := start + 1
+		for end < len(entries) && sameStream(first, entries[end]) {
+			end++
 		}
-		if streamTypePriorities[entries[i].kind] != streamTypePriorities[entries[j].kind] {
-			return streamTypePriorities[entries[i].kind] < streamTypePriorities[entries[j].kind]
+		stream := entries[start:end]
+		if start > 0 && !sameSegment(entries[start-1], first) {
+			// we don't maintain skips/replaces over these transitions
+			prevHead = ""
+			earlier = nil
 		}
-		return entries[i].version.LT(entries[j].version)
-	})
 
-	prevZMax := ""
-	var curSkips = sets.Set[string]{}
-
-	// iterate over the entries, starting from the second
-	// write any skips/replaces for the previous entry to the current entry
-	// then accumulate the skips/replaces for the current entry to be used in subsequent iterations
-	for index := 1; index < len(entries); index++ {
-		prevTuple := entries[index-1]
-		curTuple := entries[index]
-		prevX := getMajorVersion(prevTuple.version)
-		prevY := getMinorVersion(prevTuple.version)
-		curX := getMajorVersion(curTuple.version)
-		curY := getMinorVersion(curTuple.version)
-
-		archChange := curTuple.arch != prevTuple.arch
-		kindChange := curTuple.kind != prevTuple.kind
-		xChange := !prevX.EQ(curX)
-		yChange := !prevY.EQ(curY)
-
-		if archChange || kindChange || xChange || yChange {
-			// if we passed any kind of change besides Z, then we need to set skips/replaces for previous max-Z
-			prevChannel := unlinkedChannels[prevTuple.parent]
-			finalEntry := &prevChannel.Entries[prevTuple.index]
-			finalEntry.Replaces = prevZMax
-			skips := sets.List(curSkips.Difference(sets.New(finalEntry.Replaces)))
-			if len(skips) > 0 {
-				finalEntry.Skips = skips
+		// entries are only written to when they get edges
+		head := stream[len(stream)-1]
+		switch sv.edgeStrategy(head.version) {
+		case ReplacesEdgeStrategy:
+			prev := prevHead
+			for _, e := range stream {
+				if prev != "" {
+					entryFor(e).Replaces = prev
+				}
+				prev = e.name
+			}
+		default:
+			skips := sets.New(earlier...)
+			for _, e := range stream[:len(stream)-1] {
+				skips.Insert(e.name)
+			}
+			skips.Delete(prevHead)
+			if prevHead != "" {
+				entryFor(head).Replaces = prevHead
+			}
+			if skips.Len() > 0 {
+				entryFor(head).Skips = sets.List(skips)
 			}
 		}
-
-		if archChange || kindChange || xChange {
-			// we don't maintain skips/replaces over these transitions
-			curSkips = sets.Set[string]{}
-			prevZMax = ""
-		} else {
-			if yChange {
-				prevZMax = prevTuple.name
+		if sv.GenerateSkipRange {
+			if skipRange := streamSkipRange(head.version); skipRange != "" {
+				entryFor(head).SkipRange = skipRange
 			}
-			curSkips.Insert(prevTuple.name)
 		}
-	}
 
-	if len(entries) > 1 {
-		// add edges for the last entry
-		// note:  this is substantially similar to the main iteration, but there are some subtle differences since the main loop mode
-		//  design is to write the edges and then accumulate new info for subsequent edges (and this is the last edge):
-		// - we only need to watch for arch/kind/x change
-		// - we don't need to accumulate skips/replaces, since we're not writing edges for subsequent entries
-		lastTuple := entries[len(entries)-1]
-		penultimateTuple := entries[len(entries)-2]
-		prevX := getMajorVersion(penultimateTuple.version)
-		curX := getMajorVersion(lastTuple.version)
-
-		archChange := penultimateTuple.arch != lastTuple.arch
-		kindChange := penultimateTuple.kind != lastTuple.kind
-		xChange := !prevX.EQ(curX)
-		// for arch / kind / x changes, we don't maintain skips/replaces
-		if !archChange && !kindChange && !xChange {
-			prevChannel := unlinkedChannels[lastTuple.parent]
-			finalEntry := &prevChannel.Entries[lastTuple.index]
-			finalEntry.Replaces = prevZMax
-			skips := sets.List(curSkips.Difference(sets.New(finalEntry.Replaces)))
-			if len(skips) > 0 {
-				finalEntry.Skips = skips
-			}
+		for _, e := range stream {
+			earlier = append(earlier, e.name)
 		}
+		prevHead = head.name
+		start = end
 	}
 
 	for _, ch := range unlinkedChannels {
@@ -572,6 +660,27 @@ func (sv *SemverTemplateData) linkChannels(unlinkedChannels map[string]*declcfg.
 	return channels
 }
 
+// sameSegment returns true if the entries have the same archetype, stream type and major version
+func sameSegment(a, b entryTuple) bool {
+	return a.arch == b.arch && a.kind == b.kind && a.version.Major == b.version.Major
+}
+
+// sameStream returns true if the entries are in the same segment and minor version stream
+func sameStream(a, b entryTuple) bool {
+	return sameSegment(a, b) && a.version.Minor == b.version.Minor
+}
+
+// streamSkipRange returns a range of the patch versions of the minor version stream of head that are lower than head,
+// or an empty string if there are none
+func streamSkipRange(head semver.Version) string {
+	floor := semver.Version{Major: head.Major, Minor: head.Minor}
+	if head.LTE(floor) {
+		return ""
+	}
+	head.Build = nil
+	return fmt.Sprintf(">=%s <%s", floor, head)
+}
+
 func channelNameFromMinor(prefix channelArchetype, version semver.Version) string {
 	return fmt.Sprintf("%s-v%d.%d", prefix, version.Major, version.Minor)
 }
@@ -605,21 +714,7 @@ func combineConfigs(cfgs []declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig
 	return out
 }
 
-func getMinorVersion(v semver.Version) semver.Version {
-	return semver.Version{
-		Major: v.Major,
-		Minor: v.Minor,
-	}
-}
-
-// nolint:unused
-func getMajorVersion(v semver.Version) semver.Version {
-	return semver.Version{
-		Major: v.Major,
-	}
-}
-
-func withoutBuildMetadataConflict(versions *map[string]semver.Version) error {
+func withoutBuildMetadataConflict(versions *map[string]semver.Version, releases map[string]model.Release) error {
 	errs := []error{}
 
 	// using the stringified semver because the semver package generates deterministic representations,
@@ -628,6 +723,10 @@ func withoutBuildMetadataConflict(versions *map[string]semver.Version) error {
 	seen := make(map[string]int)
 	for b := range *versions {
 		stripped := stripBuildMetadata((*versions)[b])
+		// bundles of the same version are ordered by their release
+		if len(releases[b]) > 0 {
+			stripped += " release " + releases[b].String()
+		}
 		if _, ok := seen[stripped]; !ok {
 			seen[stripped] = 1
 		} else {
@@ -643,12 +742,12 @@ func withoutBuildMetadataConflict(versions *map[string]semver.Version) error {
 	return nil
 }
 
-func validateVersions(versions *map[string]semver.Version) error {
+func validateVersions(versions *map[string]semver.Version, releases map[string]model.Release) error {
 	// short-circuit if empty, since that is not an error
 	if len(*versions) == 0 {
 		return nil
 	}
-	return withoutBuildMetadataConflict(versions)
+	return withoutBuildMetadataConflict(versions, releases)
 }
 
 // strips out the build metadata from a semver.Version and then stringifies it to make it suitable for collision detection
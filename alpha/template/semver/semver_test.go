@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
 	"github.com/operator-framework/operator-registry/alpha/property"
 )
 
@@ -537,6 +538,155 @@ func TestGenerateChannels(t *testing.T) {
 	}
 }
 
+func TestGenerateChannelsEdgeOptions(t *testing.T) {
+	versions := bundleVersions{
+		"stable": {
+			"a-v1.0.0": semver.MustParse("1.0.0"),
+			"a-v1.0.1": semver.MustParse("1.0.1"),
+			"a-v1.1.0": semver.MustParse("1.1.0"),
+			"a-v1.1.1": semver.MustParse("1.1.1"),
+			"a-v1.1.2": semver.MustParse("1.1.2"),
+			"a-v1.2.0": semver.MustParse("1.2.0"),
+		},
+	}
+	releaseVersions := bundleVersions{
+		"stable": {
+			"a-v1.0.0-10": semver.MustParse("1.0.0"),
+			"a-v1.0.0-2":  semver.MustParse("1.0.0"),
+			"a-v1.0.0-1":  semver.MustParse("1.0.0"),
+			"a-v1.0.0":    semver.MustParse("1.0.0"),
+			"a-v1.0.1":    semver.MustParse("1.0.1"),
+		},
+	}
+	releases := map[string]model.Release{
+		"a-v1.0.0-1":  {semver.PRVersion{VersionNum: 1, IsNum: true}},
+		"a-v1.0.0-2":  {semver.PRVersion{VersionNum: 2, IsNum: true}},
+		"a-v1.0.0-10": {semver.PRVersion{VersionNum: 10, IsNum: true}},
+	}
+
+	type spec struct {
+		name     string
+		sv       SemverTemplateData
+		versions bundleVersions
+		expected []declcfg.ChannelEntry
+	}
+	specs := []spec{
+		{
+			name:     "Skips with skipRange",
+			sv:       SemverTemplateData{GenerateSkipRange: true},
+			versions: versions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.1", Skips: []string{"a-v1.0.0"}, SkipRange: ">=1.0.0 <1.0.1"},
+				{Name: "a-v1.1.0"},
+				{Name: "a-v1.1.1"},
+				{Name: "a-v1.1.2", Replaces: "a-v1.0.1", Skips: []string{"a-v1.0.0", "a-v1.1.0", "a-v1.1.1"}, SkipRange: ">=1.1.0 <1.1.2"},
+				{Name: "a-v1.2.0", Replaces: "a-v1.1.2", Skips: []string{"a-v1.0.0", "a-v1.0.1", "a-v1.1.0", "a-v1.1.1"}},
+			},
+		},
+		{
+			name:     "Replaces",
+			sv:       SemverTemplateData{EdgeStrategy: ReplacesEdgeStrategy},
+			versions: versions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.1", Replaces: "a-v1.0.0"},
+				{Name: "a-v1.1.0", Replaces: "a-v1.0.1"},
+				{Name: "a-v1.1.1", Replaces: "a-v1.1.0"},
+				{Name: "a-v1.1.2", Replaces: "a-v1.1.1"},
+				{Name: "a-v1.2.0", Replaces: "a-v1.1.2"},
+			},
+		},
+		{
+			name:     "Replaces with skipRange",
+			sv:       SemverTemplateData{EdgeStrategy: ReplacesEdgeStrategy, GenerateSkipRange: true},
+			versions: versions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.1", Replaces: "a-v1.0.0", SkipRange: ">=1.0.0 <1.0.1"},
+				{Name: "a-v1.1.0", Replaces: "a-v1.0.1"},
+				{Name: "a-v1.1.1", Replaces: "a-v1.1.0"},
+				{Name: "a-v1.1.2", Replaces: "a-v1.1.1", SkipRange: ">=1.1.0 <1.1.2"},
+				{Name: "a-v1.2.0", Replaces: "a-v1.1.2"},
+			},
+		},
+		{
+			name: "Per-stream strategy, minor over major",
+			sv: SemverTemplateData{EdgeStrategy: ReplacesEdgeStrategy, Streams: []streamOptions{
+				{Version: "1.1", EdgeStrategy: ReplacesEdgeStrategy},
+				{Version: "1", EdgeStrategy: SkipsEdgeStrategy},
+			}},
+			versions: versions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.1", Skips: []string{"a-v1.0.0"}},
+				{Name: "a-v1.1.0", Replaces: "a-v1.0.1"},
+				{Name: "a-v1.1.1", Replaces: "a-v1.1.0"},
+				{Name: "a-v1.1.2", Replaces: "a-v1.1.1"},
+				{Name: "a-v1.2.0", Replaces: "a-v1.1.2", Skips: []string{"a-v1.0.0", "a-v1.0.1", "a-v1.1.0", "a-v1.1.1"}},
+			},
+		},
+		{
+			name:     "Releases ordered after their version",
+			sv:       SemverTemplateData{EdgeStrategy: ReplacesEdgeStrategy, GenerateSkipRange: true, releases: releases},
+			versions: releaseVersions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.0-1", Replaces: "a-v1.0.0"},
+				{Name: "a-v1.0.0-2", Replaces: "a-v1.0.0-1"},
+				{Name: "a-v1.0.0-10", Replaces: "a-v1.0.0-2"},
+				{Name: "a-v1.0.1", Replaces: "a-v1.0.0-10", SkipRange: ">=1.0.0 <1.0.1"},
+			},
+		},
+		{
+			name:     "Releases skipped by the stream head",
+			sv:       SemverTemplateData{releases: releases},
+			versions: releaseVersions,
+			expected: []declcfg.ChannelEntry{
+				{Name: "a-v1.0.0"},
+				{Name: "a-v1.0.0-1"},
+				{Name: "a-v1.0.0-2"},
+				{Name: "a-v1.0.0-10"},
+				{Name: "a-v1.0.1", Skips: []string{"a-v1.0.0", "a-v1.0.0-1", "a-v1.0.0-10", "a-v1.0.0-2"}},
+			},
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			sv := s.sv
+			sv.pkg = "a"
+			sv.GenerateMajorChannels = true
+			sv.DefaultChannelTypePreference = MajorStreamType
+			out := sv.generateChannels(&s.versions)
+			expected := []declcfg.Channel{{Schema: "olm.channel", Name: "stable-v1", Package: "a", Entries: s.expected}}
+			if diff := gocmp.Diff(expected, out); diff != "" {
+				t.Errorf("unexpected generated channels (-expected +received):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetVersionsWithReleases(t *testing.T) {
+	sv := SemverTemplateData{
+		Stable: channelBundles{[]bundleEntry{
+			{Image: "repo/origin/a-v1.0.0-1"},
+			{Image: "repo/origin/a-v1.0.0-2"},
+		}},
+	}
+	dc := declcfg.DeclarativeConfig{
+		Bundles: []declcfg.Bundle{
+			{Schema: "olm.bundle", Image: "repo/origin/a-v1.0.0-1", Name: "a-v1.0.0-1", Properties: []property.Property{property.MustBuildPackageRelease("a", "1.0.0", "1")}},
+			{Schema: "olm.bundle", Image: "repo/origin/a-v1.0.0-2", Name: "a-v1.0.0-2", Properties: []property.Property{property.MustBuildPackageRelease("a", "1.0.0", "2")}},
+		},
+	}
+
+	// bundles that differ only by release can be ordered
+	versions, err := sv.getVersionsFromStandardChannels(&dc, buildBundleList(sv))
+	require.NoError(t, err)
+	require.Len(t, (*versions)[stableChannelArchetype], 2)
+	require.Equal(t, "2", sv.releases["a-v1.0.0-2"].String())
+}
+
 func TestGetVersionsFromStandardChannel(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1141,6 +1291,42 @@ invalid:
 				require.ErrorContains(t, err, "unknown DefaultChannelTypePreference")
 			},
 		},
+		{
+			name:  "edge options",
+			input: "schema: olm.semver\nedgeStrategy: replaces\ngenerateSkipRange: true\nstreams:\n  - version: \"1\"\n    edgeStrategy: skips\n  - version: \"1.2\"\n",
+			assertions: func(t *testing.T, template *SemverTemplateData, err error) {
+				require.NoError(t, err)
+				require.Equal(t, ReplacesEdgeStrategy, template.EdgeStrategy)
+				require.True(t, template.GenerateSkipRange)
+				require.Equal(t, SkipsEdgeStrategy, template.edgeStrategy(semver.MustParse("1.3.0")))
+				require.Equal(t, SkipsEdgeStrategy, template.edgeStrategy(semver.MustParse("1.2.0")))
+				require.Equal(t, ReplacesEdgeStrategy, template.edgeStrategy(semver.MustParse("2.0.0")))
+			},
+		},
+		{
+			name:  "unknown edge strategy",
+			input: "schema: olm.semver\nedgeStrategy: chain\n",
+			assertions: func(t *testing.T, template *SemverTemplateData, err error) {
+				require.Nil(t, template)
+				require.ErrorContains(t, err, `unknown EdgeStrategy: "chain"`)
+			},
+		},
+		{
+			name:  "invalid stream version",
+			input: "schema: olm.semver\nstreams:\n  - version: 1.2.3\n    edgeStrategy: replaces\n",
+			assertions: func(t *testing.T, template *SemverTemplateData, err error) {
+				require.Nil(t, template)
+				require.ErrorContains(t, err, `invalid stream version "1.2.3"`)
+			},
+		},
+		{
+			name:  "duplicate stream version",
+			input: "schema: olm.semver\nstreams:\n  - version: \"1.2\"\n  - version: \"1.2\"\n",
+			assertions: func(t *testing.T, template *SemverTemplateData, err error) {
+				require.Nil(t, template)
+				require.ErrorContains(t, err, `duplicate options for stream version "1.2"`)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
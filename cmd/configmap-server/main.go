@@ -44,6 +44,7 @@ func init() {
 	rootCmd.Flags().StringP("configMapName", "c", "", "name of a configmap")
 	rootCmd.Flags().StringP("configMapNamespace", "n", "", "namespace of a configmap")
 	rootCmd.Flags().StringP("port", "p", "50051", "port number to serve on")
+	rootCmd.Flags().String("metrics-addr", "", "if set, address on which to serve Prometheus metrics at /metrics (addr:port format)")
 	rootCmd.Flags().StringP("termination-log", "t", "/dev/termination-log", "path to a container termination log file")
 	rootCmd.Flags().Bool("permissive", false, "allow registry load errors")
 	if err := rootCmd.Flags().MarkHidden("debug"); err != nil {
@@ -152,7 +153,19 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		logger.Fatalf("failed to listen: %s", err)
 	}
-	s := grpc.NewServer()
+	metricsAddr, err := cmd.Flags().GetString("metrics-addr")
+	if err != nil {
+		return err
+	}
+	var serverOpts []grpc.ServerOption
+	if metricsAddr != "" {
+		metrics := server.NewMetrics()
+		serverOpts = append(serverOpts, metrics.ServerOptions()...)
+		if err := metrics.ServeOn(ctx, metricsAddr, logger); err != nil {
+			logger.Fatal(err)
+		}
+	}
+	s := grpc.NewServer(serverOpts...)
 
 	api.RegisterRegistryServer(s, server.NewRegistryServer(store))
 	health.RegisterHealthServer(s, server.NewHealthServer())
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+func newAddBundleCmd() *cobra.Command {
+	var (
+		add  action.AddBundle
+		mode string
+	)
+	cmd := &cobra.Command{
+		Use:   "add-bundle <fbcRootDir> <bundleImage>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Add a bundle to a file-based catalog",
+		Long: `Add a bundle image to a file-based catalog directory.
+
+The bundle is inserted into the channels named in its annotations, which are
+created if they do not exist. The upgrade edges of the bundle are computed
+according to the update mode, as with "opm index add":
+
+  replaces          use the replaces and skips of the bundle's CSV
+  semver            insert the bundle between its neighbours by version
+  semver-skippatch  like semver, and also skip lower patch versions of the
+                    same major and minor version
+
+The resulting package is validated before any file is written, and only the
+files that contain the bundle's package are rewritten.
+`,
+		Example: `
+#
+# Add a bundle, using the replaces of its CSV
+#
+$ opm alpha catalog add-bundle ./catalog quay.io/example/foo-bundle:v0.2.0
+
+#
+# Add a bundle, computing its upgrade edges from its version
+#
+$ opm alpha catalog add-bundle ./catalog quay.io/example/foo-bundle:v0.2.1 --mode semver-skippatch
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modeEnum, err := registry.GetModeFromString(mode)
+			if err != nil {
+				return err
+			}
+
+			reg, err := util.CreateCLIRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = reg.Destroy()
+			}()
+
+			add.CatalogDir = args[0]
+			add.BundleImage = args[1]
+			add.Mode = modeEnum
+			add.Registry = reg
+			add.Log = logrus.NewEntry(logrus.StandardLogger())
+			return add.Run(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVar(&mode, "mode", "replaces", "graph update mode that defines how channel graphs are updated. One of: [replaces, semver, semver-skippatch]")
+	return cmd
+}
@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+)
+
+func newBuildCmd() *cobra.Command {
+	var (
+		build          action.BuildCatalogImage
+		extraLabelStrs []string
+	)
+	cmd := &cobra.Command{
+		Use:   "build <fbcRootDir>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Build a catalog image from a file-based catalog",
+		Long: `Build a catalog image from a file-based catalog, without a container runtime.
+
+The catalog is added to the base image at /configs, along with a serve cache
+pre-built at /tmp/cache, as "opm generate dockerfile" would. The image is
+labeled with the location of the catalog, and, unless the base image is
+"scratch", configured to run "opm serve" from the base image.
+
+The serve cache is built by this opm binary, so the base image should contain
+the same version of opm.
+
+By default, the image is pushed to the registry of its reference. With
+--oci-layout, it is instead written to an OCI image layout directory, tagged
+with the image reference, from which it can be copied with tools such as skopeo.
+`,
+		Example: `
+#
+# Build and push a catalog image
+#
+$ opm alpha catalog build ./catalog --tag quay.io/example/catalog:latest
+
+#
+# Write a catalog image with a pinned base image to an OCI layout directory
+#
+$ opm alpha catalog build ./catalog --tag quay.io/example/catalog:latest \
+    --base-image quay.io/operator-framework/opm:v1.50.0 --oci-layout ./catalog-image
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			extraLabels, err := parseLabels(extraLabelStrs)
+			if err != nil {
+				return err
+			}
+
+			reg, err := util.CreateCLIRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = reg.Destroy()
+			}()
+
+			build.CatalogDir = filepath.Clean(args[0])
+			build.ExtraLabels = extraLabels
+			build.Registry = reg
+			build.Log = logrus.NewEntry(logrus.StandardLogger())
+			return build.Run(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVarP(&build.ImageRef, "tag", "t", "", "Reference of the image to build (required)")
+	cmd.Flags().StringVarP(&build.BaseImage, "base-image", "i", containertools.DefaultBinarySourceImage, `Image base to use to build catalog, or "scratch".`)
+	cmd.Flags().StringVar(&build.OCILayoutDir, "oci-layout", "", "If set, write the image to this OCI image layout directory instead of pushing it")
+	cmd.Flags().StringSliceVarP(&extraLabelStrs, "extra-labels", "l", []string{}, "Extra labels to set on the image. Labels should be of the form 'key=value'.")
+	_ = cmd.MarkFlagRequired("tag")
+	return cmd
+}
+
+func parseLabels(labelStrs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, l := range labelStrs {
+		k, v, ok := strings.Cut(l, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q", l)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
@@ -0,0 +1,22 @@
+package catalog
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Build and modify file-based catalogs",
+		Args:  cobra.NoArgs,
+		Run:   func(_ *cobra.Command, _ []string) {}, // adding an empty function here to preserve non-zero exit status for misstated subcommands/flags for the command hierarchy
+	}
+	cmd.AddCommand(
+		newBuildCmd(),
+		newAddBundleCmd(),
+		newPruneCmd(),
+		newPruneStrandedCmd(),
+		newTruncateCmd(),
+	)
+	return cmd
+}
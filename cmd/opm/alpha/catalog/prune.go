@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+const pruneRefsHelp = `The catalog can be anything that "opm render" accepts for catalogs: catalog
+images, file-based catalog directories and sqlite databases. The result is
+streamed to stdout, like "opm render", and is validated before it is written.
+`
+
+func newPruneCmd() *cobra.Command {
+	var (
+		output   string
+		packages []string
+	)
+	cmd := &cobra.Command{
+		Use:   "prune <catalogRef>",
+		Short: "Keep only the given packages of a catalog",
+		Long: `Keep only the given packages of a catalog, like "opm index prune".
+
+` + pruneRefsHelp,
+		Example: `
+#
+# Keep only the foo and bar packages of a catalog image
+#
+$ opm alpha catalog prune quay.io/example/catalog:latest --packages foo,bar > catalog/catalog.json
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runCatalogAction(cmd, output, func(ctx context.Context, reg image.Registry) (*declcfg.DeclarativeConfig, error) {
+				return action.PrunePackages{CatalogRef: args[0], Packages: packages, Registry: reg}.Run(ctx)
+			})
+		},
+	}
+	cmd.Flags().StringSliceVarP(&packages, "packages", "p", nil, "comma separated list of packages to keep")
+	_ = cmd.MarkFlagRequired("packages")
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format of the streamed file-based catalog objects (json|yaml)")
+	return cmd
+}
+
+func newPruneStrandedCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "prune-stranded <catalogRef>",
+		Short: "Remove stranded bundles from a catalog",
+		Long: `Remove stranded bundles from a catalog, like "opm index prune-stranded".
+
+A channel entry is stranded if it is neither on the replaces chain that starts
+at the channel head nor skipped by another entry. Stranded entries are removed
+from their channels, and bundles that are no longer in any channel are removed
+from the catalog.
+
+` + pruneRefsHelp,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runCatalogAction(cmd, output, func(ctx context.Context, reg image.Registry) (*declcfg.DeclarativeConfig, error) {
+				return action.PruneStranded{CatalogRef: args[0], Registry: reg}.Run(ctx)
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format of the streamed file-based catalog objects (json|yaml)")
+	return cmd
+}
+
+func newTruncateCmd() *cobra.Command {
+	var (
+		output   string
+		truncate action.TruncateChannel
+	)
+	cmd := &cobra.Command{
+		Use:   "truncate <catalogRef>",
+		Short: "Truncate the upgrade graph of a package below a bundle",
+		Long: `Truncate the upgrade graph of a package below a bundle, like the truncation
+performed by "opm index deprecatetruncate".
+
+All entries that the bundle replaces or skips, directly or transitively, are
+removed from the channel, or from all channels of the package that contain
+the bundle if no channel is given. Bundles that are no longer in any channel
+are removed from the catalog.
+
+` + pruneRefsHelp,
+		Example: `
+#
+# Make foo.v0.2.0 the oldest version of the foo package in the stable channel
+#
+$ opm alpha catalog truncate ./catalog --package foo --bundle foo.v0.2.0 --channel stable
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runCatalogAction(cmd, output, func(ctx context.Context, reg image.Registry) (*declcfg.DeclarativeConfig, error) {
+				truncate.CatalogRef = args[0]
+				truncate.Registry = reg
+				return truncate.Run(ctx)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&truncate.Package, "package", "", "package of the bundle")
+	cmd.Flags().StringVar(&truncate.Bundle, "bundle", "", "name of the bundle below which to truncate")
+	cmd.Flags().StringVar(&truncate.Channel, "channel", "", "channel to truncate (default: all channels that contain the bundle)")
+	_ = cmd.MarkFlagRequired("package")
+	_ = cmd.MarkFlagRequired("bundle")
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format of the streamed file-based catalog objects (json|yaml)")
+	return cmd
+}
+
+// runCatalogAction runs an action that transforms a catalog, and writes the
+// result to stdout in the given format.
+func runCatalogAction(cmd *cobra.Command, output string, run func(context.Context, image.Registry) (*declcfg.DeclarativeConfig, error)) {
+	var write func(declcfg.DeclarativeConfig, io.Writer) error
+	switch output {
+	case "yaml":
+		write = declcfg.WriteYAML
+	case "json":
+		write = declcfg.WriteJSON
+	default:
+		log.Fatalf("invalid --output value %q, expected (json|yaml)", output)
+	}
+
+	// The bundle loading impl is somewhat verbose, even on the happy path,
+	// so discard all logrus default logger logs. Any important failures will be
+	// returned from the action and logged as fatal errors.
+	logrus.SetOutput(io.Discard)
+
+	reg, err := util.CreateCLIRegistry(cmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		_ = reg.Destroy()
+	}()
+
+	cfg, err := run(cmd.Context(), reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := write(*cfg, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
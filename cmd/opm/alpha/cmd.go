@@ -4,10 +4,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/bundle"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/catalog"
 	converttemplate "github.com/operator-framework/operator-registry/cmd/opm/alpha/convert-template"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/diff"
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/list"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/render"
 	rendergraph "github.com/operator-framework/operator-registry/cmd/opm/alpha/render-graph"
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/template"
+	upgradepath "github.com/operator-framework/operator-registry/cmd/opm/alpha/upgrade-path"
 )
 
 func NewCmd(showAlphaHelp bool) *cobra.Command {
@@ -28,6 +32,10 @@ func NewCmd(showAlphaHelp bool) *cobra.Command {
 		rendergraph.NewCmd(),
 		template.NewCmd(),
 		converttemplate.NewCmd(),
+		diff.NewCmd(),
+		upgradepath.NewCmd(),
+		catalog.NewCmd(),
+		render.NewCmd(),
 	)
 	return runCmd
 }
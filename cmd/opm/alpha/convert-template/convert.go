@@ -3,6 +3,7 @@ package converttemplate
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -18,6 +19,7 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(
 		newBasicConvertCmd(),
 		newSubstitutesConvertCmd(),
+		newSemverConvertCmd(),
 	)
 	return cmd
 }
@@ -105,3 +107,55 @@ If no argument is specified or is '-' input is assumed from STDIN.
 
 	return cmd
 }
+
+func newSemverConvertCmd() *cobra.Command {
+	var (
+		converter converter.Converter
+		output    string
+	)
+	cmd := &cobra.Command{
+		Use:   "semver [<fbc-file> | -]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Generate a semver template from existing FBC",
+		Long: `Generate a semver template from existing FBC.
+
+This command outputs a semver catalog template to STDOUT from input FBC for a
+single package. If no argument is specified or is '-' input is assumed from STDIN.
+
+Bundles are listed as candidate, fast or stable bundles according to the names
+of the channels they are in. Major and minor version channel generation are
+each tried, alone and together, and the option whose rendered channels differ
+least from the input is used.
+
+A report is written to STDERR that says whether the template reproduces the
+input channels, and lists every channel entry, replaces, skips and skipRange
+edge that rendering the template would add (+) or remove (-).
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			switch output {
+			case "yaml", "json":
+				converter.OutputFormat = output
+			default:
+				log.Fatalf("invalid --output value %q, expected (json|yaml)", output)
+			}
+
+			reader, name, err := util.OpenFileOrStdin(c, args)
+			if err != nil {
+				return fmt.Errorf("unable to open input: %q", name)
+			}
+
+			converter.FbcReader = reader
+			converter.DestinationTemplateType = "semver"
+			converter.Report = os.Stderr
+			err = converter.Convert()
+			if err != nil {
+				return fmt.Errorf("converting: %v", err)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format (json|yaml)")
+
+	return cmd
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+)
+
+func NewCmd() *cobra.Command {
+	var (
+		output   string
+		exitCode bool
+	)
+	cmd := &cobra.Command{
+		Use:   "diff <old-ref> <new-ref>",
+		Short: "Compare two catalogs structurally",
+		Long: `Compare two catalogs structurally.
+
+The references can be anything that "opm render" accepts: catalog images,
+file-based catalog directories, sqlite databases, and bundle images or
+directories. Packages, channels, channel entries, bundles, deprecations, and
+custom schema blobs are matched by identity, so differences in the ordering of
+objects, properties, or JSON keys are not reported.`,
+		Example: `
+#
+# Show what changed between two versions of a catalog image
+#
+$ opm alpha diff quay.io/example/catalog:v1 quay.io/example/catalog:v2
+
+#
+# Compare a catalog image with a local file-based catalog and output JSON
+#
+$ opm alpha diff quay.io/example/catalog:v1 ./catalog -o json
+`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			// The bundle loading impl is somewhat verbose, even on the happy path,
+			// so discard all logrus default logger logs. Any important failures will be
+			// returned from diff.Run and logged as fatal errors.
+			logrus.SetOutput(io.Discard)
+
+			reg, err := util.CreateCLIRegistry(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() {
+				_ = reg.Destroy()
+			}()
+
+			diff := action.Diff{
+				OldRef:   args[0],
+				NewRef:   args[1],
+				Registry: reg,
+			}
+			res, err := diff.Run(cmd.Context())
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			switch output {
+			case "text":
+				err = res.WriteText(os.Stdout)
+			case "json":
+				err = res.WriteJSON(os.Stdout)
+			default:
+				log.Fatalf("invalid --output value %q, expected (text|json)", output)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+			if exitCode && !res.Empty() {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "exit with status 1 if the catalogs differ")
+	return cmd
+}
@@ -19,12 +19,20 @@ func NewCmd() *cobra.Command {
 		minEdge              string
 		specifiedPackageName string
 		drawV0Semantics      bool
+		output               string
 	)
 	cmd := &cobra.Command{
 		Use:   "render-graph [index-image | fbc-dir]",
-		Short: "Generate mermaid-formatted view of upgrade graph of operators in an index",
-		Long:  `Generate mermaid-formatted view of upgrade graphs of operators in an index`,
-		Args:  cobra.MinimumNArgs(1),
+		Short: "Generate a view of the upgrade graph of operators in an index",
+		Long: `Generate a view of the upgrade graphs of operators in an index.
+
+The graph is written in one of these formats:
+
+  mermaid  a mermaid flowchart, for mermaid renderers like github and mermaid.live
+  dot      a Graphviz digraph, for rendering large catalogs with dot(1)
+  html     a self-contained HTML page that draws the graph in the browser, with
+           a toggle per channel`,
+		Args: cobra.MinimumNArgs(1),
 		Example: `
 #
 # Output channel graph of a catalog in mermaid format
@@ -46,9 +54,38 @@ EOF
 $ opm alpha render-graph quay.io/operatorhubio/catalog:latest | \
     docker run --rm -i -v "$PWD":/data ghcr.io/mermaid-js/mermaid-cli/mermaid-cli -c /data/mermaid.json -o /data/operatorhubio-catalog.svg
 
+#
+# Output channel graph of a catalog in DOT format, which has no size limit, and generate an SVG representation
+#
+$ opm alpha render-graph quay.io/operatorhubio/catalog:latest -o dot | dot -Tsvg -o operatorhubio-catalog.svg
+
+#
+# Output channel graph of a catalog as an HTML page
+#
+$ opm alpha render-graph quay.io/operatorhubio/catalog:latest -o html > operatorhubio-catalog.html
 
 		`,
 		Run: func(cmd *cobra.Command, args []string) {
+			opts := declcfg.GraphOptions{
+				MinEdgeName:          minEdge,
+				SpecifiedPackageName: specifiedPackageName,
+				DrawV0Semantics:      drawV0Semantics,
+			}
+			var write func(declcfg.DeclarativeConfig, io.Writer) error
+			switch output {
+			case "mermaid":
+				write = declcfg.NewMermaidWriter(
+					declcfg.WithMinEdgeName(minEdge),
+					declcfg.WithSpecifiedPackageName(specifiedPackageName),
+					declcfg.WithV0Semantics(drawV0Semantics)).WriteChannels
+			case "dot":
+				write = declcfg.NewDotWriter(opts).WriteChannels
+			case "html":
+				write = declcfg.NewHTMLWriter(opts).WriteChannels
+			default:
+				log.Fatalf("invalid --output value %q, expected (mermaid|dot|html)", output)
+			}
+
 			// The bundle loading impl is somewhat verbose, even on the happy path,
 			// so discard all logrus default logger logs. Any important failures will be
 			// returned from render.Run and logged as fatal errors.
@@ -68,11 +105,7 @@ $ opm alpha render-graph quay.io/operatorhubio/catalog:latest | \
 				log.Fatal(err)
 			}
 
-			writer := declcfg.NewMermaidWriter(
-				declcfg.WithMinEdgeName(minEdge),
-				declcfg.WithSpecifiedPackageName(specifiedPackageName),
-				declcfg.WithV0Semantics(drawV0Semantics))
-			if err := writer.WriteChannels(*cfg, os.Stdout); err != nil {
+			if err := write(*cfg, os.Stdout); err != nil {
 				log.Fatal(err)
 			}
 		},
@@ -80,5 +113,6 @@ $ opm alpha render-graph quay.io/operatorhubio/catalog:latest | \
 	cmd.Flags().StringVar(&minEdge, "minimum-edge", "", "the channel edge to be used as the lower bound of the set of edges composing the upgrade graph; default is to include all edges")
 	cmd.Flags().StringVarP(&specifiedPackageName, "package-name", "p", "", "a specific package name to filter output; default is to include all packages in reference")
 	cmd.Flags().BoolVar(&drawV0Semantics, "draw-v0-semantics", false, "whether to indicate OLMv0 semantics in the output; default is to simply represent the upgrade graph")
+	cmd.Flags().StringVarP(&output, "output", "o", "mermaid", "Output format (mermaid|dot|html)")
 	return cmd
 }
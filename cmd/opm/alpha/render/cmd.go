@@ -0,0 +1,102 @@
+package render
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/filter"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+)
+
+func NewCmd() *cobra.Command {
+	var (
+		render     action.Render
+		output     string
+		filterFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "render [catalog-image | catalog-directory | sqlite-file]... --filter <file>",
+		Short: "Generate a subset of a catalog selected by a filter configuration",
+		Long: `Generate a stream of file-based catalog objects to stdout from the provided
+catalogs, like "opm render", keeping only the packages, channels and versions
+selected by a filter configuration.
+
+Within a channel with a version range, the bundles needed to keep the upgrade
+graph connected are also kept. If the default channel of a package is filtered
+out, the selected channel whose head has the highest version becomes the
+default channel, unless the configuration sets one. The result is validated
+before it is written.
+
+Example filter configuration:
+
+  apiVersion: olm.operatorframework.io/filter/v1alpha1
+  kind: FilterConfiguration
+  packages:
+    - name: foo
+      defaultChannel: stable
+      channels:
+        - name: stable
+          versionRange: ">=1.2.0 <2.0.0"
+        - name: candidate
+    - name: bar
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			render.Refs = args
+
+			var write func(declcfg.DeclarativeConfig, io.Writer) error
+			switch output {
+			case "yaml":
+				write = declcfg.WriteYAML
+			case "json":
+				write = declcfg.WriteJSON
+			default:
+				log.Fatalf("invalid --output value %q, expected (json|yaml)", output)
+			}
+
+			f, err := os.Open(filterFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			filterConfig, err := filter.LoadConfiguration(f)
+			_ = f.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+			render.Filter = filterConfig
+
+			// The bundle loading impl is somewhat verbose, even on the happy path,
+			// so discard all logrus default logger logs. Any important failures will be
+			// returned from render.Run and logged as fatal errors.
+			logrus.SetOutput(io.Discard)
+
+			reg, err := util.CreateCLIRegistry(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() {
+				_ = reg.Destroy()
+			}()
+			render.Registry = reg
+			render.AllowedRefMask = action.RefDCImage | action.RefDCDir | action.RefSqliteImage | action.RefSqliteFile
+
+			cfg, err := render.Run(cmd.Context())
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := write(*cfg, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format of the streamed file-based catalog objects (json|yaml)")
+	cmd.Flags().StringVar(&filterFile, "filter", "", "Path to the filter configuration file")
+	_ = cmd.MarkFlagRequired("filter")
+	return cmd
+}
@@ -1,14 +1,21 @@
 package template
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/cobra"
 
 	"github.com/operator-framework/operator-registry/alpha/action/migrations"
 	alphatemplate "github.com/operator-framework/operator-registry/alpha/template"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
 )
 
 func NewCmd() *cobra.Command {
-	var output, migrateLevel string
+	var output, outputDir, migrateLevel, lockFile string
+	var updateLock bool
+	var pluginDirs []string
+	var pluginConfig string
 	tr := alphatemplate.NewRegistry()
 
 	runCmd := &cobra.Command{
@@ -26,15 +33,58 @@ Examples:
   opm alpha render-template basic template.yaml
   opm alpha render-template semver template.yaml  
   opm alpha render-template template.yaml  # auto-detect type
-  opm alpha render-template < template.yaml  # auto-detect from stdin`,
+  opm alpha render-template < template.yaml  # auto-detect from stdin
+
+With --lock-file, the digest that each bundle reference resolves to and the
+name of the bundle it renders are recorded in the lock file on the first
+render. Later renders use the locked digests instead of resolving tags again,
+and fail if the template and the lock file do not match. Pass --update-lock
+to resolve all references again and rewrite the lock file.
+
+  opm alpha render-template semver template.yaml --lock-file template.lock.yaml
+
+A composite template renders other template files, concurrently, into one
+catalog. Component paths are relative to the composite template file, and
+each component's type is detected from its schema unless set. Rendering fails
+if more than one component renders the same package, channel or bundle.
+
+  schema: olm.template.composite
+  components:
+    - path: foo/semver.yaml
+    - path: bar/basic.yaml
+      type: basic
+
+With --output-dir, the catalog is written to one directory per package
+instead of to standard output:
+
+  opm alpha render-template composite.yaml -o yaml --output-dir catalog
+
+Template plugins are executables that render templates of other schemas.
+Executables named opm-template-<name> in the --template-plugin-dir
+directories (default: $OPM_TEMPLATE_PLUGIN_PATH) and those listed in the
+--template-plugin-config file are registered like built-in templates, so
+their schemas are auto-detected and their types can be passed as TYPE.
+
+  apiVersion: olm.operatorframework.io/template-plugins/v1alpha1
+  kind: TemplatePluginConfiguration
+  plugins:
+    - path: ./bin/release-calendar`,
 		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRenderTemplate(cmd, args, tr)
 		},
 	}
 
-	runCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output format (json|yaml|mermaid)")
+	runCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output format (json|yaml|mermaid|dot|html)")
+	runCmd.PersistentFlags().StringVar(&outputDir, "output-dir", "", "Write the catalog to a directory per package in this directory, instead of to standard output")
 	runCmd.PersistentFlags().StringVar(&migrateLevel, "migrate-level", "", "Name of the last migration to run (default: none)\n"+migrations.HelpText())
 
+	runCmd.PersistentFlags().StringVar(&lockFile, "lock-file", "", "Path to a lock file that pins bundle references to digests; created if it does not exist")
+	runCmd.PersistentFlags().BoolVar(&updateLock, "update-lock", false, "Resolve bundle references again and rewrite the lock file")
+	util.AddBundleCacheFlags(runCmd.PersistentFlags())
+
+	runCmd.PersistentFlags().StringSliceVar(&pluginDirs, "template-plugin-dir", filepath.SplitList(os.Getenv("OPM_TEMPLATE_PLUGIN_PATH")), "Directories to discover opm-template-* template plugin executables in")
+	runCmd.PersistentFlags().StringVar(&pluginConfig, "template-plugin-config", "", "Path to a configuration file that lists template plugin executables")
+
 	return runCmd
 }
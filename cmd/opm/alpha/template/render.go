@@ -1,11 +1,14 @@
 package template
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -13,13 +16,20 @@ import (
 	"github.com/operator-framework/operator-registry/alpha/action/migrations"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	alphatemplate "github.com/operator-framework/operator-registry/alpha/template"
+	"github.com/operator-framework/operator-registry/alpha/template/composite"
+	"github.com/operator-framework/operator-registry/alpha/template/plugin"
 	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+	"github.com/operator-framework/operator-registry/pkg/image"
 )
 
 // runRenderTemplate handles the unified template rendering logic
 func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Registry) error {
 	var templateType, filePath string
 
+	if err := registerPlugins(cmd, tr); err != nil {
+		return err
+	}
+
 	// Parse arguments based on number provided
 	switch len(args) {
 	case 0:
@@ -51,6 +61,11 @@ func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Regis
 	}
 	defer data.Close()
 
+	// Component paths of composite templates are relative to the template file
+	if filePath != "-" {
+		tr.Register(&composite.Factory{Creator: tr, BaseDir: filepath.Dir(filePath)})
+	}
+
 	// Determine output format
 	var write func(declcfg.DeclarativeConfig, io.Writer) error
 	output, err := cmd.Flags().GetString("output")
@@ -67,8 +82,19 @@ func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Regis
 			mermaidWriter := declcfg.NewMermaidWriter()
 			return mermaidWriter.WriteChannels(cfg, writer)
 		}
+	case "dot":
+		write = declcfg.NewDotWriter(declcfg.GraphOptions{DrawV0Semantics: true}).WriteChannels
+	case "html":
+		write = declcfg.NewHTMLWriter(declcfg.GraphOptions{DrawV0Semantics: true}).WriteChannels
 	default:
-		return fmt.Errorf("invalid --output value %q, expected (json|yaml|mermaid)", output)
+		return fmt.Errorf("invalid --output value %q, expected (json|yaml|mermaid|dot|html)", output)
+	}
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		return err
+	}
+	if outputDir != "" && output != "json" && output != "yaml" {
+		return fmt.Errorf("--output-dir cannot be used with --output=%s", output)
 	}
 
 	// The bundle loading impl is somewhat verbose, even on the happy path,
@@ -94,6 +120,11 @@ func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Regis
 		}
 	}
 
+	bundleCache, err := util.CreateBundleCache(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Create render bundle function
 	renderBundle := alphatemplate.BundleRenderer(func(ctx context.Context, image string) (*declcfg.DeclarativeConfig, error) {
 		renderer := action.Render{
@@ -101,10 +132,40 @@ func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Regis
 			Registry:       reg,
 			AllowedRefMask: action.RefBundleImage,
 			Migrations:     m,
+			BundleCache:    bundleCache,
 		}
 		return renderer.Run(ctx)
 	})
 
+	// Pin bundle references to the digests in the lock file, if one is used
+	lockFile, err := cmd.Flags().GetString("lock-file")
+	if err != nil {
+		return err
+	}
+	updateLock, err := cmd.Flags().GetBool("update-lock")
+	if err != nil {
+		return err
+	}
+	var lockingRenderer *alphatemplate.LockingRenderer
+	if lockFile != "" {
+		lock, err := alphatemplate.LoadLockFile(lockFile)
+		if err != nil {
+			return fmt.Errorf("loading lock file: %v", err)
+		}
+		resolver, ok := reg.(image.Resolver)
+		if !ok {
+			return fmt.Errorf("registry cannot resolve image digests")
+		}
+		resolve := func(ctx context.Context, ref string) (digest.Digest, error) {
+			return resolver.Digest(ctx, image.SimpleReference(ref))
+		}
+		// The first render records the lock
+		lockingRenderer = alphatemplate.NewLockingRenderer(lock, resolve, renderBundle, updateLock || lock == nil)
+		renderBundle = lockingRenderer.RenderBundle
+	} else if updateLock {
+		return fmt.Errorf("--update-lock requires --lock-file")
+	}
+
 	var tmpl alphatemplate.Template
 	// a reader for the schema data.  in the simple case, this is just 'data'.
 	// in the case where we auto-detect the schema, this is a reader that
@@ -129,13 +190,76 @@ func runRenderTemplate(cmd *cobra.Command, args []string, tr alphatemplate.Regis
 	// Render the template
 	cfg, err := tmpl.Render(cmd.Context(), renderReader)
 	if err != nil {
+		if lockingRenderer != nil {
+			if staleErr := lockingRenderer.StaleError(); staleErr != nil {
+				return fmt.Errorf("rendering template: %v, rerun with --update-lock to update it", staleErr)
+			}
+		}
 		return fmt.Errorf("rendering template: %v", err)
 	}
 
+	if lockingRenderer != nil {
+		lock, err := lockingRenderer.Lock()
+		if err != nil {
+			return fmt.Errorf("%v, rerun with --update-lock to update it", err)
+		}
+		var buf bytes.Buffer
+		if err := alphatemplate.WriteLock(*lock, &buf); err != nil {
+			return fmt.Errorf("writing lock file: %v", err)
+		}
+		if err := os.WriteFile(lockFile, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("writing lock file: %v", err)
+		}
+	}
+
 	// Write output
+	if outputDir != "" {
+		if err := declcfg.WriteFS(*cfg, outputDir, write, "."+output); err != nil {
+			return fmt.Errorf("writing output: %v", err)
+		}
+		return nil
+	}
 	if err := write(*cfg, os.Stdout); err != nil {
 		return fmt.Errorf("writing output: %v", err)
 	}
 
 	return nil
 }
+
+// registerPlugins registers the template plugins found in the plugin
+// directories and named in the plugin configuration. Plugins cannot replace
+// built-in templates, or each other.
+func registerPlugins(cmd *cobra.Command, tr alphatemplate.Registry) error {
+	dirs, err := cmd.Flags().GetStringSlice("template-plugin-dir")
+	if err != nil {
+		return err
+	}
+	configFile, err := cmd.Flags().GetString("template-plugin-config")
+	if err != nil {
+		return err
+	}
+
+	factories, err := plugin.Discover(cmd.Context(), dirs)
+	if err != nil {
+		return err
+	}
+	if configFile != "" {
+		cfg, err := plugin.LoadConfigurationFile(configFile)
+		if err != nil {
+			return fmt.Errorf("loading template plugin configuration: %v", err)
+		}
+		configured, err := cfg.Factories(cmd.Context())
+		if err != nil {
+			return err
+		}
+		factories = append(factories, configured...)
+	}
+
+	for _, f := range factories {
+		if tr.HasSchema(f.Schema()) || tr.HasType(f.Type()) {
+			return fmt.Errorf("template plugin %q: schema %q or type %q is already registered", f.Path(), f.Schema(), f.Type())
+		}
+		tr.Register(f)
+	}
+	return nil
+}
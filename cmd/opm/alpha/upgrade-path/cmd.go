@@ -0,0 +1,82 @@
+package upgradepath
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/cmd/opm/internal/util"
+)
+
+func NewCmd() *cobra.Command {
+	var (
+		upgradePath action.UpgradePath
+		output      string
+	)
+	cmd := &cobra.Command{
+		Use:   "upgrade-path <ref> <packageName> <fromBundle>",
+		Short: "Show the shortest upgrade path from a bundle to the channel head",
+		Long: `Show the shortest upgrade path from a bundle to the channel head.
+
+The upgrade graph of the channel is built from the replaces, skips, and
+skipRange fields of its entries. If no upgrade path exists, the command fails
+with an explanation of why the destination cannot be reached.
+
+The reference can be anything that "opm render" accepts.`,
+		Example: `
+#
+# Show how to upgrade from foo.v1.0.0 to the head of foo's default channel
+#
+$ opm alpha upgrade-path quay.io/example/catalog:latest foo foo.v1.0.0
+
+#
+# Show how to upgrade from foo.v1.0.0 to foo.v1.2.0 in the "fast" channel
+#
+$ opm alpha upgrade-path ./catalog foo foo.v1.0.0 --channel fast --to foo.v1.2.0
+`,
+		Args: cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			// The bundle loading impl is somewhat verbose, even on the happy path,
+			// so discard all logrus default logger logs. Any important failures will be
+			// returned from upgradePath.Run and logged as fatal errors.
+			logrus.SetOutput(io.Discard)
+
+			reg, err := util.CreateCLIRegistry(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() {
+				_ = reg.Destroy()
+			}()
+
+			upgradePath.Ref = args[0]
+			upgradePath.PackageName = args[1]
+			upgradePath.From = args[2]
+			upgradePath.Registry = reg
+			res, err := upgradePath.Run(cmd.Context())
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			switch output {
+			case "text":
+				err = res.WriteColumns(os.Stdout)
+			case "json":
+				err = res.WriteJSON(os.Stdout)
+			default:
+				log.Fatalf("invalid --output value %q, expected (text|json)", output)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&upgradePath.ChannelName, "channel", "c", "", "the channel in which to compute the upgrade path; default is the package's default channel")
+	cmd.Flags().StringVar(&upgradePath.To, "to", "", "the bundle to upgrade to; default is the channel head")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	return cmd
+}
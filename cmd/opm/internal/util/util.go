@@ -5,8 +5,11 @@ import (
 	"io"
 	"os"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/operator-framework/operator-registry/alpha/action"
 	"github.com/operator-framework/operator-registry/pkg/image"
 	"github.com/operator-framework/operator-registry/pkg/image/containersimageregistry"
 )
@@ -56,6 +59,40 @@ func CreateCLIRegistry(cmd *cobra.Command) (image.Registry, error) {
 	)
 }
 
+// AddBundleCacheFlags adds the flags that configure the bundle render cache
+// returned by CreateBundleCache.
+func AddBundleCacheFlags(flags *pflag.FlagSet) {
+	flags.String("render-cache-dir", "", "Directory to cache rendered bundles in, by image digest (default: renders in $OLM_CACHE_DIR, or in the user cache directory)")
+	flags.Bool("no-render-cache", false, "Render bundle images without reading or writing the render cache")
+}
+
+// CreateBundleCache returns the bundle render cache configured by the flags
+// added by AddBundleCacheFlags, or nil if the cache is disabled or no cache
+// directory is available, which is logged as a warning.
+func CreateBundleCache(cmd *cobra.Command) (*action.BundleCache, error) {
+	noCache, err := cmd.Flags().GetBool("no-render-cache")
+	if err != nil {
+		return nil, err
+	}
+	dir, err := cmd.Flags().GetString("render-cache-dir")
+	if err != nil {
+		return nil, err
+	}
+	if noCache {
+		if dir != "" {
+			return nil, errors.New("invalid flag combination: cannot use --render-cache-dir with --no-render-cache")
+		}
+		return nil, nil
+	}
+	if dir == "" {
+		if dir, err = action.DefaultBundleCacheDir(); err != nil {
+			logrus.WithError(err).Warn("no render cache directory is available, rendering bundles without the render cache")
+			return nil, nil
+		}
+	}
+	return action.NewBundleCache(dir), nil
+}
+
 func OpenFileOrStdin(cmd *cobra.Command, args []string) (io.ReadCloser, string, error) {
 	if len(args) == 0 || args[0] == "-" {
 		return io.NopCloser(cmd.InOrStdin()), "stdin", nil
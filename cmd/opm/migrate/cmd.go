@@ -16,10 +16,11 @@ func NewCmd() *cobra.Command {
 	var (
 		migrate      action.Migrate
 		migrateLevel string
+		targetLevel  string
 		output       string
 	)
 	cmd := &cobra.Command{
-		Use:   "migrate <indexRef> <outputDir>",
+		Use:   "migrate <indexRef> [<outputDir>]",
 		Short: "Migrate a sqlite-based index image or database file to a file-based catalog",
 		Long: `Migrate a sqlite-based index image or database file to a file-based catalog.
 
@@ -27,11 +28,22 @@ NOTE: the --output=json format produces streamable, concatenated JSON files.
 These are suitable to opm and jq, but may not be supported by arbitrary JSON
 parsers that assume that a file contains exactly one valid JSON object.
 
+With --target-level, the catalog is migrated to the given level, whatever
+its current level is: later migrations are reversed, if they can be, to
+produce a catalog for older clients.
+
+With --dry-run, the changes that the migrations make to each bundle and
+package are reported instead, and no output directory is needed.
+
 ` + sqlite.DeprecationMessage,
-		Args: cobra.ExactArgs(2),
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			migrate.CatalogRef = args[0]
-			migrate.OutputDir = args[1]
+			if len(args) == 2 {
+				migrate.OutputDir = args[1]
+			} else if !migrate.DryRun {
+				log.Fatal("an output directory is required, unless --dry-run is set")
+			}
 
 			switch output {
 			case "yaml":
@@ -44,24 +56,37 @@ parsers that assume that a file contains exactly one valid JSON object.
 				log.Fatalf("invalid --output value %q, expected (json|yaml)", output)
 			}
 
-			if migrateLevel != "" {
+			switch {
+			case migrateLevel != "" && targetLevel != "":
+				log.Fatal("--migrate-level and --target-level cannot be used together")
+			case migrateLevel != "":
 				m, err := migrations.NewMigrations(migrateLevel)
 				if err != nil {
 					log.Fatal(err)
 				}
 				migrate.Migrations = m
+			case targetLevel != "":
+				m, err := migrations.NewTargetMigrations(targetLevel)
+				if err != nil {
+					log.Fatal(err)
+				}
+				migrate.Migrations = m
 			}
 
 			logrus.Infof("rendering index %q as file-based catalog", migrate.CatalogRef)
 			if err := migrate.Run(cmd.Context()); err != nil {
 				logrus.New().Fatal(err)
 			}
-			logrus.Infof("wrote rendered file-based catalog to %q\n", migrate.OutputDir)
+			if !migrate.DryRun {
+				logrus.Infof("wrote rendered file-based catalog to %q\n", migrate.OutputDir)
+			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format (json|yaml)")
 	cmd.Flags().StringVar(&migrateLevel, "migrate-level", "", "Name of the last migration to run (default: none)\n"+migrations.HelpText())
+	cmd.Flags().StringVar(&targetLevel, "target-level", "", "Name of the migration level to migrate the catalog to, up or down; cannot be used with --migrate-level")
+	cmd.Flags().BoolVar(&migrate.DryRun, "dry-run", false, "Report the changes that the migrations make to the catalog, without writing it")
 
 	return cmd
 }
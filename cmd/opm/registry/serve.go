@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
 	"github.com/operator-framework/operator-registry/pkg/lib/dns"
 	"github.com/operator-framework/operator-registry/pkg/lib/log"
 	"github.com/operator-framework/operator-registry/pkg/lib/tmp"
@@ -155,6 +156,7 @@ func serveFunc(cmd *cobra.Command, _ []string) error {
 	}
 
 	api.RegisterRegistryServer(s, server.NewRegistryServer(store))
+	apiv2.RegisterCatalogServer(s, server.NewCatalogServer(store))
 	health.RegisterHealthServer(s, server.NewHealthServer())
 	reflection.Register(s)
 
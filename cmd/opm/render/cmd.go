@@ -61,6 +61,11 @@ database files.
 
 			render.Registry = reg
 
+			render.BundleCache, err = util.CreateBundleCache(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+
 			if imageRefTemplate != "" {
 				tmpl, err := template.New("image-ref-template").Parse(imageRefTemplate)
 				if err != nil {
@@ -96,6 +101,7 @@ database files.
 	cmd.Flags().StringVar(&migrateLevel, "migrate-level", "", "Name of the last migration to run (default: none)\n"+migrations.HelpText())
 	cmd.Flags().BoolVar(&oldMigrateAllFlag, "migrate", false, "Perform all available schema migrations on the rendered FBC")
 	cmd.MarkFlagsMutuallyExclusive("migrate", "migrate-level")
+	util.AddBundleCacheFlags(cmd.Flags())
 
 	// Alpha flags
 	cmd.Flags().StringVar(&imageRefTemplate, "alpha-image-ref-template", "", "When bundle image reference information is unavailable, populate it with this template")
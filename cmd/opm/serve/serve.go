@@ -3,13 +3,16 @@ package serve
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"net/http"
 	endpoint "net/http/pprof"
 	"os"
 	"runtime/pprof"
+	"slices"
 	"sync"
 	"time"
 
@@ -17,12 +20,15 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	health "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
 	"github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 	"github.com/operator-framework/operator-registry/pkg/lib/dns"
 	"github.com/operator-framework/operator-registry/pkg/lib/log"
 	"github.com/operator-framework/operator-registry/pkg/server"
@@ -34,9 +40,18 @@ type serve struct {
 	cacheOnly             bool
 	cacheEnforceIntegrity bool
 
+	watch         bool
+	watchInterval time.Duration
+
 	port           string
+	httpAddr       string
+	metricsAddr    string
 	terminationLog string
 
+	tlsCertFile     string
+	tlsKeyFile      string
+	tlsClientCAFile string
+
 	debug           bool
 	pprofAddr       string
 	captureProfiles bool
@@ -58,9 +73,43 @@ func NewCmd() *cobra.Command {
 		Short: "serve declarative configs",
 		Long: `This command serves declarative configs via a GRPC server.
 
-NOTE: The declarative config directory is loaded by the serve command at
-startup. Changes made to the declarative config after the this command starts
-will not be reflected in the served content.
+NOTE: By default, the declarative config directory is loaded by the serve
+command at startup. Changes made to the declarative config after the this
+command starts will not be reflected in the served content.
+
+With --watch, the declarative config directory is checked for changes every
+--watch-interval. When it changes, a new cache is built in the background and
+swapped in once it is ready; requests that are in flight complete against the
+previous content. If the new cache cannot be built, the previous content
+continues to be served until the directory changes again. Clients can follow
+these changes with the Watch call of the api.v2.Catalog GRPC service, which
+reports the digest of the served content and the packages that were added,
+updated or removed each time a new cache is swapped in. Reloaded caches are
+built in temporary directories, copying the packages that did not change from
+the served cache, and --cache-dir is not updated: when the server restarts, a
+cache in --cache-dir that is out of date is rebuilt, or fails the integrity
+check if --cache-enforce-integrity is set.
+
+With --http-addr, the declarative config is also served over HTTP as JSON
+Lines, for clients that do not use the GRPC API:
+
+  GET /api/v1/all                      the full catalog
+  GET /api/v1/packages/{name}          the objects of one package
+  GET /api/v1/metas?schema=&package=   objects filtered by schema and/or package
+
+HTTP responses support ETag/If-None-Match based on the content they serve,
+and gzip compression. The content served over HTTP is a second copy of the
+catalog, which is only stored in the cache when --http-addr is set. To serve
+a cache built with --cache-only over HTTP, also set --http-addr when building
+it. A cache in --cache-dir built without that content is rebuilt, or, if
+--cache-enforce-integrity is set, is served as is, and the HTTP endpoints
+respond with 503 Service Unavailable.
+
+With --tls-cert and --tls-key, the GRPC API and the HTTP declarative config
+endpoint are served over TLS. With --tls-client-ca, clients must also present
+a certificate signed by one of the CAs in that file. The certificate, key and
+client CA files are reloaded when they change, so they can be rotated without
+restarting the server.
 `,
 		Args: cobra.ExactArgs(1),
 		PreRun: func(_ *cobra.Command, args []string) {
@@ -82,11 +131,18 @@ will not be reflected in the served content.
 	cmd.Flags().BoolVar(&s.debug, "debug", false, "enable debug logging")
 	cmd.Flags().StringVarP(&s.terminationLog, "termination-log", "t", "/dev/termination-log", "path to a container termination log file")
 	cmd.Flags().StringVarP(&s.port, "port", "p", "50051", "port number to serve on")
+	cmd.Flags().StringVar(&s.metricsAddr, "metrics-addr", "", "if set, address on which to serve Prometheus metrics at /metrics (addr:port format)")
+	cmd.Flags().StringVar(&s.httpAddr, "http-addr", "", "if set, address on which to serve the declarative config over HTTP (addr:port format)")
+	cmd.Flags().StringVar(&s.tlsCertFile, "tls-cert", "", "if set, path to a PEM-encoded certificate to serve TLS with (requires --tls-key)")
+	cmd.Flags().StringVar(&s.tlsKeyFile, "tls-key", "", "if set, path to the PEM-encoded private key of --tls-cert")
+	cmd.Flags().StringVar(&s.tlsClientCAFile, "tls-client-ca", "", "if set, path to PEM-encoded CA certificates that client certificates must be signed by (mutual TLS)")
 	cmd.Flags().StringVar(&s.pprofAddr, "pprof-addr", "localhost:6060", "address of startup profiling endpoint (addr:port format)")
 	cmd.Flags().BoolVar(&s.captureProfiles, "pprof-capture-profiles", false, "capture pprof CPU profiles")
 	cmd.Flags().StringVar(&s.cacheDir, "cache-dir", "", "if set, sync and persist server cache directory")
 	cmd.Flags().BoolVar(&s.cacheOnly, "cache-only", false, "sync the serve cache and exit without serving")
 	cmd.Flags().BoolVar(&s.cacheEnforceIntegrity, "cache-enforce-integrity", false, "exit with error if cache is not present or has been invalidated. (default: true when --cache-dir is set and --cache-only is false, false otherwise), ")
+	cmd.Flags().BoolVar(&s.watch, "watch", false, "rebuild and serve the cache when the declarative config directory changes")
+	cmd.Flags().DurationVar(&s.watchInterval, "watch-interval", 10*time.Second, "how often to check the declarative config directory for changes when --watch is set")
 	return cmd
 }
 
@@ -116,6 +172,14 @@ func (s *serve) run(ctx context.Context) error {
 		mainLogger.WithError(err).Warn("unable to write default nsswitch config")
 	}
 
+	var tlsConfig *tls.Config
+	if s.tlsCertFile != "" || s.tlsKeyFile != "" || s.tlsClientCAFile != "" {
+		tlsConfig, err = certs.ServerTLSConfig(s.tlsCertFile, s.tlsKeyFile, s.tlsClientCAFile)
+		if err != nil {
+			return fmt.Errorf("could not configure TLS: %v", err)
+		}
+	}
+
 	if s.cacheDir == "" && s.cacheEnforceIntegrity {
 		return fmt.Errorf("--cache-dir must be specified with --cache-enforce-integrity")
 	}
@@ -132,11 +196,26 @@ func (s *serve) run(ctx context.Context) error {
 		"cache":   s.cacheDir,
 	})
 
-	store, err := cache.New(s.cacheDir, cache.WithLog(mainLogger))
+	var (
+		metrics   *server.Metrics
+		cacheOpts []cache.CacheOption
+	)
+	if s.metricsAddr != "" {
+		metrics = server.NewMetrics()
+		cacheOpts = append(cacheOpts, cache.WithObserver(metrics.ObserveCacheOperation))
+	}
+	if s.httpAddr != "" {
+		cacheOpts = append(cacheOpts, cache.WithDeclarativeConfig())
+	}
+
+	store, err := cache.New(s.cacheDir, append(cacheOpts, cache.WithLog(mainLogger))...)
 	if err != nil {
 		return err
 	}
-	defer store.Close()
+	// The reloadable owns the store, and closes whichever cache it is
+	// serving at shutdown.
+	reloadable := cache.NewReloadable(store)
+	defer reloadable.Close()
 	if s.cacheEnforceIntegrity {
 		if err := store.CheckIntegrity(ctx, os.DirFS(s.configDir)); err != nil {
 			return fmt.Errorf("integrity check failed: %v", err)
@@ -144,6 +223,9 @@ func (s *serve) run(ctx context.Context) error {
 		if err := store.Load(ctx); err != nil {
 			return fmt.Errorf("failed to load cache: %v", err)
 		}
+		if cache.MissesDeclarativeConfig(ctx, store) {
+			mainLogger.Warn("cache was built without declarative config content, it cannot be served over HTTP until the cache is rebuilt with --http-addr")
+		}
 	} else {
 		if err := cache.LoadOrRebuild(ctx, store, os.DirFS(s.configDir)); err != nil {
 			return fmt.Errorf("failed to load or rebuild cache: %v", err)
@@ -154,6 +236,45 @@ func (s *serve) run(ctx context.Context) error {
 		return nil
 	}
 
+	notifier, err := server.NewChangeNotifier(ctx, store)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog state: %v", err)
+	}
+	watchOpts := []cache.WatchOption{cache.WithOnReload(func(c cache.Cache) {
+		if err := notifier.Update(ctx, c); err != nil {
+			mainLogger.WithError(err).Warn("unable to notify watchers of reloaded cache")
+		}
+	})}
+	if metrics != nil {
+		setCatalogInfo := func(c cache.Cache) {
+			digest, err := c.Digest(ctx)
+			if err != nil {
+				mainLogger.WithError(err).Warn("unable to read cache digest for metrics")
+				return
+			}
+			metrics.SetCatalogInfo(digest, c.Format())
+		}
+		setCatalogInfo(store)
+		watchOpts = append(watchOpts, cache.WithOnReload(setCatalogInfo))
+
+		if err := metrics.ServeOn(ctx, s.metricsAddr, mainLogger); err != nil {
+			return err
+		}
+	}
+
+	if s.watch {
+		if s.watchInterval <= 0 {
+			return fmt.Errorf("--watch-interval must be positive")
+		}
+		watcher := cache.NewWatcher(reloadable, os.DirFS(s.configDir), buildReloadCache(mainLogger, cacheOpts...),
+			append(watchOpts,
+				cache.WithInterval(s.watchInterval),
+				cache.WithWatchLog(mainLogger),
+			)...,
+		)
+		go watcher.Run(ctx)
+	}
+
 	mainLogger = mainLogger.WithFields(logrus.Fields{"port": s.port})
 
 	lis, err := net.Listen("tcp", ":"+s.port)
@@ -161,13 +282,43 @@ func (s *serve) run(ctx context.Context) error {
 		return fmt.Errorf("failed to listen: %s", err)
 	}
 
+	var httpServer *http.Server
+	if s.httpAddr != "" {
+		httpLis, err := net.Listen("tcp", s.httpAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for HTTP: %s", err)
+		}
+		if tlsConfig != nil {
+			httpLis = tls.NewListener(httpLis, tlsConfig)
+		}
+		httpLogger := mainLogger.WithField("httpAddr", s.httpAddr)
+		httpServer = &http.Server{
+			Handler:           server.NewCatalogHandler(reloadable, server.WithCatalogHandlerLog(httpLogger)),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			httpLogger.Info("serving declarative config over HTTP")
+			if err := httpServer.Serve(httpLis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				httpLogger.WithError(err).Error("HTTP server failed")
+			}
+		}()
+	}
+
 	streamLogger, unaryLogger := loggingInterceptors(s.logger.Dup())
-	grpcServer := grpc.NewServer(
+	serverOpts := []grpc.ServerOption{
 		grpc.ChainStreamInterceptor(streamLogger),
 		grpc.ChainUnaryInterceptor(unaryLogger),
-	)
-	api.RegisterRegistryServer(grpcServer, server.NewRegistryServer(store))
-	api.RegisterExperimentalRegistryServer(grpcServer, server.NewExperimentalRegistryServer(store))
+	}
+	if metrics != nil {
+		serverOpts = append(serverOpts, metrics.ServerOptions()...)
+	}
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	api.RegisterRegistryServer(grpcServer, server.NewRegistryServer(reloadable))
+	api.RegisterExperimentalRegistryServer(grpcServer, server.NewExperimentalRegistryServer(reloadable))
+	apiv2.RegisterCatalogServer(grpcServer, server.NewCatalogServer(reloadable, server.WithChangeNotifier(notifier)))
 	health.RegisterHealthServer(grpcServer, server.NewHealthServer())
 	reflection.Register(grpcServer)
 	mainLogger.Info("serving registry")
@@ -177,6 +328,13 @@ func (s *serve) run(ctx context.Context) error {
 		<-ctx.Done()
 		mainLogger.Info("shutting down server")
 		grpcServer.GracefulStop()
+		if httpServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := httpServer.Shutdown(shutdownCtx); err != nil {
+				mainLogger.Warnf("error shutting down HTTP server: %v", err)
+			}
+		}
 		if err := p.stopEndpoint(ctx); err != nil {
 			mainLogger.Warnf("error shutting down pprof server: %v", err)
 		}
@@ -185,6 +343,19 @@ func (s *serve) run(ctx context.Context) error {
 	return grpcServer.Serve(lis)
 }
 
+// buildReloadCache returns a cache.BuildFunc that builds each reloaded cache
+// in its own temporary directory. The directory of the cache being served
+// may still be in use by in-flight requests, so it is never rebuilt in
+// place. Instead, the new cache is seeded from the served cache, so that
+// only the packages that changed are rebuilt; the bundles of the other
+// packages are copied from the served cache. The reloaded caches are never
+// written back to --cache-dir.
+func buildReloadCache(logger *logrus.Entry, opts ...cache.CacheOption) cache.BuildFunc {
+	return func(ctx context.Context, fbc fs.FS, current cache.Cache) (cache.Cache, error) {
+		return cache.BuildTempDirCache(ctx, fbc, "opm-serve-cache-", append(slices.Clone(opts), cache.WithLog(logger), cache.WithSeed(current))...)
+	}
+}
+
 // manages an HTTP pprof endpoint served by `server`,
 // including default pprof handlers and custom cpu pprof cache stored in `cache`.
 // the cache is intended to sample CPU activity for a period and serve the data
@@ -4,23 +4,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 
+	"github.com/operator-framework/operator-registry/alpha/lint"
 	"github.com/operator-framework/operator-registry/pkg/lib/config"
 )
 
 const (
-	outputJSON = "json"
-	outputYAML = "yaml"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputSARIF = "sarif"
 )
 
 // ValidationResult represents the structured output of validation
 type ValidationResult struct {
-	Passed bool             `json:"passed" yaml:"passed"`
-	Error  *ValidationError `json:"error,omitempty" yaml:"error,omitempty"`
+	Passed   bool             `json:"passed" yaml:"passed"`
+	Error    *ValidationError `json:"error,omitempty" yaml:"error,omitempty"`
+	Findings []lint.Finding   `json:"findings,omitempty" yaml:"findings,omitempty"`
 }
 
 // ValidationError represents a structured validation error
@@ -43,13 +47,29 @@ func errorToValidationError(err error) *ValidationError {
 
 func NewCmd() *cobra.Command {
 	logger := logrus.New()
-	var output string
+	var (
+		output         string
+		lintConfigPath string
+	)
 
 	validate := &cobra.Command{
 		Use:   "validate <directory>",
 		Short: "Validate the declarative index config",
-		Long:  "Validate the declarative config JSON file(s) in a given directory",
-		Args:  cobra.ExactArgs(1),
+		Long: `Validate the declarative config JSON file(s) in a given directory.
+
+The config is checked by a set of lint rules, each with a stable ID and a
+severity of error, warning, or info. Validation fails if any rule reports a
+finding with error severity. Findings with info severity are only reported by
+the structured output formats. Rules can be disabled, or their severity
+changed, with a lint configuration file:
+
+  rules:
+    package-icon-missing:
+      enabled: false
+    default-channel-deprecated:
+      severity: error
+`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			directory := args[0]
 			s, err := os.Stat(directory)
@@ -60,34 +80,73 @@ func NewCmd() *cobra.Command {
 				return fmt.Errorf("%q is not a directory", directory)
 			}
 
+			switch output {
+			case "", outputJSON, outputYAML, outputSARIF:
+			default:
+				return fmt.Errorf("invalid --output value %q, expected (json|yaml|sarif)", output)
+			}
+
+			var lintConfig *lint.Config
+			if lintConfigPath != "" {
+				f, err := os.Open(lintConfigPath)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				if lintConfig, err = lint.LoadConfig(f); err != nil {
+					return err
+				}
+			}
+
 			// Perform validation
-			validationErr := config.Validate(c.Context(), os.DirFS(directory))
+			result, validationErr := config.Lint(c.Context(), os.DirFS(directory), lint.NewLinter(), lintConfig)
+			if validationErr == nil {
+				validationErr = result.Err()
+				// Report files relative to the working directory rather
+				// than to the catalog directory.
+				for i, f := range result.Findings {
+					if f.File != "" {
+						result.Findings[i].File = filepath.Join(directory, f.File)
+					}
+				}
+			}
 
 			// Handle structured output
 			if output != "" {
-				result := ValidationResult{
-					Passed: validationErr == nil,
-					Error:  errorToValidationError(validationErr),
-				}
-
 				var data []byte
 				var marshalErr error
 
 				switch output {
-				case outputJSON:
-					data, marshalErr = json.MarshalIndent(result, "", "  ")
-				case outputYAML:
-					data, marshalErr = yaml.Marshal(result)
-				default:
-					return fmt.Errorf("invalid --output value %q, expected (json|yaml)", output)
+				case outputSARIF:
+					if result == nil {
+						// The config could not be loaded, so there are
+						// no findings to report.
+						return validationErr
+					}
+					marshalErr = result.WriteSARIF(os.Stdout)
+				case outputJSON, outputYAML:
+					vr := ValidationResult{
+						Passed: validationErr == nil,
+						Error:  errorToValidationError(validationErr),
+					}
+					if result != nil {
+						vr.Findings = result.Findings
+					}
+					if output == outputJSON {
+						data, marshalErr = json.MarshalIndent(vr, "", "  ")
+					} else {
+						data, marshalErr = yaml.Marshal(vr)
+					}
 				}
 
 				if marshalErr != nil {
 					return fmt.Errorf("failed to marshal output: %w", marshalErr)
 				}
 
-				if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
-					return fmt.Errorf("failed to write output: %w", err)
+				if data != nil {
+					if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+						return fmt.Errorf("failed to write output: %w", err)
+					}
 				}
 
 				// Silence cobra error output only for validation errors
@@ -100,7 +159,14 @@ func NewCmd() *cobra.Command {
 				return validationErr
 			}
 
-			// Default behavior: use logger.Fatal on error
+			// Default behavior: log warnings and use logger.Fatal on error
+			if result != nil {
+				for _, f := range result.Findings {
+					if f.Severity == lint.SeverityWarning {
+						logger.Warn(f.String())
+					}
+				}
+			}
 			if validationErr != nil {
 				logger.Fatal(validationErr)
 			}
@@ -108,7 +174,8 @@ func NewCmd() *cobra.Command {
 		},
 	}
 
-	validate.Flags().StringVarP(&output, "output", "o", "", "Output format for validation results (json|yaml)")
+	validate.Flags().StringVarP(&output, "output", "o", "", "Output format for validation results (json|yaml|sarif)")
+	validate.Flags().StringVar(&lintConfigPath, "lint-config", "", "Path to a YAML or JSON file that enables, disables, or changes the severity of lint rules")
 
 	return validate
 }
@@ -10,10 +10,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	health "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 	"github.com/operator-framework/operator-registry/pkg/lib/dns"
 	"github.com/operator-framework/operator-registry/pkg/lib/log"
 	"github.com/operator-framework/operator-registry/pkg/lib/tmp"
@@ -43,6 +46,10 @@ func init() {
 	rootCmd.Flags().Bool("debug", false, "enable debug logging")
 	rootCmd.Flags().StringP("database", "d", "bundles.db", "relative path to sqlite db")
 	rootCmd.Flags().StringP("port", "p", "50051", "port number to serve on")
+	rootCmd.Flags().String("metrics-addr", "", "if set, address on which to serve Prometheus metrics at /metrics (addr:port format)")
+	rootCmd.Flags().String("tls-cert", "", "if set, path to a PEM-encoded certificate to serve TLS with (requires --tls-key)")
+	rootCmd.Flags().String("tls-key", "", "if set, path to the PEM-encoded private key of --tls-cert")
+	rootCmd.Flags().String("tls-client-ca", "", "if set, path to PEM-encoded CA certificates that client certificates must be signed by (mutual TLS)")
 	rootCmd.Flags().StringP("termination-log", "t", "/dev/termination-log", "path to a container termination log file")
 	rootCmd.Flags().Bool("skip-migrate", false, "do  not attempt to migrate to the latest db revision when starting")
 	if err := rootCmd.Flags().MarkHidden("debug"); err != nil {
@@ -125,9 +132,41 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		logger.Fatalf("failed to listen: %s", err)
 	}
-	s := grpc.NewServer()
+	metricsAddr, err := cmd.Flags().GetString("metrics-addr")
+	if err != nil {
+		return err
+	}
+	var serverOpts []grpc.ServerOption
+	if metricsAddr != "" {
+		metrics := server.NewMetrics()
+		serverOpts = append(serverOpts, metrics.ServerOptions()...)
+		if err := metrics.ServeOn(ctx, metricsAddr, logger); err != nil {
+			logger.Fatal(err)
+		}
+	}
+	tlsCertFile, err := cmd.Flags().GetString("tls-cert")
+	if err != nil {
+		return err
+	}
+	tlsKeyFile, err := cmd.Flags().GetString("tls-key")
+	if err != nil {
+		return err
+	}
+	tlsClientCAFile, err := cmd.Flags().GetString("tls-client-ca")
+	if err != nil {
+		return err
+	}
+	if tlsCertFile != "" || tlsKeyFile != "" || tlsClientCAFile != "" {
+		tlsConfig, err := certs.ServerTLSConfig(tlsCertFile, tlsKeyFile, tlsClientCAFile)
+		if err != nil {
+			return fmt.Errorf("could not configure TLS: %v", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s := grpc.NewServer(serverOpts...)
 
 	api.RegisterRegistryServer(s, server.NewRegistryServer(store))
+	apiv2.RegisterCatalogServer(s, server.NewCatalogServer(store))
 	health.RegisterHealthServer(s, server.NewHealthServer())
 	reflection.Register(s)
 
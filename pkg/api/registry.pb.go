@@ -9,6 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
@@ -627,7 +628,16 @@ func (*ListPackageRequest) Descriptor() ([]byte, []int) {
 }
 
 type ListBundlesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pkgName, if set, limits the response to bundles of the package.
+	PkgName string `protobuf:"bytes,1,opt,name=pkgName,proto3" json:"pkgName,omitempty"`
+	// channelName, if set, limits the response to bundles of the channel.
+	ChannelName string `protobuf:"bytes,2,opt,name=channelName,proto3" json:"channelName,omitempty"`
+	// excludeDeprecated omits bundles that are deprecated.
+	ExcludeDeprecated bool `protobuf:"varint,3,opt,name=excludeDeprecated,proto3" json:"excludeDeprecated,omitempty"`
+	// fieldMask, if set, limits the fields of the returned bundles to the
+	// given paths.
+	FieldMask     *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=fieldMask,proto3" json:"fieldMask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -662,6 +672,34 @@ func (*ListBundlesRequest) Descriptor() ([]byte, []int) {
 	return file_registry_proto_rawDescGZIP(), []int{9}
 }
 
+func (x *ListBundlesRequest) GetPkgName() string {
+	if x != nil {
+		return x.PkgName
+	}
+	return ""
+}
+
+func (x *ListBundlesRequest) GetChannelName() string {
+	if x != nil {
+		return x.ChannelName
+	}
+	return ""
+}
+
+func (x *ListBundlesRequest) GetExcludeDeprecated() bool {
+	if x != nil {
+		return x.ExcludeDeprecated
+	}
+	return false
+}
+
+func (x *ListBundlesRequest) GetFieldMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
 type GetPackageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -1226,7 +1264,7 @@ var File_registry_proto protoreflect.FileDescriptor
 
 const file_registry_proto_rawDesc = "" +
 	"\n" +
-	"\x0eregistry.proto\x12\x03api\x1a\x1cgoogle/protobuf/struct.proto\"k\n" +
+	"\x0eregistry.proto\x12\x03api\x1a google/protobuf/field_mask.proto\x1a\x1cgoogle/protobuf/struct.proto\"k\n" +
 	"\aChannel\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\acsvName\x18\x02 \x01(\tR\acsvName\x122\n" +
@@ -1278,8 +1316,12 @@ const file_registry_proto_rawDesc = "" +
 	"bundleName\x18\x03 \x01(\tR\n" +
 	"bundleName\x12\x1a\n" +
 	"\breplaces\x18\x04 \x01(\tR\breplaces\"\x14\n" +
-	"\x12ListPackageRequest\"\x14\n" +
-	"\x12ListBundlesRequest\"'\n" +
+	"\x12ListPackageRequest\"\xb8\x01\n" +
+	"\x12ListBundlesRequest\x12\x18\n" +
+	"\apkgName\x18\x01 \x01(\tR\apkgName\x12 \n" +
+	"\vchannelName\x18\x02 \x01(\tR\vchannelName\x12,\n" +
+	"\x11excludeDeprecated\x18\x03 \x01(\bR\x11excludeDeprecated\x128\n" +
+	"\tfieldMask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\tfieldMask\"'\n" +
 	"\x11GetPackageRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"h\n" +
 	"\x10GetBundleRequest\x12\x18\n" +
@@ -1364,7 +1406,8 @@ var file_registry_proto_goTypes = []any{
 	(*GetDefaultProviderRequest)(nil),                   // 17: api.GetDefaultProviderRequest
 	(*Deprecation)(nil),                                 // 18: api.Deprecation
 	(*ExperimentalListPackageCustomSchemasRequest)(nil), // 19: api.ExperimentalListPackageCustomSchemasRequest
-	(*structpb.Struct)(nil),                             // 20: google.protobuf.Struct
+	(*fieldmaskpb.FieldMask)(nil),                       // 20: google.protobuf.FieldMask
+	(*structpb.Struct)(nil),                             // 21: google.protobuf.Struct
 }
 var file_registry_proto_depIdxs = []int32{
 	18, // 0: api.Channel.deprecation:type_name -> api.Deprecation
@@ -1375,33 +1418,34 @@ var file_registry_proto_depIdxs = []int32{
 	4,  // 5: api.Bundle.dependencies:type_name -> api.Dependency
 	5,  // 6: api.Bundle.properties:type_name -> api.Property
 	18, // 7: api.Bundle.deprecation:type_name -> api.Deprecation
-	8,  // 8: api.Registry.ListPackages:input_type -> api.ListPackageRequest
-	10, // 9: api.Registry.GetPackage:input_type -> api.GetPackageRequest
-	11, // 10: api.Registry.GetBundle:input_type -> api.GetBundleRequest
-	12, // 11: api.Registry.GetBundleForChannel:input_type -> api.GetBundleInChannelRequest
-	13, // 12: api.Registry.GetChannelEntriesThatReplace:input_type -> api.GetAllReplacementsRequest
-	14, // 13: api.Registry.GetBundleThatReplaces:input_type -> api.GetReplacementRequest
-	15, // 14: api.Registry.GetChannelEntriesThatProvide:input_type -> api.GetAllProvidersRequest
-	16, // 15: api.Registry.GetLatestChannelEntriesThatProvide:input_type -> api.GetLatestProvidersRequest
-	17, // 16: api.Registry.GetDefaultBundleThatProvides:input_type -> api.GetDefaultProviderRequest
-	9,  // 17: api.Registry.ListBundles:input_type -> api.ListBundlesRequest
-	19, // 18: api.ExperimentalRegistry.ExperimentalListPackageCustomSchemas:input_type -> api.ExperimentalListPackageCustomSchemasRequest
-	1,  // 19: api.Registry.ListPackages:output_type -> api.PackageName
-	2,  // 20: api.Registry.GetPackage:output_type -> api.Package
-	6,  // 21: api.Registry.GetBundle:output_type -> api.Bundle
-	6,  // 22: api.Registry.GetBundleForChannel:output_type -> api.Bundle
-	7,  // 23: api.Registry.GetChannelEntriesThatReplace:output_type -> api.ChannelEntry
-	6,  // 24: api.Registry.GetBundleThatReplaces:output_type -> api.Bundle
-	7,  // 25: api.Registry.GetChannelEntriesThatProvide:output_type -> api.ChannelEntry
-	7,  // 26: api.Registry.GetLatestChannelEntriesThatProvide:output_type -> api.ChannelEntry
-	6,  // 27: api.Registry.GetDefaultBundleThatProvides:output_type -> api.Bundle
-	6,  // 28: api.Registry.ListBundles:output_type -> api.Bundle
-	20, // 29: api.ExperimentalRegistry.ExperimentalListPackageCustomSchemas:output_type -> google.protobuf.Struct
-	19, // [19:30] is the sub-list for method output_type
-	8,  // [8:19] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	20, // 8: api.ListBundlesRequest.fieldMask:type_name -> google.protobuf.FieldMask
+	8,  // 9: api.Registry.ListPackages:input_type -> api.ListPackageRequest
+	10, // 10: api.Registry.GetPackage:input_type -> api.GetPackageRequest
+	11, // 11: api.Registry.GetBundle:input_type -> api.GetBundleRequest
+	12, // 12: api.Registry.GetBundleForChannel:input_type -> api.GetBundleInChannelRequest
+	13, // 13: api.Registry.GetChannelEntriesThatReplace:input_type -> api.GetAllReplacementsRequest
+	14, // 14: api.Registry.GetBundleThatReplaces:input_type -> api.GetReplacementRequest
+	15, // 15: api.Registry.GetChannelEntriesThatProvide:input_type -> api.GetAllProvidersRequest
+	16, // 16: api.Registry.GetLatestChannelEntriesThatProvide:input_type -> api.GetLatestProvidersRequest
+	17, // 17: api.Registry.GetDefaultBundleThatProvides:input_type -> api.GetDefaultProviderRequest
+	9,  // 18: api.Registry.ListBundles:input_type -> api.ListBundlesRequest
+	19, // 19: api.ExperimentalRegistry.ExperimentalListPackageCustomSchemas:input_type -> api.ExperimentalListPackageCustomSchemasRequest
+	1,  // 20: api.Registry.ListPackages:output_type -> api.PackageName
+	2,  // 21: api.Registry.GetPackage:output_type -> api.Package
+	6,  // 22: api.Registry.GetBundle:output_type -> api.Bundle
+	6,  // 23: api.Registry.GetBundleForChannel:output_type -> api.Bundle
+	7,  // 24: api.Registry.GetChannelEntriesThatReplace:output_type -> api.ChannelEntry
+	6,  // 25: api.Registry.GetBundleThatReplaces:output_type -> api.Bundle
+	7,  // 26: api.Registry.GetChannelEntriesThatProvide:output_type -> api.ChannelEntry
+	7,  // 27: api.Registry.GetLatestChannelEntriesThatProvide:output_type -> api.ChannelEntry
+	6,  // 28: api.Registry.GetDefaultBundleThatProvides:output_type -> api.Bundle
+	6,  // 29: api.Registry.ListBundles:output_type -> api.Bundle
+	21, // 30: api.ExperimentalRegistry.ExperimentalListPackageCustomSchemas:output_type -> google.protobuf.Struct
+	20, // [20:31] is the sub-list for method output_type
+	9,  // [9:20] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_registry_proto_init() }
@@ -0,0 +1,932 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.27.0
+// source: v2/catalog.proto
+
+package v2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListPackagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPackagesRequest) Reset() {
+	*x = ListPackagesRequest{}
+	mi := &file_v2_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPackagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPackagesRequest) ProtoMessage() {}
+
+func (x *ListPackagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPackagesRequest.ProtoReflect.Descriptor instead.
+func (*ListPackagesRequest) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+type Package struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description        string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Icon               *Icon                  `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	DefaultChannelName string                 `protobuf:"bytes,4,opt,name=defaultChannelName,proto3" json:"defaultChannelName,omitempty"`
+	ChannelNames       []string               `protobuf:"bytes,5,rep,name=channelNames,proto3" json:"channelNames,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Package) Reset() {
+	*x = Package{}
+	mi := &file_v2_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Package) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Package) ProtoMessage() {}
+
+func (x *Package) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Package.ProtoReflect.Descriptor instead.
+func (*Package) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Package) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Package) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Package) GetIcon() *Icon {
+	if x != nil {
+		return x.Icon
+	}
+	return nil
+}
+
+func (x *Package) GetDefaultChannelName() string {
+	if x != nil {
+		return x.DefaultChannelName
+	}
+	return ""
+}
+
+func (x *Package) GetChannelNames() []string {
+	if x != nil {
+		return x.ChannelNames
+	}
+	return nil
+}
+
+type Icon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	MediaType     string                 `protobuf:"bytes,2,opt,name=mediaType,proto3" json:"mediaType,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Icon) Reset() {
+	*x = Icon{}
+	mi := &file_v2_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Icon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Icon) ProtoMessage() {}
+
+func (x *Icon) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Icon.ProtoReflect.Descriptor instead.
+func (*Icon) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Icon) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Icon) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+type ListChannelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PackageName   string                 `protobuf:"bytes,1,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChannelsRequest) Reset() {
+	*x = ListChannelsRequest{}
+	mi := &file_v2_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChannelsRequest) ProtoMessage() {}
+
+func (x *ListChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChannelsRequest.ProtoReflect.Descriptor instead.
+func (*ListChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListChannelsRequest) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+type Channel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PackageName   string                 `protobuf:"bytes,1,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Entries       []*ChannelEntry        `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Channel) Reset() {
+	*x = Channel{}
+	mi := &file_v2_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Channel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Channel) ProtoMessage() {}
+
+func (x *Channel) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Channel.ProtoReflect.Descriptor instead.
+func (*Channel) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Channel) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *Channel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Channel) GetEntries() []*ChannelEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ChannelEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Replaces      string                 `protobuf:"bytes,2,opt,name=replaces,proto3" json:"replaces,omitempty"`
+	Skips         []string               `protobuf:"bytes,3,rep,name=skips,proto3" json:"skips,omitempty"`
+	SkipRange     string                 `protobuf:"bytes,4,opt,name=skipRange,proto3" json:"skipRange,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelEntry) Reset() {
+	*x = ChannelEntry{}
+	mi := &file_v2_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelEntry) ProtoMessage() {}
+
+func (x *ChannelEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelEntry.ProtoReflect.Descriptor instead.
+func (*ChannelEntry) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChannelEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChannelEntry) GetReplaces() string {
+	if x != nil {
+		return x.Replaces
+	}
+	return ""
+}
+
+func (x *ChannelEntry) GetSkips() []string {
+	if x != nil {
+		return x.Skips
+	}
+	return nil
+}
+
+func (x *ChannelEntry) GetSkipRange() string {
+	if x != nil {
+		return x.SkipRange
+	}
+	return ""
+}
+
+type GetDeprecationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PackageName   string                 `protobuf:"bytes,1,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeprecationsRequest) Reset() {
+	*x = GetDeprecationsRequest{}
+	mi := &file_v2_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeprecationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeprecationsRequest) ProtoMessage() {}
+
+func (x *GetDeprecationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeprecationsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeprecationsRequest) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetDeprecationsRequest) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+type Deprecations struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PackageName   string                 `protobuf:"bytes,1,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	Entries       []*DeprecationEntry    `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deprecations) Reset() {
+	*x = Deprecations{}
+	mi := &file_v2_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deprecations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deprecations) ProtoMessage() {}
+
+func (x *Deprecations) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deprecations.ProtoReflect.Descriptor instead.
+func (*Deprecations) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Deprecations) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *Deprecations) GetEntries() []*DeprecationEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type DeprecationEntry struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Reference     *PackageScopedReference `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	Message       string                  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeprecationEntry) Reset() {
+	*x = DeprecationEntry{}
+	mi := &file_v2_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeprecationEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeprecationEntry) ProtoMessage() {}
+
+func (x *DeprecationEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeprecationEntry.ProtoReflect.Descriptor instead.
+func (*DeprecationEntry) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeprecationEntry) GetReference() *PackageScopedReference {
+	if x != nil {
+		return x.Reference
+	}
+	return nil
+}
+
+func (x *DeprecationEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// PackageScopedReference refers to the package itself when its schema is
+// olm.package, and otherwise to the named olm.channel or olm.bundle.
+type PackageScopedReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schema        string                 `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackageScopedReference) Reset() {
+	*x = PackageScopedReference{}
+	mi := &file_v2_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackageScopedReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageScopedReference) ProtoMessage() {}
+
+func (x *PackageScopedReference) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageScopedReference.ProtoReflect.Descriptor instead.
+func (*PackageScopedReference) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PackageScopedReference) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *PackageScopedReference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListCustomSchemaBlobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schema        string                 `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	PackageName   string                 `protobuf:"bytes,2,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomSchemaBlobsRequest) Reset() {
+	*x = ListCustomSchemaBlobsRequest{}
+	mi := &file_v2_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomSchemaBlobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomSchemaBlobsRequest) ProtoMessage() {}
+
+func (x *ListCustomSchemaBlobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomSchemaBlobsRequest.ProtoReflect.Descriptor instead.
+func (*ListCustomSchemaBlobsRequest) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListCustomSchemaBlobsRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *ListCustomSchemaBlobsRequest) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *ListCustomSchemaBlobsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CustomSchemaBlob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schema        string                 `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	PackageName   string                 `protobuf:"bytes,2,opt,name=packageName,proto3" json:"packageName,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Blob          *structpb.Struct       `protobuf:"bytes,4,opt,name=blob,proto3" json:"blob,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomSchemaBlob) Reset() {
+	*x = CustomSchemaBlob{}
+	mi := &file_v2_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomSchemaBlob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomSchemaBlob) ProtoMessage() {}
+
+func (x *CustomSchemaBlob) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomSchemaBlob.ProtoReflect.Descriptor instead.
+func (*CustomSchemaBlob) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CustomSchemaBlob) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *CustomSchemaBlob) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *CustomSchemaBlob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CustomSchemaBlob) GetBlob() *structpb.Struct {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_v2_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+// WatchEvent describes the served catalog after a change. The first event of
+// a Watch call only has the digest of the catalog being served.
+type WatchEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Digest          string                 `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+	AddedPackages   []string               `protobuf:"bytes,2,rep,name=addedPackages,proto3" json:"addedPackages,omitempty"`
+	UpdatedPackages []string               `protobuf:"bytes,3,rep,name=updatedPackages,proto3" json:"updatedPackages,omitempty"`
+	RemovedPackages []string               `protobuf:"bytes,4,rep,name=removedPackages,proto3" json:"removedPackages,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_v2_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_v2_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchEvent) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetAddedPackages() []string {
+	if x != nil {
+		return x.AddedPackages
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetUpdatedPackages() []string {
+	if x != nil {
+		return x.UpdatedPackages
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetRemovedPackages() []string {
+	if x != nil {
+		return x.RemovedPackages
+	}
+	return nil
+}
+
+var File_v2_catalog_proto protoreflect.FileDescriptor
+
+const file_v2_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\x10v2/catalog.proto\x12\x06api.v2\x1a\x1cgoogle/protobuf/struct.proto\"\x15\n" +
+	"\x13ListPackagesRequest\"\xb5\x01\n" +
+	"\aPackage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12 \n" +
+	"\x04icon\x18\x03 \x01(\v2\f.api.v2.IconR\x04icon\x12.\n" +
+	"\x12defaultChannelName\x18\x04 \x01(\tR\x12defaultChannelName\x12\"\n" +
+	"\fchannelNames\x18\x05 \x03(\tR\fchannelNames\"8\n" +
+	"\x04Icon\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1c\n" +
+	"\tmediaType\x18\x02 \x01(\tR\tmediaType\"7\n" +
+	"\x13ListChannelsRequest\x12 \n" +
+	"\vpackageName\x18\x01 \x01(\tR\vpackageName\"o\n" +
+	"\aChannel\x12 \n" +
+	"\vpackageName\x18\x01 \x01(\tR\vpackageName\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\aentries\x18\x03 \x03(\v2\x14.api.v2.ChannelEntryR\aentries\"r\n" +
+	"\fChannelEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\breplaces\x18\x02 \x01(\tR\breplaces\x12\x14\n" +
+	"\x05skips\x18\x03 \x03(\tR\x05skips\x12\x1c\n" +
+	"\tskipRange\x18\x04 \x01(\tR\tskipRange\":\n" +
+	"\x16GetDeprecationsRequest\x12 \n" +
+	"\vpackageName\x18\x01 \x01(\tR\vpackageName\"d\n" +
+	"\fDeprecations\x12 \n" +
+	"\vpackageName\x18\x01 \x01(\tR\vpackageName\x122\n" +
+	"\aentries\x18\x02 \x03(\v2\x18.api.v2.DeprecationEntryR\aentries\"j\n" +
+	"\x10DeprecationEntry\x12<\n" +
+	"\treference\x18\x01 \x01(\v2\x1e.api.v2.PackageScopedReferenceR\treference\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"D\n" +
+	"\x16PackageScopedReference\x12\x16\n" +
+	"\x06schema\x18\x01 \x01(\tR\x06schema\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"l\n" +
+	"\x1cListCustomSchemaBlobsRequest\x12\x16\n" +
+	"\x06schema\x18\x01 \x01(\tR\x06schema\x12 \n" +
+	"\vpackageName\x18\x02 \x01(\tR\vpackageName\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\x8d\x01\n" +
+	"\x10CustomSchemaBlob\x12\x16\n" +
+	"\x06schema\x18\x01 \x01(\tR\x06schema\x12 \n" +
+	"\vpackageName\x18\x02 \x01(\tR\vpackageName\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12+\n" +
+	"\x04blob\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04blob\"\x0e\n" +
+	"\fWatchRequest\"\x9e\x01\n" +
+	"\n" +
+	"WatchEvent\x12\x16\n" +
+	"\x06digest\x18\x01 \x01(\tR\x06digest\x12$\n" +
+	"\raddedPackages\x18\x02 \x03(\tR\raddedPackages\x12(\n" +
+	"\x0fupdatedPackages\x18\x03 \x03(\tR\x0fupdatedPackages\x12(\n" +
+	"\x0fremovedPackages\x18\x04 \x03(\tR\x0fremovedPackages2\xec\x02\n" +
+	"\aCatalog\x12@\n" +
+	"\fListPackages\x12\x1b.api.v2.ListPackagesRequest\x1a\x0f.api.v2.Package\"\x000\x01\x12@\n" +
+	"\fListChannels\x12\x1b.api.v2.ListChannelsRequest\x1a\x0f.api.v2.Channel\"\x000\x01\x12I\n" +
+	"\x0fGetDeprecations\x12\x1e.api.v2.GetDeprecationsRequest\x1a\x14.api.v2.Deprecations\"\x00\x12[\n" +
+	"\x15ListCustomSchemaBlobs\x12$.api.v2.ListCustomSchemaBlobsRequest\x1a\x18.api.v2.CustomSchemaBlob\"\x000\x01\x125\n" +
+	"\x05Watch\x12\x14.api.v2.WatchRequest\x1a\x12.api.v2.WatchEvent\"\x000\x01B\x06Z\x04.;v2b\x06proto3"
+
+var (
+	file_v2_catalog_proto_rawDescOnce sync.Once
+	file_v2_catalog_proto_rawDescData []byte
+)
+
+func file_v2_catalog_proto_rawDescGZIP() []byte {
+	file_v2_catalog_proto_rawDescOnce.Do(func() {
+		file_v2_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_v2_catalog_proto_rawDesc), len(file_v2_catalog_proto_rawDesc)))
+	})
+	return file_v2_catalog_proto_rawDescData
+}
+
+var file_v2_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_v2_catalog_proto_goTypes = []any{
+	(*ListPackagesRequest)(nil),          // 0: api.v2.ListPackagesRequest
+	(*Package)(nil),                      // 1: api.v2.Package
+	(*Icon)(nil),                         // 2: api.v2.Icon
+	(*ListChannelsRequest)(nil),          // 3: api.v2.ListChannelsRequest
+	(*Channel)(nil),                      // 4: api.v2.Channel
+	(*ChannelEntry)(nil),                 // 5: api.v2.ChannelEntry
+	(*GetDeprecationsRequest)(nil),       // 6: api.v2.GetDeprecationsRequest
+	(*Deprecations)(nil),                 // 7: api.v2.Deprecations
+	(*DeprecationEntry)(nil),             // 8: api.v2.DeprecationEntry
+	(*PackageScopedReference)(nil),       // 9: api.v2.PackageScopedReference
+	(*ListCustomSchemaBlobsRequest)(nil), // 10: api.v2.ListCustomSchemaBlobsRequest
+	(*CustomSchemaBlob)(nil),             // 11: api.v2.CustomSchemaBlob
+	(*WatchRequest)(nil),                 // 12: api.v2.WatchRequest
+	(*WatchEvent)(nil),                   // 13: api.v2.WatchEvent
+	(*structpb.Struct)(nil),              // 14: google.protobuf.Struct
+}
+var file_v2_catalog_proto_depIdxs = []int32{
+	2,  // 0: api.v2.Package.icon:type_name -> api.v2.Icon
+	5,  // 1: api.v2.Channel.entries:type_name -> api.v2.ChannelEntry
+	8,  // 2: api.v2.Deprecations.entries:type_name -> api.v2.DeprecationEntry
+	9,  // 3: api.v2.DeprecationEntry.reference:type_name -> api.v2.PackageScopedReference
+	14, // 4: api.v2.CustomSchemaBlob.blob:type_name -> google.protobuf.Struct
+	0,  // 5: api.v2.Catalog.ListPackages:input_type -> api.v2.ListPackagesRequest
+	3,  // 6: api.v2.Catalog.ListChannels:input_type -> api.v2.ListChannelsRequest
+	6,  // 7: api.v2.Catalog.GetDeprecations:input_type -> api.v2.GetDeprecationsRequest
+	10, // 8: api.v2.Catalog.ListCustomSchemaBlobs:input_type -> api.v2.ListCustomSchemaBlobsRequest
+	12, // 9: api.v2.Catalog.Watch:input_type -> api.v2.WatchRequest
+	1,  // 10: api.v2.Catalog.ListPackages:output_type -> api.v2.Package
+	4,  // 11: api.v2.Catalog.ListChannels:output_type -> api.v2.Channel
+	7,  // 12: api.v2.Catalog.GetDeprecations:output_type -> api.v2.Deprecations
+	11, // 13: api.v2.Catalog.ListCustomSchemaBlobs:output_type -> api.v2.CustomSchemaBlob
+	13, // 14: api.v2.Catalog.Watch:output_type -> api.v2.WatchEvent
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_v2_catalog_proto_init() }
+func file_v2_catalog_proto_init() {
+	if File_v2_catalog_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v2_catalog_proto_rawDesc), len(file_v2_catalog_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_v2_catalog_proto_goTypes,
+		DependencyIndexes: file_v2_catalog_proto_depIdxs,
+		MessageInfos:      file_v2_catalog_proto_msgTypes,
+	}.Build()
+	File_v2_catalog_proto = out.File
+	file_v2_catalog_proto_goTypes = nil
+	file_v2_catalog_proto_depIdxs = nil
+}
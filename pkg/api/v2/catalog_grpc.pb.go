@@ -0,0 +1,382 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v5.27.0
+// source: v2/catalog.proto
+
+package v2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Catalog_ListPackages_FullMethodName          = "/api.v2.Catalog/ListPackages"
+	Catalog_ListChannels_FullMethodName          = "/api.v2.Catalog/ListChannels"
+	Catalog_GetDeprecations_FullMethodName       = "/api.v2.Catalog/GetDeprecations"
+	Catalog_ListCustomSchemaBlobs_FullMethodName = "/api.v2.Catalog/ListCustomSchemaBlobs"
+	Catalog_Watch_FullMethodName                 = "/api.v2.Catalog/Watch"
+)
+
+// CatalogClient is the client API for Catalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CatalogClient interface {
+	// ListPackages sends each package of the catalog with its metadata.
+	ListPackages(ctx context.Context, in *ListPackagesRequest, opts ...grpc.CallOption) (Catalog_ListPackagesClient, error)
+	// ListChannels sends the channels of a package, or of every package if no
+	// package name is given, with all of their entries.
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (Catalog_ListChannelsClient, error)
+	// GetDeprecations returns the deprecations of a package.
+	GetDeprecations(ctx context.Context, in *GetDeprecationsRequest, opts ...grpc.CallOption) (*Deprecations, error)
+	// ListCustomSchemaBlobs sends the blobs of schemas other than the olm.*
+	// schemas that match the request. Empty request fields match all blobs.
+	ListCustomSchemaBlobs(ctx context.Context, in *ListCustomSchemaBlobsRequest, opts ...grpc.CallOption) (Catalog_ListCustomSchemaBlobsClient, error)
+	// Watch sends the digest of the served catalog, then an event each time
+	// the served content changes, until the client cancels the call.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Catalog_WatchClient, error)
+}
+
+type catalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatalogClient(cc grpc.ClientConnInterface) CatalogClient {
+	return &catalogClient{cc}
+}
+
+func (c *catalogClient) ListPackages(ctx context.Context, in *ListPackagesRequest, opts ...grpc.CallOption) (Catalog_ListPackagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[0], Catalog_ListPackages_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogListPackagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_ListPackagesClient interface {
+	Recv() (*Package, error)
+	grpc.ClientStream
+}
+
+type catalogListPackagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogListPackagesClient) Recv() (*Package, error) {
+	m := new(Package)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (Catalog_ListChannelsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[1], Catalog_ListChannels_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogListChannelsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_ListChannelsClient interface {
+	Recv() (*Channel, error)
+	grpc.ClientStream
+}
+
+type catalogListChannelsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogListChannelsClient) Recv() (*Channel, error) {
+	m := new(Channel)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogClient) GetDeprecations(ctx context.Context, in *GetDeprecationsRequest, opts ...grpc.CallOption) (*Deprecations, error) {
+	out := new(Deprecations)
+	err := c.cc.Invoke(ctx, Catalog_GetDeprecations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListCustomSchemaBlobs(ctx context.Context, in *ListCustomSchemaBlobsRequest, opts ...grpc.CallOption) (Catalog_ListCustomSchemaBlobsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[2], Catalog_ListCustomSchemaBlobs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogListCustomSchemaBlobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_ListCustomSchemaBlobsClient interface {
+	Recv() (*CustomSchemaBlob, error)
+	grpc.ClientStream
+}
+
+type catalogListCustomSchemaBlobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogListCustomSchemaBlobsClient) Recv() (*CustomSchemaBlob, error) {
+	m := new(CustomSchemaBlob)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Catalog_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[3], Catalog_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type catalogWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CatalogServer is the server API for Catalog service.
+// All implementations must embed UnimplementedCatalogServer
+// for forward compatibility
+type CatalogServer interface {
+	// ListPackages sends each package of the catalog with its metadata.
+	ListPackages(*ListPackagesRequest, Catalog_ListPackagesServer) error
+	// ListChannels sends the channels of a package, or of every package if no
+	// package name is given, with all of their entries.
+	ListChannels(*ListChannelsRequest, Catalog_ListChannelsServer) error
+	// GetDeprecations returns the deprecations of a package.
+	GetDeprecations(context.Context, *GetDeprecationsRequest) (*Deprecations, error)
+	// ListCustomSchemaBlobs sends the blobs of schemas other than the olm.*
+	// schemas that match the request. Empty request fields match all blobs.
+	ListCustomSchemaBlobs(*ListCustomSchemaBlobsRequest, Catalog_ListCustomSchemaBlobsServer) error
+	// Watch sends the digest of the served catalog, then an event each time
+	// the served content changes, until the client cancels the call.
+	Watch(*WatchRequest, Catalog_WatchServer) error
+	mustEmbedUnimplementedCatalogServer()
+}
+
+// UnimplementedCatalogServer must be embedded to have forward compatible implementations.
+type UnimplementedCatalogServer struct {
+}
+
+func (UnimplementedCatalogServer) ListPackages(*ListPackagesRequest, Catalog_ListPackagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPackages not implemented")
+}
+func (UnimplementedCatalogServer) ListChannels(*ListChannelsRequest, Catalog_ListChannelsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
+}
+func (UnimplementedCatalogServer) GetDeprecations(context.Context, *GetDeprecationsRequest) (*Deprecations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeprecations not implemented")
+}
+func (UnimplementedCatalogServer) ListCustomSchemaBlobs(*ListCustomSchemaBlobsRequest, Catalog_ListCustomSchemaBlobsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListCustomSchemaBlobs not implemented")
+}
+func (UnimplementedCatalogServer) Watch(*WatchRequest, Catalog_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedCatalogServer) mustEmbedUnimplementedCatalogServer() {}
+
+// UnsafeCatalogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CatalogServer will
+// result in compilation errors.
+type UnsafeCatalogServer interface {
+	mustEmbedUnimplementedCatalogServer()
+}
+
+func RegisterCatalogServer(s grpc.ServiceRegistrar, srv CatalogServer) {
+	s.RegisterService(&Catalog_ServiceDesc, srv)
+}
+
+func _Catalog_ListPackages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPackagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListPackages(m, &catalogListPackagesServer{stream})
+}
+
+type Catalog_ListPackagesServer interface {
+	Send(*Package) error
+	grpc.ServerStream
+}
+
+type catalogListPackagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogListPackagesServer) Send(m *Package) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Catalog_ListChannels_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListChannelsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListChannels(m, &catalogListChannelsServer{stream})
+}
+
+type Catalog_ListChannelsServer interface {
+	Send(*Channel) error
+	grpc.ServerStream
+}
+
+type catalogListChannelsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogListChannelsServer) Send(m *Channel) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Catalog_GetDeprecations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeprecationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).GetDeprecations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_GetDeprecations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).GetDeprecations(ctx, req.(*GetDeprecationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListCustomSchemaBlobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCustomSchemaBlobsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListCustomSchemaBlobs(m, &catalogListCustomSchemaBlobsServer{stream})
+}
+
+type Catalog_ListCustomSchemaBlobsServer interface {
+	Send(*CustomSchemaBlob) error
+	grpc.ServerStream
+}
+
+type catalogListCustomSchemaBlobsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogListCustomSchemaBlobsServer) Send(m *CustomSchemaBlob) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Catalog_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).Watch(m, &catalogWatchServer{stream})
+}
+
+type Catalog_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type catalogWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Catalog_ServiceDesc is the grpc.ServiceDesc for Catalog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Catalog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.v2.Catalog",
+	HandlerType: (*CatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDeprecations",
+			Handler:    _Catalog_GetDeprecations_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPackages",
+			Handler:       _Catalog_ListPackages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListChannels",
+			Handler:       _Catalog_ListChannels_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListCustomSchemaBlobs",
+			Handler:       _Catalog_ListCustomSchemaBlobs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Catalog_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v2/catalog.proto",
+}
@@ -32,6 +32,10 @@ func (b bundleKeys) Set(k bundleKey) {
 	b.t.Set(k)
 }
 
+func (b bundleKeys) Delete(k bundleKey) {
+	b.t.Delete(k)
+}
+
 func (b bundleKeys) Len() int {
 	return b.t.Len()
 }
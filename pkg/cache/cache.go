@@ -2,15 +2,19 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -24,13 +28,28 @@ import (
 
 type Cache interface {
 	registry.GRPCQuery
+	registry.FilteredBundleSender
+	registry.CatalogQuery
 
 	CheckIntegrity(ctx context.Context, fbc fs.FS) error
 	Build(ctx context.Context, fbc fs.FS) error
 	Load(ctc context.Context) error
 	Close() error
 
+	// Digest returns the digest recorded when the cache was last built.
+	Digest(ctx context.Context) (string, error)
+	// PackageDigests returns the digest of the content of each package
+	// served by the cache.
+	PackageDigests(ctx context.Context) (map[string]string, error)
+	// Format returns the name of the cache's storage format, e.g. FormatJSON.
+	Format() string
+
 	ListPackageCustomSchemas(ctx context.Context, schema, packageName string, sender func(*structpb.Struct) error) error
+	SendDeclarativeConfig(ctx context.Context, filter FBCFilter, sender func(*declcfg.Meta) error) error
+	// DeclarativeConfigDigest returns a digest of the declarative config
+	// content that SendDeclarativeConfig sends for filter, which changes
+	// whenever that content does.
+	DeclarativeConfigDigest(ctx context.Context, filter FBCFilter) (string, error)
 }
 
 type backend interface {
@@ -44,12 +63,36 @@ type backend interface {
 	GetPackageIndex(context.Context) (packageIndex, error)
 	PutPackageIndex(context.Context, packageIndex) error
 
-	SendBundles(context.Context, registry.BundleSender) error
+	SendBundles(context.Context, registry.BundleFilter, registry.BundleSender) error
 	GetBundle(context.Context, bundleKey) (*api.Bundle, error)
 	PutBundle(context.Context, bundleKey, *api.Bundle) error
+	DeleteBundle(context.Context, bundleKey) error
 
 	PutMeta(context.Context, metaKey, []byte) error
 	SendMetas(context.Context, metaKey, func(*structpb.Struct) error) error
+	// ListMetaKeys returns the keys of the stored custom schema metas.
+	ListMetaKeys(context.Context) ([]metaKey, error)
+	// DeleteMetas deletes the custom schema metas of the given packages.
+	DeleteMetas(context.Context, []string) error
+
+	// PutFBCPackages and GetFBCPackages store the names of the packages
+	// whose declarative config is stored with PutFBC. GetFBCPackages returns
+	// ErrFBCNotCached if the cache was built without declarative config
+	// content, or after DeleteFBCPackages.
+	PutFBCPackages(context.Context, []string) error
+	GetFBCPackages(context.Context) ([]string, error)
+	DeleteFBCPackages(context.Context) error
+	PutFBC(context.Context, string, []byte) error
+	GetFBC(context.Context, string) ([]byte, error)
+	DeleteFBC(context.Context, string) error
+
+	// PutPackageDigests and GetPackageDigests store the digest of the
+	// declarative config content of each package, which is used to rebuild
+	// only the packages that have changed. Like the declarative config
+	// content, they are not included in the cache digest. GetPackageDigests
+	// returns a nil map if the cache was built without package digests.
+	PutPackageDigests(context.Context, map[string]string) error
+	GetPackageDigests(context.Context) (map[string]string, error)
 
 	GetDigest(context.Context) (string, error)
 	ComputeDigest(context.Context, fs.FS) (string, error)
@@ -57,8 +100,49 @@ type backend interface {
 }
 
 type CacheOptions struct {
-	Log    *logrus.Entry
-	Format string
+	Log      *logrus.Entry
+	Format   string
+	Observer Observer
+	Seed     Cache
+	// DeclarativeConfig makes builds store the declarative config content
+	// of each package.
+	DeclarativeConfig bool
+}
+
+// Observer is called after each cache build and load with the name of the
+// operation (OperationBuild or OperationLoad), its duration and its result.
+type Observer func(operation string, d time.Duration, err error)
+
+const (
+	OperationBuild = "build"
+	OperationLoad  = "load"
+)
+
+func WithObserver(observer Observer) CacheOption {
+	return func(o *CacheOptions) {
+		o.Observer = observer
+	}
+}
+
+// WithSeed makes a build of a cache that holds no earlier build of its own
+// copy the packages that have not changed from seed, a loaded cache, instead
+// of rebuilding them. seed must have been created by New, or wrap such a
+// cache and return it from an Unwrap() Cache method, and must not be closed
+// while the cache is being built. Other seeds are ignored.
+func WithSeed(seed Cache) CacheOption {
+	return func(o *CacheOptions) {
+		o.Seed = seed
+	}
+}
+
+// WithDeclarativeConfig makes builds also store the declarative config
+// content of each package, which SendDeclarativeConfig and
+// DeclarativeConfigDigest serve. That content is a second copy of the
+// catalog, so it is only stored for caches that serve it.
+func WithDeclarativeConfig() CacheOption {
+	return func(o *CacheOptions) {
+		o.DeclarativeConfig = true
+	}
 }
 
 func WithLog(log *logrus.Entry) CacheOption {
@@ -94,7 +178,28 @@ func New(cacheDir string, cacheOpts ...CacheOption) (Cache, error) {
 	if err := cacheBackend.Open(); err != nil {
 		return nil, fmt.Errorf("open cache: %v", err)
 	}
-	return &cache{backend: cacheBackend, log: opts.Log}, nil
+	return &cache{
+		backend:  cacheBackend,
+		log:      opts.Log,
+		observer: opts.Observer,
+		seed:     unwrapCache(opts.Seed),
+		storeFBC: opts.DeclarativeConfig,
+	}, nil
+}
+
+// unwrapCache returns the cache created by New that c is or wraps, or nil.
+func unwrapCache(c Cache) *cache {
+	for c != nil {
+		switch v := c.(type) {
+		case *cache:
+			return v
+		case interface{ Unwrap() Cache }:
+			c = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
 }
 
 func getBackend(cacheDir string, backendName string, log *logrus.Entry) (backend, error) {
@@ -140,8 +245,11 @@ func getBackend(cacheDir string, backendName string, log *logrus.Entry) (backend
 	return nil, fmt.Errorf("cache directory has unexpected contents: %v", strings.Join(entryNames, ","))
 }
 
+// LoadOrRebuild loads c, first rebuilding it if it fails its integrity
+// check, or if it was created with WithDeclarativeConfig but built without
+// declarative config content.
 func LoadOrRebuild(ctx context.Context, c Cache, fbc fs.FS) error {
-	if err := c.CheckIntegrity(ctx, fbc); err != nil {
+	if err := c.CheckIntegrity(ctx, fbc); err != nil || MissesDeclarativeConfig(ctx, c) {
 		if err := c.Build(ctx, fbc); err != nil {
 			return fmt.Errorf("failed to rebuild cache: %v", err)
 		}
@@ -149,11 +257,26 @@ func LoadOrRebuild(ctx context.Context, c Cache, fbc fs.FS) error {
 	return c.Load(ctx)
 }
 
+// MissesDeclarativeConfig reports whether c was created with
+// WithDeclarativeConfig, but was built without declarative config content,
+// e.g. by a build that did not use the option.
+func MissesDeclarativeConfig(ctx context.Context, c Cache) bool {
+	cc := unwrapCache(c)
+	if cc == nil || !cc.storeFBC {
+		return false
+	}
+	_, err := cc.backend.GetFBCPackages(ctx)
+	return errors.Is(err, ErrFBCNotCached)
+}
+
 var _ Cache = &cache{}
 
 type cache struct {
-	backend backend
-	log     *logrus.Entry
+	backend  backend
+	log      *logrus.Entry
+	observer Observer
+	seed     *cache
+	storeFBC bool
 	packageIndex
 }
 
@@ -176,6 +299,10 @@ func (s *sliceBundleSender) Send(b *api.Bundle) error {
 }
 
 func (c *cache) SendBundles(ctx context.Context, stream registry.BundleSender) error {
+	return c.SendFilteredBundles(ctx, registry.BundleFilter{}, stream)
+}
+
+func (c *cache) SendFilteredBundles(ctx context.Context, filter registry.BundleFilter, stream registry.BundleSender) error {
 	transform := func(bundle *api.Bundle) {
 		if bundle.BundlePath != "" {
 			// The SQLite-based server
@@ -186,7 +313,7 @@ func (c *cache) SendBundles(ctx context.Context, stream registry.BundleSender) e
 			bundle.Object = nil
 		}
 	}
-	return c.backend.SendBundles(ctx, &transformingBundleSender{stream, transform})
+	return c.backend.SendBundles(ctx, filter, &transformingBundleSender{stream, transform})
 }
 
 func (c *cache) ListBundles(ctx context.Context) ([]*api.Bundle, error) {
@@ -267,17 +394,58 @@ func (c *cache) CheckIntegrity(ctx context.Context, fbc fs.FS) error {
 	return nil
 }
 
+func (c *cache) Digest(ctx context.Context) (string, error) {
+	return c.backend.GetDigest(ctx)
+}
+
+// PackageDigests returns the digest of the declarative config content of
+// each package in the package index. Caches built without package digests
+// report the cache digest for every package, so that every package appears
+// to have changed whenever the cache has.
+func (c *cache) PackageDigests(ctx context.Context) (map[string]string, error) {
+	stored, err := c.backend.GetPackageDigests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fallback string
+	if stored == nil {
+		if fallback, err = c.backend.GetDigest(ctx); err != nil {
+			return nil, err
+		}
+	}
+	digests := make(map[string]string, len(c.packageIndex))
+	for pkgName := range c.packageIndex {
+		digest, ok := stored[pkgName]
+		if !ok {
+			digest = fallback
+		}
+		digests[pkgName] = digest
+	}
+	return digests, nil
+}
+
+func (c *cache) Format() string {
+	return c.backend.Name()
+}
+
+func (c *cache) observe(operation string, start time.Time, err error) {
+	if c.observer != nil {
+		c.observer(operation, time.Since(start), err)
+	}
+}
+
 func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
+	start := time.Now()
+	err := c.build(ctx, fbcFsys)
+	c.observe(OperationBuild, start, err)
+	return err
+}
+
+func (c *cache) build(ctx context.Context, fbcFsys fs.FS) error {
 	// ensure that generated cache is available to all future users
 	oldUmask := umask(000)
 	defer umask(oldUmask)
 
-	c.log.Info("building cache")
-
-	if err := c.backend.Init(); err != nil {
-		return fmt.Errorf("init cache: %v", err)
-	}
-
 	tmpFile, err := os.CreateTemp("", "opm-cache-build-*.json")
 	if err != nil {
 		return err
@@ -288,11 +456,10 @@ func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
 	}()
 
 	var (
-		concurrency      = runtime.NumCPU()
-		byPackageReaders = map[string][]io.Reader{}
-		walkMu           sync.Mutex
-		metaMu           sync.Mutex
-		offset           int64
+		concurrency  = runtime.NumCPU()
+		byPackageFBC = map[string][]fbcEntry{}
+		walkMu       sync.Mutex
+		offset       int64
 	)
 	if err := declcfg.WalkMetasFS(ctx, fbcFsys, func(path string, meta *declcfg.Meta, err error) error {
 		if err != nil {
@@ -302,20 +469,10 @@ func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
 		if meta.Schema == declcfg.SchemaPackage {
 			packageName = meta.Name
 		}
-
-		switch meta.Schema {
-		case declcfg.SchemaPackage, declcfg.SchemaChannel, declcfg.SchemaBundle, declcfg.SchemaDeprecation:
-		default:
-			mk, err := newValidatedMetaKey(meta.Schema, packageName)
-			if err != nil {
+		if !isCoreSchema(meta.Schema) {
+			if _, err := newValidatedMetaKey(meta.Schema, packageName); err != nil {
 				return fmt.Errorf("invalid custom schema meta: %w", err)
 			}
-			metaMu.Lock()
-			defer metaMu.Unlock()
-			if err := c.backend.PutMeta(ctx, mk, meta.Blob); err != nil {
-				return fmt.Errorf("store custom schema meta %v: %w", mk, err)
-			}
-			return nil
 		}
 
 		walkMu.Lock()
@@ -323,8 +480,11 @@ func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
 		if _, err := tmpFile.Write(meta.Blob); err != nil {
 			return err
 		}
-		sr := io.NewSectionReader(tmpFile, offset, int64(len(meta.Blob)))
-		byPackageReaders[packageName] = append(byPackageReaders[packageName], sr)
+		byPackageFBC[packageName] = append(byPackageFBC[packageName], fbcEntry{
+			schema: meta.Schema,
+			name:   meta.Name,
+			blob:   io.NewSectionReader(tmpFile, offset, int64(len(meta.Blob))),
+		})
 		offset += int64(len(meta.Blob))
 		return nil
 	}, declcfg.WithConcurrency(concurrency)); err != nil {
@@ -333,54 +493,72 @@ func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
 	if err := tmpFile.Sync(); err != nil {
 		return err
 	}
-
-	eg, egCtx := errgroup.WithContext(ctx)
-	pkgNameChan := make(chan string, concurrency)
-	eg.Go(func() error {
-		defer close(pkgNameChan)
-		for pkgName := range byPackageReaders {
-			select {
-			case <-egCtx.Done():
-				return egCtx.Err()
-			case pkgNameChan <- pkgName:
-			}
+	packages := make(map[string]*packageSource, len(byPackageFBC))
+	for pkgName, entries := range byPackageFBC {
+		packages[pkgName] = &packageSource{entries: entries}
+	}
+	if err := forEachPackage(ctx, slices.Collect(maps.Keys(packages)), concurrency, func(_ context.Context, pkgName string) error {
+		content, err := packages[pkgName].load()
+		if err != nil {
+			return fmt.Errorf("read package %q: %v", pkgName, err)
 		}
+		packages[pkgName].digest = content.digest
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
 
-	var (
-		pkgs   = packageIndex{}
-		pkgsMu sync.Mutex
-	)
-	for i := 0; i < concurrency; i++ {
-		eg.Go(func() error {
-			for {
-				select {
-				case <-egCtx.Done():
-					return egCtx.Err()
-				case pkgName, ok := <-pkgNameChan:
-					if !ok {
-						return nil
-					}
-					pkgIndex, err := c.processPackage(egCtx, io.MultiReader(byPackageReaders[pkgName]...))
-					if err != nil {
-						return fmt.Errorf("process package %q: %v", pkgName, err)
-					}
+	pkgs, pkgsToBuild, err := c.prepareBuild(ctx, packages)
+	if err != nil {
+		return err
+	}
 
-					pkgsMu.Lock()
-					pkgs[pkgName] = pkgIndex[pkgName]
-					pkgsMu.Unlock()
-				}
-			}
-		})
+	// Declarative config content and custom schema metas are stored in the
+	// same order by full and incremental builds, so that both produce the
+	// same digest.
+	for _, pkgName := range pkgsToBuild {
+		if err := c.storePackageContent(ctx, pkgName, packages[pkgName]); err != nil {
+			return err
+		}
 	}
-	if err := eg.Wait(); err != nil {
+	if c.storeFBC {
+		if err := c.backend.PutFBCPackages(ctx, slices.Sorted(maps.Keys(packages))); err != nil {
+			return fmt.Errorf("store declarative config: %v", err)
+		}
+	}
+
+	var pkgsMu sync.Mutex
+	if err := forEachPackage(ctx, pkgsToBuild, concurrency, func(ctx context.Context, pkgName string) error {
+		if !packages[pkgName].hasCoreSchemas() {
+			return nil
+		}
+		content, err := packages[pkgName].load()
+		if err != nil {
+			return fmt.Errorf("read package %q: %v", pkgName, err)
+		}
+		pkgIndex, err := c.processPackage(ctx, content.coreSchemaReader())
+		if err != nil {
+			return fmt.Errorf("process package %q: %v", pkgName, err)
+		}
+
+		pkgsMu.Lock()
+		defer pkgsMu.Unlock()
+		pkgs[pkgName] = pkgIndex[pkgName]
+		return nil
+	}); err != nil {
 		return fmt.Errorf("build package index: %v", err)
 	}
 
 	if err := c.backend.PutPackageIndex(ctx, pkgs); err != nil {
 		return fmt.Errorf("store package index: %v", err)
 	}
+	packageDigests := make(map[string]string, len(packages))
+	for pkgName, p := range packages {
+		packageDigests[pkgName] = p.digest
+	}
+	if err := c.backend.PutPackageDigests(ctx, packageDigests); err != nil {
+		return fmt.Errorf("store package digests: %v", err)
+	}
 
 	digest, err := c.backend.ComputeDigest(ctx, fbcFsys)
 	if err != nil {
@@ -392,6 +570,218 @@ func (c *cache) Build(ctx context.Context, fbcFsys fs.FS) error {
 	return nil
 }
 
+// storePackageContent stores the custom schema metas of a package, and its
+// declarative config content if the cache stores it.
+func (c *cache) storePackageContent(ctx context.Context, pkgName string, p *packageSource) error {
+	content, err := p.load()
+	if err != nil {
+		return fmt.Errorf("read package %q: %v", pkgName, err)
+	}
+	for _, m := range content.metas {
+		if isCoreSchema(m.schema) {
+			continue
+		}
+		mk := metaKey{Schema: m.schema, PackageName: pkgName}
+		if err := c.backend.PutMeta(ctx, mk, m.blob); err != nil {
+			return fmt.Errorf("store custom schema meta %v: %w", mk, err)
+		}
+	}
+	if !c.storeFBC {
+		return nil
+	}
+	if err := c.backend.PutFBC(ctx, pkgName, content.data); err != nil {
+		return fmt.Errorf("store declarative config for package %q: %v", pkgName, err)
+	}
+	return nil
+}
+
+// prepareBuild prepares the backend to store packages, returning the index
+// of the packages that are already stored and the names of the packages that
+// must be built.
+//
+// If the backend holds a complete earlier build with package digests, only
+// the packages that were added or whose digest has changed are built, after
+// the stored content of those packages and of removed packages is deleted.
+// Otherwise, the backend is emptied and every package is built, except for
+// the packages that are copied from the seed cache, if there is one.
+func (c *cache) prepareBuild(ctx context.Context, packages map[string]*packageSource) (packageIndex, []string, error) {
+	pkgIndex, prevDigests, ok := c.previousBuild(ctx)
+	if !ok {
+		if err := c.backend.Init(); err != nil {
+			return nil, nil, fmt.Errorf("init cache: %v", err)
+		}
+		if c.seed != nil {
+			return c.copySeedPackages(ctx, packages)
+		}
+		c.log.Info("building cache")
+		return packageIndex{}, slices.Sorted(maps.Keys(packages)), nil
+	}
+
+	var changed, removed []string
+	for pkgName, p := range packages {
+		if prevDigest, ok := prevDigests[pkgName]; !ok || prevDigest != p.digest {
+			changed = append(changed, pkgName)
+		}
+	}
+	for pkgName := range prevDigests {
+		if _, ok := packages[pkgName]; !ok {
+			removed = append(removed, pkgName)
+		}
+	}
+	slices.Sort(changed)
+	slices.Sort(removed)
+	c.log.WithFields(logrus.Fields{
+		"packages": len(packages),
+		"changed":  len(changed),
+		"removed":  len(removed),
+	}).Info("updating cache")
+
+	// Invalidate the cache while it is updated, so that an interrupted update
+	// fails integrity checks and is followed by a full build.
+	if err := c.backend.PutDigest(ctx, ""); err != nil {
+		return nil, nil, fmt.Errorf("invalidate digest: %v", err)
+	}
+	stale := slices.Concat(changed, removed)
+	for _, pkgName := range stale {
+		if pkg, ok := pkgIndex[pkgName]; ok {
+			for _, ch := range pkg.Channels {
+				for _, b := range ch.Bundles {
+					if err := c.backend.DeleteBundle(ctx, bundleKey{pkg.Name, ch.Name, b.Name}); err != nil {
+						return nil, nil, fmt.Errorf("delete bundle %q: %v", b.Name, err)
+					}
+				}
+			}
+			delete(pkgIndex, pkgName)
+		}
+		if err := c.backend.DeleteFBC(ctx, pkgName); err != nil {
+			return nil, nil, fmt.Errorf("delete declarative config for package %q: %v", pkgName, err)
+		}
+	}
+	if err := c.backend.DeleteMetas(ctx, stale); err != nil {
+		return nil, nil, fmt.Errorf("delete custom schema metas: %v", err)
+	}
+	if !c.storeFBC {
+		// The declarative config content of an earlier build that stored
+		// it would be out of date after this one.
+		if err := c.deleteFBC(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pkgIndex, changed, nil
+}
+
+// deleteFBC deletes the declarative config content of the backend, if any.
+func (c *cache) deleteFBC(ctx context.Context) error {
+	packageNames, err := c.backend.GetFBCPackages(ctx)
+	if errors.Is(err, ErrFBCNotCached) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read declarative config packages: %v", err)
+	}
+	for _, pkgName := range packageNames {
+		if err := c.backend.DeleteFBC(ctx, pkgName); err != nil {
+			return fmt.Errorf("delete declarative config for package %q: %v", pkgName, err)
+		}
+	}
+	if err := c.backend.DeleteFBCPackages(ctx); err != nil {
+		return fmt.Errorf("delete declarative config packages: %v", err)
+	}
+	return nil
+}
+
+// copySeedPackages copies the packages whose digest is the same in the seed
+// cache into the backend, which must be empty, returning their index and the
+// names of the packages that must still be built. Only bundles are read from
+// the seed. The declarative config content and custom schema metas of the
+// copied packages are stored from packages, as they are for built packages.
+func (c *cache) copySeedPackages(ctx context.Context, packages map[string]*packageSource) (packageIndex, []string, error) {
+	seedDigests, err := c.seed.backend.GetPackageDigests(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read seed package digests: %v", err)
+	}
+	var unchanged, changed []string
+	for _, pkgName := range slices.Sorted(maps.Keys(packages)) {
+		p := packages[pkgName]
+		_, indexed := c.seed.packageIndex[pkgName]
+		if digest, ok := seedDigests[pkgName]; ok && digest == p.digest && (indexed || !p.hasCoreSchemas()) {
+			unchanged = append(unchanged, pkgName)
+		} else {
+			changed = append(changed, pkgName)
+		}
+	}
+	c.log.WithFields(logrus.Fields{
+		"packages": len(packages),
+		"changed":  len(changed),
+	}).Info("building cache from seed")
+
+	pkgIndex := packageIndex{}
+	for _, pkgName := range unchanged {
+		if err := c.storePackageContent(ctx, pkgName, packages[pkgName]); err != nil {
+			return nil, nil, err
+		}
+		if pkg, ok := c.seed.packageIndex[pkgName]; ok {
+			pkgIndex[pkgName] = pkg
+		}
+	}
+	if err := forEachPackage(ctx, slices.Collect(maps.Keys(pkgIndex)), runtime.NumCPU(), func(ctx context.Context, pkgName string) error {
+		pkg := pkgIndex[pkgName]
+		for _, ch := range pkg.Channels {
+			for _, b := range ch.Bundles {
+				key := bundleKey{pkg.Name, ch.Name, b.Name}
+				bundle, err := c.seed.backend.GetBundle(ctx, key)
+				if err != nil {
+					return fmt.Errorf("read seed bundle %q: %v", b.Name, err)
+				}
+				if err := c.backend.PutBundle(ctx, key, bundle); err != nil {
+					return fmt.Errorf("store bundle %q: %v", b.Name, err)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, fmt.Errorf("copy seed packages: %v", err)
+	}
+	return pkgIndex, changed, nil
+}
+
+// forEachPackage calls fn for each of the named packages, with at most
+// concurrency calls running at a time.
+func forEachPackage(ctx context.Context, pkgNames []string, concurrency int, fn func(context.Context, string) error) error {
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for _, pkgName := range pkgNames {
+		if egCtx.Err() != nil {
+			break
+		}
+		eg.Go(func() error { return fn(egCtx, pkgName) })
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// previousBuild returns the package index and package digests of the
+// complete build held by the backend, if any.
+func (c *cache) previousBuild(ctx context.Context) (packageIndex, map[string]string, bool) {
+	if digest, err := c.backend.GetDigest(ctx); err != nil || digest == "" {
+		return nil, nil, false
+	}
+	digests, err := c.backend.GetPackageDigests(ctx)
+	if err != nil || digests == nil {
+		return nil, nil, false
+	}
+	if _, err := c.backend.GetFBCPackages(ctx); err != nil && c.storeFBC {
+		return nil, nil, false
+	}
+	pkgIndex, err := c.backend.GetPackageIndex(ctx)
+	if err != nil {
+		return nil, nil, false
+	}
+	return pkgIndex, digests, true
+}
+
 func (c *cache) processPackage(ctx context.Context, reader io.Reader) (packageIndex, error) {
 	pkgFbc, err := declcfg.LoadReader(reader)
 	if err != nil {
@@ -422,6 +812,13 @@ func (c *cache) processPackage(ctx context.Context, reader io.Reader) (packageIn
 }
 
 func (c *cache) Load(ctx context.Context) error {
+	start := time.Now()
+	err := c.load(ctx)
+	c.observe(OperationLoad, start, err)
+	return err
+}
+
+func (c *cache) load(ctx context.Context) error {
 	pi, err := c.backend.GetPackageIndex(ctx)
 	if err != nil {
 		return fmt.Errorf("get package index: %v", err)
@@ -462,6 +859,29 @@ func writeDigestFile(file string, digest string, mode os.FileMode) error {
 	return os.WriteFile(file, []byte(digest), mode)
 }
 
+func readPackageDigestsFile(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var digests map[string]string
+	if err := json.Unmarshal(data, &digests); err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+func writePackageDigestsFile(file string, digests map[string]string, mode os.FileMode) error {
+	data, err := json.Marshal(digests)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, mode)
+}
+
 func doesBundleProvide(ctx context.Context, getBundle getBundleFunc, pkgName, chName, bundleName, group, version, kind string) (bool, error) {
 	apiBundle, err := getBundle(ctx, bundleKey{pkgName, chName, bundleName})
 	if err != nil {
@@ -3,13 +3,19 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/fs"
+	"maps"
+	"slices"
 	"testing"
 	"testing/fstest"
 
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/pkg/lib/log"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 )
@@ -210,6 +216,62 @@ func TestCache_ListBundles(t *testing.T) {
 	}
 }
 
+func TestCache_SendBundlesFiltered(t *testing.T) {
+	for name, testQuerier := range genTestCaches(t, validFS) {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range []struct {
+				name     string
+				filter   registry.BundleFilter
+				expected []string
+			}{
+				{
+					name:     "Package",
+					filter:   registry.BundleFilter{PackageName: "cockroachdb"},
+					expected: []string{"cockroachdb.v2.0.9", "cockroachdb.v2.1.1", "cockroachdb.v2.1.11", "cockroachdb.v3.0.7", "cockroachdb.v5.0.3"},
+				},
+				{
+					name:     "Channel",
+					filter:   registry.BundleFilter{ChannelName: "stable-5.x"},
+					expected: []string{"cockroachdb.v5.0.3"},
+				},
+				{
+					name:     "PackageAndChannel",
+					filter:   registry.BundleFilter{PackageName: "etcd", ChannelName: "stable-5.x"},
+					expected: nil,
+				},
+				{
+					name:     "UnknownPackage",
+					filter:   registry.BundleFilter{PackageName: "nonexistent"},
+					expected: nil,
+				},
+			} {
+				t.Run(tt.name, func(t *testing.T) {
+					var bundles sliceBundleSender
+					require.NoError(t, testQuerier.SendFilteredBundles(context.TODO(), tt.filter, &bundles))
+					var names []string
+					for _, b := range bundles {
+						names = append(names, b.CsvName)
+					}
+					require.ElementsMatch(t, tt.expected, names)
+				})
+			}
+		})
+	}
+}
+
+func TestCache_PackageDigests(t *testing.T) {
+	for name, testQuerier := range genTestCaches(t, validFS) {
+		t.Run(name, func(t *testing.T) {
+			digests, err := testQuerier.PackageDigests(context.TODO())
+			require.NoError(t, err)
+			require.Len(t, digests, 2)
+			require.NotEmpty(t, digests["cockroachdb"])
+			require.NotEmpty(t, digests["etcd"])
+			require.NotEqual(t, digests["cockroachdb"], digests["etcd"])
+		})
+	}
+}
+
 func TestCache_ListPackages(t *testing.T) {
 	for name, testQuerier := range genTestCaches(t, validFS) {
 		t.Run(name, func(t *testing.T) {
@@ -382,12 +444,12 @@ func TestCache_ListPackageCustomSchemas_NoCustomSchemas(t *testing.T) {
 	}
 }
 
-func genTestCaches(t *testing.T, fbcFS fs.FS) map[string]Cache {
+func genTestCaches(t *testing.T, fbcFS fs.FS, opts ...CacheOption) map[string]Cache {
 	t.Helper()
 
 	caches := make(map[string]Cache)
 	for _, format := range []string{FormatJSON, FormatPogrebV1} {
-		c, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()))
+		c, err := New(t.TempDir(), append([]CacheOption{WithFormat(format), WithLog(log.Null())}, opts...)...)
 		require.NoError(t, err)
 		caches[format] = c
 	}
@@ -802,3 +864,317 @@ var badBundleFS = fstest.MapFS{
         }
     ]
 }`)}}
+
+func TestCache_SendDeclarativeConfig(t *testing.T) {
+	for name, testCache := range genTestCaches(t, customSchemaFS, WithDeclarativeConfig()) {
+		t.Run(name, func(t *testing.T) {
+			collect := func(filter FBCFilter) ([]string, error) {
+				t.Helper()
+				var names []string
+				err := testCache.SendDeclarativeConfig(context.TODO(), filter, func(meta *declcfg.Meta) error {
+					names = append(names, meta.Schema+"/"+meta.Name)
+					return nil
+				})
+				return names, err
+			}
+
+			// All objects, in canonical order.
+			names, err := collect(FBCFilter{})
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				"olm.package/testpkg",
+				"olm.channel/stable",
+				"olm.bundle/testpkg.v1.0.0",
+				"custom.operator.io/another-custom-resource",
+				"custom.operator.io/my-custom-resource",
+				"other.custom.schema/other-custom",
+			}, names)
+
+			// Filtered by schema.
+			names, err = collect(FBCFilter{Package: "testpkg", Schema: "custom.operator.io"})
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				"custom.operator.io/another-custom-resource",
+				"custom.operator.io/my-custom-resource",
+			}, names)
+
+			// Unknown package.
+			_, err = collect(FBCFilter{Package: "nonexistent"})
+			require.ErrorIs(t, err, ErrPackageNotFound)
+		})
+	}
+}
+
+func incrementalTestPackage(name, version string) string {
+	return fmt.Sprintf(`{"schema": "olm.package", "name": %[1]q, "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": %[1]q, "name": "stable", "entries": [{"name": "%[1]s.v%[2]s"}]}
+{"schema": "olm.bundle", "package": %[1]q, "name": "%[1]s.v%[2]s", "image": "quay.io/test/%[1]s:v%[2]s", "properties": [{"type": "olm.package", "value": {"packageName": %[1]q, "version": %[2]q}}]}
+{"schema": "custom.example.com", "package": %[1]q, "name": "extra", "version": %[2]q}
+`, name, version)
+}
+
+func TestCache_IncrementalBuild(t *testing.T) {
+	before := fstest.MapFS{
+		"foo.json":     &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "1.0.0"))},
+		"bar.json":     &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "1.0.0"))},
+		"baz.json":     &fstest.MapFile{Data: []byte(incrementalTestPackage("baz", "1.0.0"))},
+		"global.json":  &fstest.MapFile{Data: []byte(`{"schema": "custom.global", "name": "global"}`)},
+		"unknown.json": &fstest.MapFile{Data: []byte(`{"schema": "custom.example.com", "package": "unknown", "name": "extra"}`)},
+	}
+	after := fstest.MapFS{
+		// foo is unchanged, bar is updated, baz and unknown are removed
+		// and qux is added.
+		"foo.json":    before["foo.json"],
+		"bar.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "2.0.0"))},
+		"qux.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("qux", "1.0.0"))},
+		"global.json": before["global.json"],
+	}
+
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			logger, hook := test.NewNullLogger()
+			incremental, err := New(t.TempDir(), WithFormat(format), WithLog(logrus.NewEntry(logger)), WithDeclarativeConfig())
+			require.NoError(t, err)
+			defer incremental.Close()
+			require.NoError(t, incremental.Build(ctx, before))
+
+			hook.Reset()
+			require.NoError(t, incremental.Build(ctx, after))
+			require.NoError(t, incremental.Load(ctx))
+			require.NoError(t, incremental.CheckIntegrity(ctx, after))
+			entry := hook.LastEntry()
+			require.Equal(t, "updating cache", entry.Message)
+			require.Equal(t, logrus.Fields{"packages": 4, "changed": 2, "removed": 2}, entry.Data)
+
+			full, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()), WithDeclarativeConfig())
+			require.NoError(t, err)
+			defer full.Close()
+			require.NoError(t, full.Build(ctx, after))
+			require.NoError(t, full.Load(ctx))
+
+			// An incremental build stores the same content as a full build.
+			incrementalDigest, err := incremental.Digest(ctx)
+			require.NoError(t, err)
+			fullDigest, err := full.Digest(ctx)
+			require.NoError(t, err)
+			require.Equal(t, fullDigest, incrementalDigest)
+
+			listPackages := func(c Cache) []string {
+				pkgs, err := c.ListPackages(ctx)
+				require.NoError(t, err)
+				return pkgs
+			}
+			require.ElementsMatch(t, []string{"bar", "foo", "qux"}, listPackages(incremental))
+
+			listBundles := func(c Cache) []string {
+				bundles, err := c.ListBundles(ctx)
+				require.NoError(t, err)
+				var names []string
+				for _, b := range bundles {
+					names = append(names, b.CsvName)
+				}
+				return names
+			}
+			require.ElementsMatch(t, []string{"bar.v2.0.0", "foo.v1.0.0", "qux.v1.0.0"}, listBundles(incremental))
+			require.Equal(t, listBundles(full), listBundles(incremental))
+
+			for _, pkgName := range []string{"foo", "bar", "baz", "qux", "unknown"} {
+				var versions []string
+				require.NoError(t, incremental.ListPackageCustomSchemas(ctx, "custom.example.com", pkgName, func(s *structpb.Struct) error {
+					versions = append(versions, s.Fields["version"].GetStringValue())
+					return nil
+				}))
+				switch pkgName {
+				case "foo", "qux":
+					require.Equal(t, []string{"1.0.0"}, versions, pkgName)
+				case "bar":
+					require.Equal(t, []string{"2.0.0"}, versions, pkgName)
+				default:
+					require.Empty(t, versions, pkgName)
+				}
+			}
+
+			var fbcNames []string
+			require.NoError(t, incremental.SendDeclarativeConfig(ctx, FBCFilter{Schema: declcfg.SchemaBundle}, func(m *declcfg.Meta) error {
+				fbcNames = append(fbcNames, m.Name)
+				return nil
+			}))
+			require.Equal(t, []string{"bar.v2.0.0", "foo.v1.0.0", "qux.v1.0.0"}, fbcNames)
+		})
+	}
+}
+
+func TestCache_SeededBuild(t *testing.T) {
+	before := fstest.MapFS{
+		"foo.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "1.0.0"))},
+		"bar.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "1.0.0"))},
+		"global.json": &fstest.MapFile{Data: []byte(`{"schema": "custom.global", "name": "global"}`)},
+	}
+	after := fstest.MapFS{
+		"foo.json":    before["foo.json"],
+		"bar.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "2.0.0"))},
+		"qux.json":    &fstest.MapFile{Data: []byte(incrementalTestPackage("qux", "1.0.0"))},
+		"global.json": before["global.json"],
+	}
+
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			seed, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()))
+			require.NoError(t, err)
+			defer seed.Close()
+			require.NoError(t, seed.Build(ctx, before))
+			require.NoError(t, seed.Load(ctx))
+
+			logger, hook := test.NewNullLogger()
+			seeded, err := New(t.TempDir(), WithFormat(format), WithLog(logrus.NewEntry(logger)), WithSeed(seed))
+			require.NoError(t, err)
+			defer seeded.Close()
+			require.NoError(t, seeded.Build(ctx, after))
+			require.NoError(t, seeded.Load(ctx))
+			require.NoError(t, seeded.CheckIntegrity(ctx, after))
+			entry := hook.LastEntry()
+			require.Equal(t, "building cache from seed", entry.Message)
+			require.Equal(t, logrus.Fields{"packages": 4, "changed": 2}, entry.Data)
+
+			full, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()))
+			require.NoError(t, err)
+			defer full.Close()
+			require.NoError(t, full.Build(ctx, after))
+			require.NoError(t, full.Load(ctx))
+
+			// A seeded build stores the same content as a full build.
+			seededDigest, err := seeded.Digest(ctx)
+			require.NoError(t, err)
+			fullDigest, err := full.Digest(ctx)
+			require.NoError(t, err)
+			require.Equal(t, fullDigest, seededDigest)
+
+			seededBundles, err := seeded.ListBundles(ctx)
+			require.NoError(t, err)
+			fullBundles, err := full.ListBundles(ctx)
+			require.NoError(t, err)
+			require.Len(t, seededBundles, 3)
+			require.ElementsMatch(t, fullBundles, seededBundles)
+		})
+	}
+}
+
+func TestCache_DeclarativeConfigDigest(t *testing.T) {
+	ctx := context.Background()
+	before := fstest.MapFS{
+		"foo.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "1.0.0"))},
+		"bar.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "1.0.0"))},
+	}
+	after := fstest.MapFS{
+		"foo.json": before["foo.json"],
+		"bar.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("bar", "2.0.0"))},
+	}
+
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			c, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()), WithDeclarativeConfig())
+			require.NoError(t, err)
+			defer c.Close()
+			digests := func(fbc fs.FS) map[FBCFilter]string {
+				t.Helper()
+				require.NoError(t, c.Build(ctx, fbc))
+				out := map[FBCFilter]string{}
+				for _, filter := range []FBCFilter{{}, {Package: "foo"}, {Package: "bar"}, {Schema: declcfg.SchemaBundle}} {
+					digest, err := c.DeclarativeConfigDigest(ctx, filter)
+					require.NoError(t, err)
+					out[filter] = digest
+				}
+				return out
+			}
+			beforeDigests, afterDigests := digests(before), digests(after)
+
+			// Each filter has its own digest, which only changes with the
+			// content of the packages it selects.
+			require.Len(t, slices.Compact(slices.Sorted(maps.Values(beforeDigests))), len(beforeDigests))
+			require.Equal(t, beforeDigests[FBCFilter{Package: "foo"}], afterDigests[FBCFilter{Package: "foo"}])
+			require.NotEqual(t, beforeDigests[FBCFilter{Package: "bar"}], afterDigests[FBCFilter{Package: "bar"}])
+			require.NotEqual(t, beforeDigests[FBCFilter{}], afterDigests[FBCFilter{}])
+			require.NotEqual(t, beforeDigests[FBCFilter{Schema: declcfg.SchemaBundle}], afterDigests[FBCFilter{Schema: declcfg.SchemaBundle}])
+
+			_, err = c.DeclarativeConfigDigest(ctx, FBCFilter{Package: "nonexistent"})
+			require.ErrorIs(t, err, ErrPackageNotFound)
+		})
+	}
+}
+
+func TestCache_WithDeclarativeConfig(t *testing.T) {
+	fbc := fstest.MapFS{"foo.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "1.0.0"))}}
+	updated := fstest.MapFS{"foo.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "2.0.0"))}}
+
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			sendFBC := func(c Cache) error {
+				return c.SendDeclarativeConfig(ctx, FBCFilter{}, func(*declcfg.Meta) error { return nil })
+			}
+
+			// Without the option, the declarative config content is not
+			// stored, and the cache is valid without it.
+			c, err := New(dir, WithFormat(format), WithLog(log.Null()))
+			require.NoError(t, err)
+			require.NoError(t, c.Build(ctx, fbc))
+			require.NoError(t, c.CheckIntegrity(ctx, fbc))
+			require.ErrorIs(t, sendFBC(c), ErrFBCNotCached)
+			require.NoError(t, c.Close())
+
+			// With the option, a cache built without the content is rebuilt
+			// to store it, and keeps the same digest.
+			c, err = New(dir, WithFormat(format), WithLog(log.Null()), WithDeclarativeConfig())
+			require.NoError(t, err)
+			digest, err := c.Digest(ctx)
+			require.NoError(t, err)
+			require.NoError(t, c.CheckIntegrity(ctx, fbc))
+			require.True(t, MissesDeclarativeConfig(ctx, c))
+			require.NoError(t, LoadOrRebuild(ctx, c, fbc))
+			require.False(t, MissesDeclarativeConfig(ctx, c))
+			require.NoError(t, sendFBC(c))
+			rebuiltDigest, err := c.Digest(ctx)
+			require.NoError(t, err)
+			require.Equal(t, digest, rebuiltDigest)
+			require.NoError(t, c.Close())
+
+			// An update without the option does not leave out of date
+			// content behind.
+			c, err = New(dir, WithFormat(format), WithLog(log.Null()))
+			require.NoError(t, err)
+			defer c.Close()
+			require.NoError(t, c.Build(ctx, updated))
+			require.NoError(t, c.CheckIntegrity(ctx, updated))
+			require.ErrorIs(t, sendFBC(c), ErrFBCNotCached)
+		})
+	}
+}
+
+func TestCache_InterruptedIncrementalBuild(t *testing.T) {
+	ctx := context.Background()
+	valid := fstest.MapFS{"foo.json": &fstest.MapFile{Data: []byte(incrementalTestPackage("foo", "1.0.0"))}}
+	invalid := fstest.MapFS{"foo.json": &fstest.MapFile{Data: []byte(`{"schema": "olm.channel", "package": "foo", "name": "stable"}`)}}
+
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			c, err := New(t.TempDir(), WithFormat(format), WithLog(logrus.NewEntry(logger)))
+			require.NoError(t, err)
+			defer c.Close()
+			require.NoError(t, c.Build(ctx, valid))
+
+			// A failed update leaves the cache invalid, and the next build
+			// starts from scratch.
+			require.Error(t, c.Build(ctx, invalid))
+			require.Error(t, c.CheckIntegrity(ctx, valid))
+
+			hook.Reset()
+			require.NoError(t, c.Build(ctx, valid))
+			require.Equal(t, "building cache", hook.LastEntry().Message)
+			require.NoError(t, c.CheckIntegrity(ctx, valid))
+		})
+	}
+}
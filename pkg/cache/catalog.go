@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+var _ registry.CatalogQuery = &cache{}
+
+func (c *cache) SendPackages(ctx context.Context, sender func(*apiv2.Package) error) error {
+	for _, name := range slices.Sorted(maps.Keys(c.packageIndex)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pkg := c.packageIndex[name]
+		p := &apiv2.Package{
+			Name:               pkg.Name,
+			Description:        pkg.Description,
+			DefaultChannelName: pkg.DefaultChannel,
+			ChannelNames:       slices.Sorted(maps.Keys(pkg.Channels)),
+		}
+		if pkg.Icon != nil {
+			p.Icon = &apiv2.Icon{Data: pkg.Icon.Data, MediaType: pkg.Icon.MediaType}
+		}
+		if err := sender(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cache) SendChannels(ctx context.Context, packageName string, sender func(*apiv2.Channel) error) error {
+	packageNames := slices.Sorted(maps.Keys(c.packageIndex))
+	if packageName != "" {
+		if _, ok := c.packageIndex[packageName]; !ok {
+			return fmt.Errorf("%w: %q", registry.ErrPackageNotInDatabase, packageName)
+		}
+		packageNames = []string{packageName}
+	}
+	for _, name := range packageNames {
+		pkg := c.packageIndex[name]
+		for _, chName := range slices.Sorted(maps.Keys(pkg.Channels)) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			ch := pkg.Channels[chName]
+			channel := &apiv2.Channel{
+				PackageName: pkg.Name,
+				Name:        ch.Name,
+				Entries:     make([]*apiv2.ChannelEntry, 0, len(ch.Bundles)),
+			}
+			for _, bName := range slices.Sorted(maps.Keys(ch.Bundles)) {
+				b := ch.Bundles[bName]
+				// The package index does not hold skipRanges, so they are read
+				// from the stored bundles.
+				apiBundle, err := c.backend.GetBundle(ctx, bundleKey{b.Package, b.Channel, b.Name})
+				if err != nil {
+					return fmt.Errorf("get bundle %q: %v", b.Name, err)
+				}
+				channel.Entries = append(channel.Entries, &apiv2.ChannelEntry{
+					Name:      b.Name,
+					Replaces:  b.Replaces,
+					Skips:     b.Skips,
+					SkipRange: apiBundle.GetSkipRange(),
+				})
+			}
+			if err := sender(channel); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetDeprecations reads bundle deprecations from the stored bundles, since the
+// package index only holds those of packages and channels.
+func (c *cache) GetDeprecations(ctx context.Context, packageName string) (*apiv2.Deprecations, error) {
+	pkg, ok := c.packageIndex[packageName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", registry.ErrPackageNotInDatabase, packageName)
+	}
+	deprecations := &apiv2.Deprecations{PackageName: pkg.Name}
+	addEntry := func(schema, name, message string) {
+		deprecations.Entries = append(deprecations.Entries, &apiv2.DeprecationEntry{
+			Reference: &apiv2.PackageScopedReference{Schema: schema, Name: name},
+			Message:   message,
+		})
+	}
+	if pkg.Deprecation != nil {
+		addEntry(declcfg.SchemaPackage, "", pkg.Deprecation.Message)
+	}
+	bundleMessages := map[string]string{}
+	seen := sets.New[string]()
+	for _, chName := range slices.Sorted(maps.Keys(pkg.Channels)) {
+		ch := pkg.Channels[chName]
+		if ch.Deprecation != nil {
+			addEntry(declcfg.SchemaChannel, ch.Name, ch.Deprecation.Message)
+		}
+		for _, b := range ch.Bundles {
+			if seen.Has(b.Name) {
+				continue
+			}
+			seen.Insert(b.Name)
+			apiBundle, err := c.backend.GetBundle(ctx, bundleKey{b.Package, b.Channel, b.Name})
+			if err != nil {
+				return nil, fmt.Errorf("get bundle %q: %v", b.Name, err)
+			}
+			if apiBundle.GetDeprecation() != nil {
+				bundleMessages[b.Name] = apiBundle.GetDeprecation().GetMessage()
+			}
+		}
+	}
+	for _, bName := range slices.Sorted(maps.Keys(bundleMessages)) {
+		addEntry(declcfg.SchemaBundle, bName, bundleMessages[bName])
+	}
+	return deprecations, nil
+}
+
+// SendCustomSchemaBlobs sends the custom schema metas stored in the cache,
+// in package, schema and name order, with the metas that do not belong to a
+// package sent first.
+func (c *cache) SendCustomSchemaBlobs(ctx context.Context, schema, packageName, name string, sender func(*apiv2.CustomSchemaBlob) error) error {
+	keys, err := c.backend.ListMetaKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("list custom schema metas: %v", err)
+	}
+	if packageName != "" {
+		_, found := c.packageIndex[packageName]
+		if !found && !slices.ContainsFunc(keys, func(k metaKey) bool { return k.PackageName == packageName }) {
+			return fmt.Errorf("%w: %q", registry.ErrPackageNotInDatabase, packageName)
+		}
+	}
+	keys = slices.DeleteFunc(keys, func(k metaKey) bool {
+		return (schema != "" && k.Schema != schema) || (packageName != "" && k.PackageName != packageName)
+	})
+	slices.SortFunc(keys, func(a, b metaKey) int {
+		return cmp.Or(cmp.Compare(a.PackageName, b.PackageName), cmp.Compare(a.Schema, b.Schema))
+	})
+	for _, key := range keys {
+		var blobs []*apiv2.CustomSchemaBlob
+		if err := c.backend.SendMetas(ctx, key, func(blob *structpb.Struct) error {
+			blobName := blob.GetFields()["name"].GetStringValue()
+			if name == "" || blobName == name {
+				blobs = append(blobs, &apiv2.CustomSchemaBlob{
+					Schema:      key.Schema,
+					PackageName: key.PackageName,
+					Name:        blobName,
+					Blob:        blob,
+				})
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		slices.SortStableFunc(blobs, func(a, b *apiv2.CustomSchemaBlob) int { return cmp.Compare(a.Name, b.Name) })
+		for _, blob := range blobs {
+			if err := sender(blob); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
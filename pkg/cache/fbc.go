@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+// ErrFBCNotCached is returned by SendDeclarativeConfig when the cache was
+// built without WithDeclarativeConfig, or by a version of opm that did not
+// store declarative config content.
+var ErrFBCNotCached = errors.New("cache does not contain declarative config content; rebuild the cache with declarative config content to serve it")
+
+// ErrPackageNotFound is returned by SendDeclarativeConfig when the requested
+// package is not in the cache.
+var ErrPackageNotFound = errors.New("package not found")
+
+// FBCFilter selects the declarative config objects sent by
+// SendDeclarativeConfig. Empty fields match everything.
+type FBCFilter struct {
+	// Package selects the objects of a single package.
+	Package string
+	// Schema selects the objects with the given schema.
+	Schema string
+}
+
+func (f FBCFilter) matches(meta *declcfg.Meta) bool {
+	return f.Schema == "" || meta.Schema == f.Schema
+}
+
+// fbcEntry is a declarative config object read during a cache build, whose
+// blob is held in the build's temporary file.
+type fbcEntry struct {
+	schema string
+	name   string
+	blob   *io.SectionReader
+}
+
+// packageSource is the declarative config of a package read during a cache
+// build. Its content is loaded from the build's temporary file when needed
+// and dropped afterward, so that the content of the whole catalog is never
+// held in memory at once.
+type packageSource struct {
+	entries []fbcEntry
+	// digest identifies the package's content for incremental cache builds.
+	digest string
+}
+
+// hasCoreSchemas reports whether the package has any olm.package, olm.channel,
+// olm.bundle or olm.deprecations objects.
+func (p *packageSource) hasCoreSchemas() bool {
+	return slices.ContainsFunc(p.entries, func(e fbcEntry) bool { return isCoreSchema(e.schema) })
+}
+
+// packageContent is the declarative config content of a package, which is
+// stored for SendDeclarativeConfig by caches created with
+// WithDeclarativeConfig.
+type packageContent struct {
+	// data is the package's objects in a canonical order (packages,
+	// channels, bundles, deprecations, then other schemas, each sorted by
+	// name) so that it does not depend on the order in which the source
+	// files were read.
+	data   []byte
+	metas  []packageMeta
+	digest string
+}
+
+type packageMeta struct {
+	schema string
+	name   string
+	// blob is the part of packageContent.data holding the object.
+	blob []byte
+}
+
+// load reads the package's content from the build's temporary file.
+func (p *packageSource) load() (*packageContent, error) {
+	metas := make([]packageMeta, 0, len(p.entries))
+	size := 0
+	for _, e := range p.entries {
+		blob := make([]byte, e.blob.Size())
+		if _, err := e.blob.ReadAt(blob, 0); err != nil {
+			return nil, err
+		}
+		metas = append(metas, packageMeta{schema: e.schema, name: e.name, blob: blob})
+		size += len(blob) + 1
+	}
+	slices.SortFunc(metas, func(a, b packageMeta) int {
+		return cmp.Or(
+			cmp.Compare(schemaRank(a.schema), schemaRank(b.schema)),
+			cmp.Compare(a.schema, b.schema),
+			cmp.Compare(a.name, b.name),
+			bytes.Compare(a.blob, b.blob),
+		)
+	})
+
+	data := make([]byte, 0, size)
+	for i, m := range metas {
+		start := len(data)
+		data = append(data, m.blob...)
+		metas[i].blob = data[start:len(data):len(data)]
+		if !bytes.HasSuffix(m.blob, []byte("\n")) {
+			data = append(data, '\n')
+		}
+	}
+	return &packageContent{
+		data:   data,
+		metas:  metas,
+		digest: fmt.Sprintf("%x", sha256.Sum256(data)),
+	}, nil
+}
+
+// coreSchemaReader returns a reader of the package's olm.package, olm.channel,
+// olm.bundle and olm.deprecations objects.
+func (p *packageContent) coreSchemaReader() io.Reader {
+	readers := make([]io.Reader, 0, len(p.metas))
+	for _, m := range p.metas {
+		if isCoreSchema(m.schema) {
+			readers = append(readers, bytes.NewReader(m.blob))
+		}
+	}
+	return io.MultiReader(readers...)
+}
+
+func isCoreSchema(schema string) bool {
+	return schemaRank(schema) < schemaRank("")
+}
+
+func schemaRank(schema string) int {
+	switch schema {
+	case declcfg.SchemaPackage:
+		return 0
+	case declcfg.SchemaChannel:
+		return 1
+	case declcfg.SchemaBundle:
+		return 2
+	case declcfg.SchemaDeprecation:
+		return 3
+	}
+	return 4
+}
+
+// SendDeclarativeConfig sends the declarative config objects selected by
+// filter, exactly as they were read when the cache was built. Objects are
+// sent one package at a time, in package name order, with objects that do
+// not belong to a package sent first.
+func (c *cache) SendDeclarativeConfig(ctx context.Context, filter FBCFilter, sender func(*declcfg.Meta) error) error {
+	packageNames, err := c.fbcPackageNames(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, packageName := range packageNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := c.backend.GetFBC(ctx, packageName)
+		if err != nil {
+			return fmt.Errorf("read declarative config for package %q: %v", packageName, err)
+		}
+		if err := declcfg.WalkMetasReader(bytes.NewReader(data), func(meta *declcfg.Meta, err error) error {
+			if err != nil {
+				return err
+			}
+			if !filter.matches(meta) {
+				return nil
+			}
+			return sender(meta)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeclarativeConfigDigest returns a digest of the content of the packages
+// selected by filter, and of the schema selected by filter.
+func (c *cache) DeclarativeConfigDigest(ctx context.Context, filter FBCFilter) (string, error) {
+	packageNames, err := c.fbcPackageNames(ctx, filter)
+	if err != nil {
+		return "", err
+	}
+	digests, err := c.backend.GetPackageDigests(ctx)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "schema %q\n", filter.Schema)
+	for _, packageName := range packageNames {
+		digest, ok := digests[packageName]
+		if !ok {
+			// The cache was built without package digests, so the digest
+			// of the package is computed from its stored content, which is
+			// what the package digest would be.
+			data, err := c.backend.GetFBC(ctx, packageName)
+			if err != nil {
+				return "", fmt.Errorf("read declarative config for package %q: %v", packageName, err)
+			}
+			digest = fmt.Sprintf("%x", sha256.Sum256(data))
+		}
+		fmt.Fprintf(h, "package %q %s\n", packageName, digest)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// fbcPackageNames returns the names of the packages whose declarative config
+// is selected by filter, in the order they are sent.
+func (c *cache) fbcPackageNames(ctx context.Context, filter FBCFilter) ([]string, error) {
+	packageNames, err := c.backend.GetFBCPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Package == "" {
+		return packageNames, nil
+	}
+	if _, found := slices.BinarySearch(packageNames, filter.Package); !found {
+		return nil, fmt.Errorf("%w: %q", ErrPackageNotFound, filter.Package)
+	}
+	return []string{filter.Package}, nil
+}
@@ -31,9 +31,10 @@ const (
 	jsonCacheModeDir  = 0750
 	jsonCacheModeFile = 0640
 
-	jsonDigestFile   = "digest"
-	jsonDir          = "cache"
-	jsonPackagesFile = jsonDir + string(filepath.Separator) + "packages.json"
+	jsonDigestFile        = "digest"
+	jsonPackageDigestFile = "package-digests.json"
+	jsonDir               = "cache"
+	jsonPackagesFile      = jsonDir + string(filepath.Separator) + "packages.json"
 )
 
 type jsonBackend struct {
@@ -71,6 +72,12 @@ func (q *jsonBackend) Init() error {
 	if err := os.RemoveAll(filepath.Join(q.baseDir, jsonDigestFile)); err != nil {
 		return fmt.Errorf("failed to remove existing JSON digest file: %v", err)
 	}
+	if err := os.RemoveAll(filepath.Join(q.baseDir, jsonFBCDir)); err != nil {
+		return fmt.Errorf("failed to remove existing declarative config content: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(q.baseDir, jsonPackageDigestFile)); err != nil {
+		return fmt.Errorf("failed to remove existing JSON package digest file: %v", err)
+	}
 	q.bundles = newBundleKeys()
 	return nil
 }
@@ -143,6 +150,14 @@ func (q *jsonBackend) PutBundle(_ context.Context, key bundleKey, bundle *api.Bu
 	return nil
 }
 
+func (q *jsonBackend) DeleteBundle(_ context.Context, key bundleKey) error {
+	if err := os.Remove(q.bundleFile(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	q.bundles.Delete(key)
+	return nil
+}
+
 func (q *jsonBackend) metaDir(in metaKey) string {
 	return filepath.Join(q.baseDir, jsonDir, jsonMetasDir, in.Schema, in.PackageName)
 }
@@ -190,6 +205,160 @@ func (q *jsonBackend) SendMetas(ctx context.Context, key metaKey, sender func(*s
 	return nil
 }
 
+func (q *jsonBackend) ListMetaKeys(_ context.Context) ([]metaKey, error) {
+	schemas, err := os.ReadDir(filepath.Join(q.baseDir, jsonDir, jsonMetasDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var keys []metaKey
+	for _, schema := range schemas {
+		if !schema.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(q.metaDir(metaKey{Schema: schema.Name()}))
+		if err != nil {
+			return nil, err
+		}
+		hasPackageless := false
+		for _, entry := range entries {
+			switch {
+			case entry.IsDir():
+				keys = append(keys, metaKey{Schema: schema.Name(), PackageName: entry.Name()})
+			case filepath.Ext(entry.Name()) == ".json":
+				hasPackageless = true
+			}
+		}
+		if hasPackageless {
+			keys = append(keys, metaKey{Schema: schema.Name()})
+		}
+	}
+	return keys, nil
+}
+
+func (q *jsonBackend) DeleteMetas(_ context.Context, packageNames []string) error {
+	schemas, err := os.ReadDir(filepath.Join(q.baseDir, jsonDir, jsonMetasDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, schema := range schemas {
+		if !schema.IsDir() {
+			continue
+		}
+		for _, packageName := range packageNames {
+			dir := q.metaDir(metaKey{Schema: schema.Name(), PackageName: packageName})
+			if packageName != "" {
+				if err := os.RemoveAll(dir); err != nil {
+					return err
+				}
+				continue
+			}
+			// Metas that do not belong to a package are stored alongside
+			// the directories of the package metas.
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+					continue
+				}
+				if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Declarative config content is stored outside of jsonDir so that it is not
+// included in the cache digest. Including it would change the digest of every
+// existing cache, invalidating caches that predate it.
+const (
+	jsonFBCDir          = "fbc"
+	jsonFBCPackagesFile = "packages.json"
+	jsonFBCGlobalFile   = "global.jsonl"
+	jsonFBCPackagesDir  = "packages"
+)
+
+func (q *jsonBackend) PutFBCPackages(_ context.Context, packageNames []string) error {
+	data, err := json.Marshal(packageNames)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(q.baseDir, jsonFBCDir)
+	if err := os.MkdirAll(dir, jsonCacheModeDir); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, jsonFBCPackagesFile), data, jsonCacheModeFile)
+}
+
+func (q *jsonBackend) DeleteFBCPackages(_ context.Context) error {
+	if err := os.Remove(filepath.Join(q.baseDir, jsonFBCDir, jsonFBCPackagesFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (q *jsonBackend) GetFBCPackages(_ context.Context) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(q.baseDir, jsonFBCDir, jsonFBCPackagesFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrFBCNotCached
+		}
+		return nil, err
+	}
+	var packageNames []string
+	if err := json.Unmarshal(data, &packageNames); err != nil {
+		return nil, err
+	}
+	return packageNames, nil
+}
+
+func (q *jsonBackend) fbcFile(packageName string) string {
+	if packageName == "" {
+		return filepath.Join(q.baseDir, jsonFBCDir, jsonFBCGlobalFile)
+	}
+	return filepath.Join(q.baseDir, jsonFBCDir, jsonFBCPackagesDir, packageName+".jsonl")
+}
+
+func (q *jsonBackend) PutFBC(_ context.Context, packageName string, data []byte) error {
+	file := q.fbcFile(packageName)
+	if err := os.MkdirAll(filepath.Dir(file), jsonCacheModeDir); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, jsonCacheModeFile)
+}
+
+func (q *jsonBackend) GetFBC(_ context.Context, packageName string) ([]byte, error) {
+	data, err := os.ReadFile(q.fbcFile(packageName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (q *jsonBackend) DeleteFBC(_ context.Context, packageName string) error {
+	if err := os.Remove(q.fbcFile(packageName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (q *jsonBackend) PutPackageDigests(_ context.Context, digests map[string]string) error {
+	return writePackageDigestsFile(filepath.Join(q.baseDir, jsonPackageDigestFile), digests, jsonCacheModeFile)
+}
+
+func (q *jsonBackend) GetPackageDigests(_ context.Context) (map[string]string, error) {
+	return readPackageDigestsFile(filepath.Join(q.baseDir, jsonPackageDigestFile))
+}
+
 func (q *jsonBackend) GetDigest(_ context.Context) (string, error) {
 	return readDigestFile(filepath.Join(q.baseDir, jsonDigestFile))
 }
@@ -215,11 +384,14 @@ func (q *jsonBackend) PutDigest(_ context.Context, digest string) error {
 	return writeDigestFile(filepath.Join(q.baseDir, jsonDigestFile), digest, jsonCacheModeFile)
 }
 
-func (q *jsonBackend) SendBundles(_ context.Context, s registry.BundleSender) error {
+func (q *jsonBackend) SendBundles(_ context.Context, filter registry.BundleFilter, s registry.BundleSender) error {
 	keys := make([]bundleKey, 0, q.bundles.Len())
 	files := make([]*os.File, 0, q.bundles.Len())
 	readers := make([]io.Reader, 0, q.bundles.Len())
 	if err := q.bundles.Walk(func(key bundleKey) error {
+		if !filter.MatchesEntry(key.PackageName, key.ChannelName) {
+			return nil
+		}
 		file, err := os.Open(q.bundleFile(key))
 		if err != nil {
 			return fmt.Errorf("failed to open file for package %q, channel %q, key %q: %w", key.PackageName, key.ChannelName, key.Name, err)
@@ -248,10 +420,13 @@ func (q *jsonBackend) SendBundles(_ context.Context, s registry.BundleSender) er
 		} else if err != nil {
 			return fmt.Errorf("failed to decode file for package %q, channel %q, key %q: %w", keys[index].PackageName, keys[index].ChannelName, keys[index].Name, err)
 		}
+		index += 1
+		if !filter.Matches(&bundle) {
+			continue
+		}
 		if err := s.Send(&bundle); err != nil {
 			return err
 		}
-		index += 1
 	}
 	return nil
 }
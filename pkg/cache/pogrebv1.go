@@ -13,11 +13,13 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/akrylysov/pogreb"
 	pogrebfs "github.com/akrylysov/pogreb/fs"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/pkg/api"
@@ -39,9 +41,10 @@ const (
 	pogrebV1CacheModeDir  = 0770
 	pogrebV1CacheModeFile = 0660
 
-	pograbV1CacheDir = FormatPogrebV1
-	pogrebDigestFile = pograbV1CacheDir + "/digest"
-	pogrebDBDir      = pograbV1CacheDir + "/db"
+	pograbV1CacheDir        = FormatPogrebV1
+	pogrebDigestFile        = pograbV1CacheDir + "/digest"
+	pogrebPackageDigestFile = pograbV1CacheDir + "/package-digests.json"
+	pogrebDBDir             = pograbV1CacheDir + "/db"
 )
 
 type pogrebV1Backend struct {
@@ -168,6 +171,14 @@ func (q *pogrebV1Backend) PutBundle(_ context.Context, key bundleKey, bundle *ap
 	return nil
 }
 
+func (q *pogrebV1Backend) DeleteBundle(_ context.Context, key bundleKey) error {
+	if err := q.db.Delete(q.dbKey(key)); err != nil {
+		return err
+	}
+	q.bundles.Delete(key)
+	return nil
+}
+
 func (q *pogrebV1Backend) metaDBKey(in metaKey) []byte {
 	return []byte(fmt.Sprintf("%s%s/%s", metaKeyPrefix, in.Schema, in.PackageName))
 }
@@ -228,6 +239,102 @@ func (q *pogrebV1Backend) SendMetas(ctx context.Context, key metaKey, sender fun
 	return nil
 }
 
+func (q *pogrebV1Backend) ListMetaKeys(_ context.Context) ([]metaKey, error) {
+	orderedKeys, err := q.orderedKeys()
+	if err != nil {
+		return nil, err
+	}
+	var keys []metaKey
+	for _, dbKey := range orderedKeys {
+		key, ok := strings.CutPrefix(dbKey, metaKeyPrefix)
+		if !ok {
+			continue
+		}
+		schema, packageName, _ := strings.Cut(key, "/")
+		keys = append(keys, metaKey{Schema: schema, PackageName: packageName})
+	}
+	return keys, nil
+}
+
+func (q *pogrebV1Backend) DeleteMetas(_ context.Context, packageNames []string) error {
+	packages := sets.New(packageNames...)
+	orderedKeys, err := q.orderedKeys()
+	if err != nil {
+		return err
+	}
+	for _, dbKey := range orderedKeys {
+		// Meta keys are "metas/<schema>/<package>", or "metas/<schema>/" for
+		// metas that do not belong to a package.
+		key, ok := strings.CutPrefix(dbKey, metaKeyPrefix)
+		if !ok {
+			continue
+		}
+		if _, packageName, _ := strings.Cut(key, "/"); !packages.Has(packageName) {
+			continue
+		}
+		if err := q.db.Delete([]byte(dbKey)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Declarative config content is stored under fbcKeyPrefix, which is excluded
+// from the cache digest. Including it would change the digest of every
+// existing cache, invalidating caches that predate it.
+const (
+	fbcKeyPrefix     = "fbc/"
+	fbcPackagesKey   = fbcKeyPrefix + "packages.json"
+	fbcPackagePrefix = fbcKeyPrefix + "package/"
+)
+
+func (q *pogrebV1Backend) PutFBCPackages(_ context.Context, packageNames []string) error {
+	data, err := json.Marshal(packageNames)
+	if err != nil {
+		return err
+	}
+	return q.db.Put([]byte(fbcPackagesKey), data)
+}
+
+func (q *pogrebV1Backend) GetFBCPackages(_ context.Context) ([]string, error) {
+	data, err := q.db.Get([]byte(fbcPackagesKey))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrFBCNotCached
+	}
+	var packageNames []string
+	if err := json.Unmarshal(data, &packageNames); err != nil {
+		return nil, err
+	}
+	return packageNames, nil
+}
+
+func (q *pogrebV1Backend) DeleteFBCPackages(_ context.Context) error {
+	return q.db.Delete([]byte(fbcPackagesKey))
+}
+
+func (q *pogrebV1Backend) PutFBC(_ context.Context, packageName string, data []byte) error {
+	return q.db.Put([]byte(fbcPackagePrefix+packageName), data)
+}
+
+func (q *pogrebV1Backend) GetFBC(_ context.Context, packageName string) ([]byte, error) {
+	return q.db.Get([]byte(fbcPackagePrefix + packageName))
+}
+
+func (q *pogrebV1Backend) DeleteFBC(_ context.Context, packageName string) error {
+	return q.db.Delete([]byte(fbcPackagePrefix + packageName))
+}
+
+func (q *pogrebV1Backend) PutPackageDigests(_ context.Context, digests map[string]string) error {
+	return writePackageDigestsFile(filepath.Join(q.baseDir, pogrebPackageDigestFile), digests, pogrebV1CacheModeFile)
+}
+
+func (q *pogrebV1Backend) GetPackageDigests(_ context.Context) (map[string]string, error) {
+	return readPackageDigestsFile(filepath.Join(q.baseDir, pogrebPackageDigestFile))
+}
+
 func (q *pogrebV1Backend) GetDigest(_ context.Context) (string, error) {
 	return readDigestFile(filepath.Join(q.baseDir, pogrebDigestFile))
 }
@@ -285,6 +392,9 @@ func (q *pogrebV1Backend) ComputeDigest(ctx context.Context, fbcFsys fs.FS) (str
 		return "", err
 	}
 	for _, dbKey := range orderedKeys {
+		if strings.HasPrefix(dbKey, fbcKeyPrefix) {
+			continue
+		}
 		if err := q.writeKeyValue(computedHasher, []byte(dbKey)); err != nil {
 			return "", err
 		}
@@ -296,8 +406,11 @@ func (q *pogrebV1Backend) PutDigest(_ context.Context, digest string) error {
 	return writeDigestFile(filepath.Join(q.baseDir, pogrebDigestFile), digest, pogrebV1CacheModeFile)
 }
 
-func (q *pogrebV1Backend) SendBundles(_ context.Context, s registry.BundleSender) error {
+func (q *pogrebV1Backend) SendBundles(_ context.Context, filter registry.BundleFilter, s registry.BundleSender) error {
 	return q.bundles.Walk(func(key bundleKey) error {
+		if !filter.MatchesEntry(key.PackageName, key.ChannelName) {
+			return nil
+		}
 		bundleData, err := q.db.Get(q.dbKey(key))
 		if err != nil {
 			return fmt.Errorf("failed to get data for package %q, channel %q, key %q: %w", key.PackageName, key.ChannelName, key.Name, err)
@@ -306,6 +419,9 @@ func (q *pogrebV1Backend) SendBundles(_ context.Context, s registry.BundleSender
 		if err := proto.Unmarshal(bundleData, &bundle); err != nil {
 			return fmt.Errorf("failed to decode data for package %q, channel %q, key %q: %w", key.PackageName, key.ChannelName, key.Name, err)
 		}
+		if !filter.Matches(&bundle) {
+			return nil
+		}
 		return s.Send(&bundle)
 	})
 }
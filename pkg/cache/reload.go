@@ -0,0 +1,404 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/lib/log"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// Reloadable serves queries from a Cache that can be replaced while queries
+// are in flight. Queries that started before a swap complete against the
+// cache they started with; that cache is closed once they have finished.
+type Reloadable struct {
+	current atomic.Pointer[generation]
+}
+
+type generation struct {
+	cache Cache
+
+	// mu is held for reading by in-flight queries and for writing while
+	// the cache is closed, so that a cache is never closed under a query.
+	mu     sync.RWMutex
+	closed bool
+}
+
+var (
+	_ registry.GRPCQuery    = &Reloadable{}
+	_ registry.CatalogQuery = &Reloadable{}
+)
+
+// NewReloadable returns a Reloadable that initially serves from c, which
+// must already be loaded.
+func NewReloadable(c Cache) *Reloadable {
+	r := &Reloadable{}
+	r.current.Store(&generation{cache: c})
+	return r
+}
+
+// Swap replaces the served cache with c, which must already be loaded. The
+// previous cache is closed after all queries using it have completed.
+func (r *Reloadable) Swap(c Cache) error {
+	old := r.current.Swap(&generation{cache: c})
+	old.mu.Lock()
+	defer old.mu.Unlock()
+	old.closed = true
+	return old.cache.Close()
+}
+
+// Close closes the currently served cache.
+func (r *Reloadable) Close() error {
+	g := r.current.Load()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return nil
+	}
+	g.closed = true
+	return g.cache.Close()
+}
+
+// acquire returns the current generation, held for reading. The caller must
+// release it with g.mu.RUnlock.
+func (r *Reloadable) acquire() *generation {
+	for {
+		g := r.current.Load()
+		g.mu.RLock()
+		if !g.closed {
+			return g
+		}
+		// Swapped and closed between the load and the lock; retry with the
+		// new generation.
+		g.mu.RUnlock()
+	}
+}
+
+// View calls fn with the cache that is currently being served. The cache is
+// not closed while fn is running, even if it is swapped out, so several
+// queries made by fn see consistent content.
+func (r *Reloadable) View(fn func(Cache) error) error {
+	g := r.acquire()
+	defer g.mu.RUnlock()
+	return fn(g.cache)
+}
+
+func withCache[T any](r *Reloadable, fn func(Cache) (T, error)) (T, error) {
+	g := r.acquire()
+	defer g.mu.RUnlock()
+	return fn(g.cache)
+}
+
+func (r *Reloadable) Digest(ctx context.Context) (string, error) {
+	return withCache(r, func(c Cache) (string, error) { return c.Digest(ctx) })
+}
+
+func (r *Reloadable) PackageDigests(ctx context.Context) (map[string]string, error) {
+	return withCache(r, func(c Cache) (map[string]string, error) { return c.PackageDigests(ctx) })
+}
+
+func (r *Reloadable) ListPackages(ctx context.Context) ([]string, error) {
+	return withCache(r, func(c Cache) ([]string, error) { return c.ListPackages(ctx) })
+}
+
+func (r *Reloadable) SendBundles(ctx context.Context, stream registry.BundleSender) error {
+	_, err := withCache(r, func(c Cache) (struct{}, error) { return struct{}{}, c.SendBundles(ctx, stream) })
+	return err
+}
+
+func (r *Reloadable) SendFilteredBundles(ctx context.Context, filter registry.BundleFilter, stream registry.BundleSender) error {
+	_, err := withCache(r, func(c Cache) (struct{}, error) { return struct{}{}, c.SendFilteredBundles(ctx, filter, stream) })
+	return err
+}
+
+func (r *Reloadable) ListBundles(ctx context.Context) ([]*api.Bundle, error) {
+	return withCache(r, func(c Cache) ([]*api.Bundle, error) { return c.ListBundles(ctx) })
+}
+
+func (r *Reloadable) GetPackage(ctx context.Context, name string) (*registry.PackageManifest, error) {
+	return withCache(r, func(c Cache) (*registry.PackageManifest, error) { return c.GetPackage(ctx, name) })
+}
+
+func (r *Reloadable) GetBundle(ctx context.Context, pkgName, channelName, csvName string) (*api.Bundle, error) {
+	return withCache(r, func(c Cache) (*api.Bundle, error) { return c.GetBundle(ctx, pkgName, channelName, csvName) })
+}
+
+func (r *Reloadable) GetBundleForChannel(ctx context.Context, pkgName string, channelName string) (*api.Bundle, error) {
+	return withCache(r, func(c Cache) (*api.Bundle, error) { return c.GetBundleForChannel(ctx, pkgName, channelName) })
+}
+
+func (r *Reloadable) GetChannelEntriesThatReplace(ctx context.Context, name string) ([]*registry.ChannelEntry, error) {
+	return withCache(r, func(c Cache) ([]*registry.ChannelEntry, error) { return c.GetChannelEntriesThatReplace(ctx, name) })
+}
+
+func (r *Reloadable) GetBundleThatReplaces(ctx context.Context, name, pkgName, channelName string) (*api.Bundle, error) {
+	return withCache(r, func(c Cache) (*api.Bundle, error) { return c.GetBundleThatReplaces(ctx, name, pkgName, channelName) })
+}
+
+func (r *Reloadable) GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	return withCache(r, func(c Cache) ([]*registry.ChannelEntry, error) {
+		return c.GetChannelEntriesThatProvide(ctx, group, version, kind)
+	})
+}
+
+func (r *Reloadable) GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	return withCache(r, func(c Cache) ([]*registry.ChannelEntry, error) {
+		return c.GetLatestChannelEntriesThatProvide(ctx, group, version, kind)
+	})
+}
+
+func (r *Reloadable) GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error) {
+	return withCache(r, func(c Cache) (*api.Bundle, error) { return c.GetBundleThatProvides(ctx, group, version, kind) })
+}
+
+func (r *Reloadable) ListPackageCustomSchemas(ctx context.Context, schema, packageName string, sender func(*structpb.Struct) error) error {
+	_, err := withCache(r, func(c Cache) (struct{}, error) {
+		return struct{}{}, c.ListPackageCustomSchemas(ctx, schema, packageName, sender)
+	})
+	return err
+}
+
+func (r *Reloadable) SendPackages(ctx context.Context, sender func(*apiv2.Package) error) error {
+	return r.View(func(c Cache) error { return c.SendPackages(ctx, sender) })
+}
+
+func (r *Reloadable) SendChannels(ctx context.Context, packageName string, sender func(*apiv2.Channel) error) error {
+	return r.View(func(c Cache) error { return c.SendChannels(ctx, packageName, sender) })
+}
+
+func (r *Reloadable) GetDeprecations(ctx context.Context, packageName string) (*apiv2.Deprecations, error) {
+	return withCache(r, func(c Cache) (*apiv2.Deprecations, error) { return c.GetDeprecations(ctx, packageName) })
+}
+
+func (r *Reloadable) SendCustomSchemaBlobs(ctx context.Context, schema, packageName, name string, sender func(*apiv2.CustomSchemaBlob) error) error {
+	return r.View(func(c Cache) error { return c.SendCustomSchemaBlobs(ctx, schema, packageName, name, sender) })
+}
+
+func (r *Reloadable) SendDeclarativeConfig(ctx context.Context, filter FBCFilter, sender func(*declcfg.Meta) error) error {
+	return r.View(func(c Cache) error { return c.SendDeclarativeConfig(ctx, filter, sender) })
+}
+
+func (r *Reloadable) DeclarativeConfigDigest(ctx context.Context, filter FBCFilter) (string, error) {
+	return withCache(r, func(c Cache) (string, error) { return c.DeclarativeConfigDigest(ctx, filter) })
+}
+
+// BuildFunc builds and loads a new Cache from a declarative config. current
+// is the cache being served, which is not closed until BuildFunc returns, so
+// that it can seed the new cache (see WithSeed).
+type BuildFunc func(ctx context.Context, fbc fs.FS, current Cache) (Cache, error)
+
+// BuildTempDirCache builds and loads a cache of fbc in a new temporary
+// directory, named after pattern as with os.MkdirTemp. The directory is
+// logged as the "cache" field, and is removed when the returned cache is
+// closed. The returned cache can still seed other caches (see WithSeed).
+func BuildTempDirCache(ctx context.Context, fbc fs.FS, pattern string, opts ...CacheOption) (Cache, error) {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	o := &CacheOptions{Log: log.Null()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	c, err := New(dir, append(slices.Clone(opts), WithLog(o.Log.WithField("cache", dir)))...)
+	if err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+	if err := c.Build(ctx, fbc); err != nil {
+		return nil, errors.Join(err, c.Close(), os.RemoveAll(dir))
+	}
+	if err := c.Load(ctx); err != nil {
+		return nil, errors.Join(err, c.Close(), os.RemoveAll(dir))
+	}
+	return &tempDirCache{Cache: c, dir: dir}, nil
+}
+
+// tempDirCache removes its cache directory when it is closed.
+type tempDirCache struct {
+	Cache
+	dir string
+}
+
+func (c *tempDirCache) Close() error {
+	return errors.Join(c.Cache.Close(), os.RemoveAll(c.dir))
+}
+
+// Unwrap returns the wrapped cache, so that it can seed other caches.
+func (c *tempDirCache) Unwrap() Cache {
+	return c.Cache
+}
+
+// Watcher polls a declarative config for changes and, when it has changed,
+// builds a new cache from it and swaps it into a Reloadable. If the new
+// cache cannot be built, the Reloadable continues to serve its current
+// cache.
+type Watcher struct {
+	target   *Reloadable
+	fbc      fs.FS
+	build    BuildFunc
+	interval time.Duration
+	log      *logrus.Entry
+	onReload []func(Cache)
+
+	// fingerprint identifies the state of fbc when it was last checked. It
+	// starts empty so that the first check always compares digests, which
+	// catches changes made while the initial cache was being built.
+	fingerprint string
+}
+
+type WatchOption func(*Watcher)
+
+// WithInterval sets how often the declarative config is checked for
+// changes. The default is 10 seconds.
+func WithInterval(interval time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+func WithWatchLog(log *logrus.Entry) WatchOption {
+	return func(w *Watcher) {
+		w.log = log
+	}
+}
+
+// WithOnReload adds a function that is called with the new cache each time
+// it is swapped in.
+func WithOnReload(fn func(Cache)) WatchOption {
+	return func(w *Watcher) {
+		w.onReload = append(w.onReload, fn)
+	}
+}
+
+// NewWatcher creates a Watcher that rebuilds target from fbc using build.
+// The cache served by target is expected to have been built from the
+// current contents of fbc.
+func NewWatcher(target *Reloadable, fbc fs.FS, build BuildFunc, opts ...WatchOption) *Watcher {
+	w := &Watcher{
+		target:   target,
+		fbc:      fbc,
+		build:    build,
+		interval: 10 * time.Second,
+		log:      log.Null(),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run checks for changes every interval until ctx is done. Errors are
+// logged rather than returned, so that a bad edit to the declarative config
+// does not stop the watcher.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Reload(ctx); err != nil {
+				w.log.WithError(err).Warn("failed to reload cache, continuing to serve previous content")
+			}
+		}
+	}
+}
+
+// Reload rebuilds and swaps the cache if the declarative config has changed
+// since it was last checked. It returns true if the cache was swapped.
+//
+// A build that fails is not retried until the declarative config changes
+// again.
+func (w *Watcher) Reload(ctx context.Context) (bool, error) {
+	fp, err := fsFingerprint(w.fbc)
+	if err != nil {
+		return false, fmt.Errorf("check declarative config for changes: %v", err)
+	}
+	if fp == w.fingerprint {
+		return false, nil
+	}
+	w.fingerprint = fp
+
+	// File metadata can change without content changing (e.g. a touch, or
+	// a configmap remount), so confirm with the cache's own digest before
+	// paying for a rebuild. The served cache is held until the new cache is
+	// built, so that the build can copy from it.
+	start := time.Now()
+	g := w.target.acquire()
+	c, oldDigest, err := w.rebuild(ctx, g.cache)
+	g.mu.RUnlock()
+	if err != nil || c == nil {
+		return false, err
+	}
+	newDigest, err := c.Digest(ctx)
+	if err != nil {
+		return false, errors.Join(fmt.Errorf("read new cache digest: %v", err), c.Close())
+	}
+	if err := w.target.Swap(c); err != nil {
+		w.log.WithError(err).Warn("failed to close previous cache")
+	}
+	w.log.WithFields(logrus.Fields{
+		"oldDigest": oldDigest,
+		"newDigest": newDigest,
+		"duration":  time.Since(start).String(),
+	}).Info("reloaded cache")
+	for _, fn := range w.onReload {
+		fn(c)
+	}
+	return true, nil
+}
+
+// rebuild builds a new cache if current was not built from the current
+// contents of the declarative config, returning it with the digest of
+// current. It returns a nil cache if current is up to date.
+func (w *Watcher) rebuild(ctx context.Context, current Cache) (Cache, string, error) {
+	if err := current.CheckIntegrity(ctx, w.fbc); err == nil {
+		return nil, "", nil
+	}
+	oldDigest, err := current.Digest(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("read current cache digest: %v", err)
+	}
+	w.log.WithField("digest", oldDigest).Info("declarative config changed, rebuilding cache")
+	c, err := w.build(ctx, w.fbc, current)
+	if err != nil {
+		return nil, "", fmt.Errorf("build cache: %v", err)
+	}
+	return c, oldDigest, nil
+}
+
+// fsFingerprint returns a cheap summary of the names, sizes and modification
+// times of the files in fsys, used to detect changes without reading file
+// contents.
+func fsFingerprint(fsys fs.FS) (string, error) {
+	h := sha256.New()
+	if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\n", path, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
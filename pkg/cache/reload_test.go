@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"io/fs"
+	"maps"
+	"os"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/lib/log"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatPogrebV1} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			build := func(ctx context.Context, fbc fs.FS, current Cache) (Cache, error) {
+				c, err := New(t.TempDir(), WithFormat(format), WithLog(log.Null()), WithSeed(current))
+				if err != nil {
+					return nil, err
+				}
+				if err := c.Build(ctx, fbc); err != nil {
+					return nil, err
+				}
+				return c, c.Load(ctx)
+			}
+
+			fbc := fstest.MapFS{}
+			maps.Copy(fbc, validFS)
+			delete(fbc, "etcd.json")
+
+			initial, err := build(ctx, fbc, nil)
+			require.NoError(t, err)
+			r := NewReloadable(initial)
+			defer r.Close()
+			w := NewWatcher(r, fbc, build)
+
+			// Unchanged config: the first check compares digests and does
+			// not rebuild.
+			swapped, err := w.Reload(ctx)
+			require.NoError(t, err)
+			require.False(t, swapped)
+			initialDigest, err := r.Digest(ctx)
+			require.NoError(t, err)
+
+			// Added package: the cache is rebuilt and swapped.
+			fbc["etcd.json"] = validFS["etcd.json"]
+			swapped, err = w.Reload(ctx)
+			require.NoError(t, err)
+			require.True(t, swapped)
+			pkgs, err := r.ListPackages(ctx)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"cockroachdb", "etcd"}, pkgs)
+			newDigest, err := r.Digest(ctx)
+			require.NoError(t, err)
+			require.NotEqual(t, initialDigest, newDigest)
+
+			// Invalid config: the build fails and the previous content is
+			// still served.
+			fbc["invalid.json"] = &fstest.MapFile{Data: []byte(`{"schema": "olm.bundle", "package": "missing"}`)}
+			swapped, err = w.Reload(ctx)
+			require.Error(t, err)
+			require.False(t, swapped)
+			pkgs, err = r.ListPackages(ctx)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"cockroachdb", "etcd"}, pkgs)
+
+			// The failed build is not retried until the config changes again.
+			swapped, err = w.Reload(ctx)
+			require.NoError(t, err)
+			require.False(t, swapped)
+		})
+	}
+}
+
+func TestBuildTempDirCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := BuildTempDirCache(ctx, validFS, "cache-test-", WithLog(log.Null()))
+	require.NoError(t, err)
+	dir := c.(*tempDirCache).dir
+	require.DirExists(t, dir)
+	pkgs, err := c.ListPackages(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"cockroachdb", "etcd"}, pkgs)
+
+	// The cache can seed another one.
+	require.NotNil(t, unwrapCache(c))
+	seeded, err := BuildTempDirCache(ctx, validFS, "cache-test-", WithLog(log.Null()), WithSeed(c))
+	require.NoError(t, err)
+	require.NoError(t, seeded.Close())
+
+	require.NoError(t, c.Close())
+	_, err = os.Stat(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReloadable_SwapWhileQuerying(t *testing.T) {
+	ctx := context.Background()
+	caches := genTestCaches(t, validFS)
+	r := NewReloadable(caches[FormatJSON])
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				_, err := r.ListBundles(ctx)
+				require.NoError(t, err)
+			}
+		}()
+	}
+	require.NoError(t, r.Swap(caches[FormatPogrebV1]))
+	wg.Wait()
+
+	bundles, err := r.ListBundles(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, bundles)
+}
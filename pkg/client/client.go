@@ -2,15 +2,22 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"io"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 )
 
 type Interface interface {
@@ -19,39 +26,54 @@ type Interface interface {
 	GetReplacementBundleInPackageChannel(ctx context.Context, currentName, packageName, channelName string) (*api.Bundle, error)
 	GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error)
 	ListBundles(ctx context.Context) (*BundleIterator, error)
+	ListBundlesWithOptions(ctx context.Context, req *api.ListBundlesRequest) (*BundleIterator, error)
 	GetPackage(ctx context.Context, packageName string) (*api.Package, error)
+	ListPackages(ctx context.Context) (*PackageNameIterator, error)
+	GetChannelEntriesThatReplace(ctx context.Context, csvName string) (*ChannelEntryIterator, error)
+	GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error)
+	GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error)
+	ListPackageCustomSchemas(ctx context.Context, schema, packageName string) (*CustomSchemaIterator, error)
+	ListCustomSchemaBlobs(ctx context.Context, schema, packageName, name string) (*CustomSchemaBlobIterator, error)
+	Watch(ctx context.Context) (*WatchIterator, error)
 	HealthCheck(ctx context.Context, reconnectTimeout time.Duration) (bool, error)
 	Close() error
 }
 
 type Client struct {
-	Registry api.RegistryClient
-	Health   grpc_health_v1.HealthClient
-	Conn     *grpc.ClientConn
+	Registry     api.RegistryClient
+	Experimental api.ExperimentalRegistryClient
+	Catalog      apiv2.CatalogClient
+	Health       grpc_health_v1.HealthClient
+	Conn         *grpc.ClientConn
 }
 
 var _ Interface = &Client{}
 
-type BundleStream interface {
-	Recv() (*api.Bundle, error)
+// Stream is the receiving side of a server stream.
+type Stream[T any] interface {
+	Recv() (T, error)
 }
 
-type BundleIterator struct {
-	stream BundleStream
+// Iterator iterates over the messages of a server stream. Next returns the
+// zero value once the stream has ended, after which Error returns the error
+// that ended it, or nil if the stream completed.
+type Iterator[T any] struct {
+	stream Stream[T]
 	error  error
 }
 
-func NewBundleIterator(stream BundleStream) *BundleIterator {
-	return &BundleIterator{stream: stream}
+func NewIterator[T any](stream Stream[T]) *Iterator[T] {
+	return &Iterator[T]{stream: stream}
 }
 
-func (it *BundleIterator) Next() *api.Bundle {
+func (it *Iterator[T]) Next() T {
+	var zero T
 	if it.error != nil {
-		return nil
+		return zero
 	}
 	next, err := it.stream.Recv()
 	if errors.Is(err, io.EOF) {
-		return nil
+		return zero
 	}
 	if err != nil {
 		it.error = err
@@ -59,10 +81,44 @@ func (it *BundleIterator) Next() *api.Bundle {
 	return next
 }
 
-func (it *BundleIterator) Error() error {
+func (it *Iterator[T]) Error() error {
 	return it.error
 }
 
+type (
+	BundleIterator           = Iterator[*api.Bundle]
+	PackageNameIterator      = Iterator[*api.PackageName]
+	ChannelEntryIterator     = Iterator[*api.ChannelEntry]
+	CustomSchemaIterator     = Iterator[*structpb.Struct]
+	CustomSchemaBlobIterator = Iterator[*apiv2.CustomSchemaBlob]
+	WatchIterator            = Iterator[*apiv2.WatchEvent]
+)
+
+type BundleStream interface {
+	Recv() (*api.Bundle, error)
+}
+
+func NewBundleIterator(stream BundleStream) *BundleIterator {
+	return NewIterator[*api.Bundle](stream)
+}
+
+type WatchEventStream interface {
+	Recv() (*apiv2.WatchEvent, error)
+}
+
+func NewWatchIterator(stream WatchEventStream) *WatchIterator {
+	return NewIterator[*apiv2.WatchEvent](stream)
+}
+
+// newIterator returns an iterator over stream, or err if the call that
+// opened the stream failed.
+func newIterator[T any](stream Stream[T], err error) (*Iterator[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	return NewIterator(stream), nil
+}
+
 func (c *Client) GetBundle(ctx context.Context, packageName, channelName, csvName string) (*api.Bundle, error) {
 	return c.Registry.GetBundle(ctx, &api.GetBundleRequest{PkgName: packageName, ChannelName: channelName, CsvName: csvName})
 }
@@ -81,7 +137,13 @@ func (c *Client) GetBundleThatProvides(ctx context.Context, group, version, kind
 }
 
 func (c *Client) ListBundles(ctx context.Context) (*BundleIterator, error) {
-	stream, err := c.Registry.ListBundles(ctx, &api.ListBundlesRequest{})
+	return c.ListBundlesWithOptions(ctx, &api.ListBundlesRequest{})
+}
+
+// ListBundlesWithOptions lists the bundles selected by the filters of req,
+// limited to the fields in its field mask.
+func (c *Client) ListBundlesWithOptions(ctx context.Context, req *api.ListBundlesRequest) (*BundleIterator, error) {
+	stream, err := c.Registry.ListBundles(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +154,49 @@ func (c *Client) GetPackage(ctx context.Context, packageName string) (*api.Packa
 	return c.Registry.GetPackage(ctx, &api.GetPackageRequest{Name: packageName})
 }
 
+func (c *Client) ListPackages(ctx context.Context) (*PackageNameIterator, error) {
+	return newIterator(c.Registry.ListPackages(ctx, &api.ListPackageRequest{}))
+}
+
+func (c *Client) GetChannelEntriesThatReplace(ctx context.Context, csvName string) (*ChannelEntryIterator, error) {
+	return newIterator(c.Registry.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: csvName}))
+}
+
+func (c *Client) GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error) {
+	return newIterator(c.Registry.GetChannelEntriesThatProvide(ctx, &api.GetAllProvidersRequest{Group: group, Version: version, Kind: kind}))
+}
+
+func (c *Client) GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error) {
+	return newIterator(c.Registry.GetLatestChannelEntriesThatProvide(ctx, &api.GetLatestProvidersRequest{Group: group, Version: version, Kind: kind}))
+}
+
+// ListPackageCustomSchemas lists the blobs of a custom schema, optionally
+// limited to one package, with the experimental ExperimentalRegistry
+// service. The service may change or be removed without notice.
+func (c *Client) ListPackageCustomSchemas(ctx context.Context, schema, packageName string) (*CustomSchemaIterator, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-acknowledge-experimental", "true")
+	return newIterator(c.Experimental.ExperimentalListPackageCustomSchemas(ctx, &api.ExperimentalListPackageCustomSchemasRequest{Schema: schema, PackageName: packageName}))
+}
+
+// ListCustomSchemaBlobs lists the blobs of schemas other than the olm.*
+// schemas. Empty arguments match all blobs.
+func (c *Client) ListCustomSchemaBlobs(ctx context.Context, schema, packageName, name string) (*CustomSchemaBlobIterator, error) {
+	return newIterator(c.Catalog.ListCustomSchemaBlobs(ctx, &apiv2.ListCustomSchemaBlobsRequest{Schema: schema, PackageName: packageName, Name: name}))
+}
+
+// Watch follows changes to the catalog served by the registry. The first
+// event has the digest of the catalog being served, and each later event
+// describes a change to it. The call ends when ctx is done; if the server
+// ends it because the client fell behind, Error returns a status with code
+// Aborted and the catalog should be watched again.
+func (c *Client) Watch(ctx context.Context) (*WatchIterator, error) {
+	stream, err := c.Catalog.Watch(ctx, &apiv2.WatchRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return NewWatchIterator(stream), nil
+}
+
 func (c *Client) Close() error {
 	if c.Conn == nil {
 		return nil
@@ -117,9 +222,124 @@ func (c *Client) HealthCheck(ctx context.Context, reconnectTimeout time.Duration
 	return true, nil
 }
 
-func NewClient(address string) (*Client, error) {
+type ClientOptions struct {
+	// TLSConfig, if set, is used to connect to the server over TLS.
+	TLSConfig *tls.Config
+	// CAFile, CertFile and KeyFile configure TLS when TLSConfig is not set.
+	// The server is verified against the system roots and the CAs in CAFile,
+	// and the certificate in CertFile and KeyFile, if set, is presented to
+	// servers that require mutual TLS.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	// UseTLS connects over TLS, verifying the server against the system
+	// roots, even if no other TLS option is set.
+	UseTLS bool
+
+	// Retry, if set, retries calls that fail with a retryable status.
+	Retry *RetryPolicy
+	// CallTimeout, if positive, is the deadline of calls whose context has
+	// no earlier one. It does not apply to Watch.
+	CallTimeout time.Duration
+
+	DialOptions []grpc.DialOption
+}
+
+type ClientOption func(*ClientOptions)
+
+// WithTLSConfig connects to the server over TLS using config.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *ClientOptions) {
+		o.TLSConfig = config
+	}
+}
+
+// WithTLS connects to the server over TLS, verifying it against the system
+// roots.
+func WithTLS() ClientOption {
+	return func(o *ClientOptions) {
+		o.UseTLS = true
+	}
+}
+
+// WithCAFile connects to the server over TLS, verifying it against the
+// system roots and the CAs in caFile.
+func WithCAFile(caFile string) ClientOption {
+	return func(o *ClientOptions) {
+		o.CAFile = caFile
+	}
+}
+
+// WithClientCertificate connects to the server over TLS, presenting the
+// certificate and key in certFile and keyFile for mutual TLS. The files are
+// reloaded when they change.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(o *ClientOptions) {
+		o.CertFile = certFile
+		o.KeyFile = keyFile
+	}
+}
+
+// WithRetry retries calls that fail with one of the retryable codes of
+// policy, backing off between attempts.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(o *ClientOptions) {
+		o.Retry = &policy
+	}
+}
+
+// WithCallTimeout sets the deadline of calls whose context has no earlier
+// one. Streaming calls must be read to the end within the deadline. Watch
+// calls are not limited.
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.CallTimeout = timeout
+	}
+}
+
+// WithDialOptions adds options used to dial the server.
+func WithDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *ClientOptions) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+func (o *ClientOptions) transportCredentials() (credentials.TransportCredentials, error) {
+	if o.TLSConfig != nil {
+		return credentials.NewTLS(o.TLSConfig), nil
+	}
+	if !o.UseTLS && o.CAFile == "" && o.CertFile == "" && o.KeyFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	config, err := certs.ClientTLSConfig(o.CAFile, o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// NewClient returns a client for the registry server at address. By
+// default the connection is not encrypted; use the TLS options to connect
+// to servers that require TLS.
+func NewClient(address string, opts ...ClientOption) (*Client, error) {
+	o := &ClientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	creds, err := o.transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if o.Retry != nil || o.CallTimeout > 0 {
+		serviceConfig, err := o.serviceConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
 	// nolint:staticcheck
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	conn, err := grpc.Dial(address, append(dialOpts, o.DialOptions...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +348,10 @@ func NewClient(address string) (*Client, error) {
 
 func NewClientFromConn(conn *grpc.ClientConn) *Client {
 	return &Client{
-		Registry: api.NewRegistryClient(conn),
-		Health:   grpc_health_v1.NewHealthClient(conn),
-		Conn:     conn,
+		Registry:     api.NewRegistryClient(conn),
+		Experimental: api.NewExperimentalRegistryClient(conn),
+		Catalog:      apiv2.NewCatalogClient(conn),
+		Health:       grpc_health_v1.NewHealthClient(conn),
+		Conn:         conn,
 	}
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
 )
 
 type RegistryClientStub struct {
@@ -73,6 +75,31 @@ func (s *RegistryClientStub) Watch(ctx context.Context, in *grpc_health_v1.Healt
 	return nil, nil
 }
 
+type CatalogClientStub struct {
+	apiv2.CatalogClient
+	WatchClient apiv2.Catalog_WatchClient
+	Error       error
+}
+
+func (s *CatalogClientStub) Watch(ctx context.Context, in *apiv2.WatchRequest, opts ...grpc.CallOption) (apiv2.Catalog_WatchClient, error) {
+	return s.WatchClient, s.Error
+}
+
+type WatchEventReceiverStub struct {
+	Events []*apiv2.WatchEvent
+	Error  error
+	grpc.ClientStream
+}
+
+func (s *WatchEventReceiverStub) Recv() (*apiv2.WatchEvent, error) {
+	if len(s.Events) == 0 {
+		return nil, s.Error
+	}
+	event := s.Events[0]
+	s.Events = s.Events[1:]
+	return event, nil
+}
+
 type BundleReceiverStub struct {
 	Bundle *api.Bundle
 	Error  error
@@ -178,3 +205,49 @@ func TestGetPackage(t *testing.T) {
 		})
 	}
 }
+
+func TestWatch(t *testing.T) {
+	events := []*apiv2.WatchEvent{
+		{Digest: "1"},
+		{Digest: "2", AddedPackages: []string{"a"}},
+	}
+	for _, tt := range []struct {
+		Name      string
+		Stub      *CatalogClientStub
+		Expected  []*apiv2.WatchEvent
+		CallError error
+		RecvError error
+	}{
+		{
+			Name:     "events",
+			Stub:     &CatalogClientStub{WatchClient: &WatchEventReceiverStub{Events: events, Error: io.EOF}},
+			Expected: events,
+		},
+		{
+			Name:      "recv error",
+			Stub:      &CatalogClientStub{WatchClient: &WatchEventReceiverStub{Events: events[:1], Error: errors.New("test error")}},
+			Expected:  events[:1],
+			RecvError: errors.New("test error"),
+		},
+		{
+			Name:      "call error",
+			Stub:      &CatalogClientStub{Error: errors.New("test error")},
+			CallError: errors.New("test error"),
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			c := Client{Catalog: tt.Stub}
+			it, err := c.Watch(context.TODO())
+			require.Equal(t, tt.CallError, err)
+			if err != nil {
+				return
+			}
+			var actual []*apiv2.WatchEvent
+			for event := it.Next(); event != nil; event = it.Next() {
+				actual = append(actual, event)
+			}
+			require.Equal(t, tt.Expected, actual)
+			require.Equal(t, tt.RecvError, it.Error())
+		})
+	}
+}
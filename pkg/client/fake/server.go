@@ -0,0 +1,99 @@
+// Package fake provides an in-process registry server, so that clients of
+// the registry API can be tested without a running catalog.
+package fake
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// Server serves a declarative config with the same services as `opm serve`,
+// on a local port.
+type Server struct {
+	grpcServer *grpc.Server
+	listener   net.Listener
+	reloadable *cache.Reloadable
+	notifier   *server.ChangeNotifier
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewServer starts serving the declarative config in fbc. The server must be
+// closed with Close.
+func NewServer(ctx context.Context, fbc fs.FS) (*Server, error) {
+	c, err := cache.BuildTempDirCache(ctx, fbc, "operator-registry-fake-server-")
+	if err != nil {
+		return nil, err
+	}
+	reloadable := cache.NewReloadable(c)
+	notifier, err := server.NewChangeNotifier(ctx, reloadable)
+	if err != nil {
+		return nil, errors.Join(err, reloadable.Close())
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Join(err, reloadable.Close())
+	}
+
+	s := &Server{
+		grpcServer: grpc.NewServer(),
+		listener:   lis,
+		reloadable: reloadable,
+		notifier:   notifier,
+	}
+	api.RegisterRegistryServer(s.grpcServer, server.NewRegistryServer(reloadable))
+	api.RegisterExperimentalRegistryServer(s.grpcServer, server.NewExperimentalRegistryServer(reloadable))
+	apiv2.RegisterCatalogServer(s.grpcServer, server.NewCatalogServer(reloadable, server.WithChangeNotifier(notifier)))
+	health.RegisterHealthServer(s.grpcServer, server.NewHealthServer())
+	go func() { _ = s.grpcServer.Serve(lis) }()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Client returns a client connected to the server.
+func (s *Server) Client(opts ...client.ClientOption) (*client.Client, error) {
+	return client.NewClient(s.Addr(), opts...)
+}
+
+// Update replaces the served declarative config with fbc, as `opm serve
+// --watch` does when its declarative config changes, and sends the change to
+// Watch calls.
+func (s *Server) Update(ctx context.Context, fbc fs.FS) error {
+	c, err := cache.BuildTempDirCache(ctx, fbc, "operator-registry-fake-server-")
+	if err != nil {
+		return err
+	}
+	// The new cache is served even if the previous one fails to close, so
+	// watchers are notified either way.
+	swapErr := s.reloadable.Swap(c)
+	return errors.Join(swapErr, s.notifier.Update(ctx, c))
+}
+
+// Close stops the server, ending any calls in flight, and removes its cache.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.grpcServer.Stop()
+	return s.reloadable.Close()
+}
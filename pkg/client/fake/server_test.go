@@ -0,0 +1,136 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+)
+
+const testCatalog = `{"schema": "olm.package", "name": "foo", "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": "foo", "name": "stable", "entries": [
+  {"name": "foo.v1.0.0"},
+  {"name": "foo.v1.1.0", "replaces": "foo.v1.0.0"}
+]}
+{"schema": "olm.bundle", "package": "foo", "name": "foo.v1.0.0", "image": "foo:v1.0.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "foo", "version": "1.0.0"}},
+  {"type": "olm.gvk", "value": {"group": "example.com", "version": "v1", "kind": "Foo"}}
+]}
+{"schema": "olm.bundle", "package": "foo", "name": "foo.v1.1.0", "image": "foo:v1.1.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "foo", "version": "1.1.0"}},
+  {"type": "olm.gvk", "value": {"group": "example.com", "version": "v1", "kind": "Foo"}}
+]}
+{"schema": "example.custom", "package": "foo", "name": "extra", "data": "value"}
+`
+
+const barCatalog = `{"schema": "olm.package", "name": "bar", "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": "bar", "name": "stable", "entries": [{"name": "bar.v1.0.0"}]}
+{"schema": "olm.bundle", "package": "bar", "name": "bar.v1.0.0", "image": "bar:v1.0.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "bar", "version": "1.0.0"}}
+]}
+`
+
+func collect[T any](t *testing.T, it *client.Iterator[T], err error) []T {
+	t.Helper()
+	require.NoError(t, err)
+	var (
+		out  []T
+		zero T
+	)
+	for next := it.Next(); any(next) != any(zero); next = it.Next() {
+		out = append(out, next)
+	}
+	require.NoError(t, it.Error())
+	return out
+}
+
+func entryNames(entries []*api.ChannelEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.GetBundleName())
+	}
+	return names
+}
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := NewServer(ctx, fstest.MapFS{"foo/catalog.json": {Data: []byte(testCatalog)}})
+	require.NoError(t, err)
+	defer s.Close()
+
+	c, err := s.Client(
+		client.WithRetry(client.RetryPolicy{MaxAttempts: 3, RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted}}),
+		client.WithCallTimeout(10*time.Second),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	healthy, err := c.HealthCheck(ctx, time.Second)
+	require.NoError(t, err)
+	require.True(t, healthy)
+
+	packages, err := c.ListPackages(ctx)
+	require.Equal(t, []string{"foo"}, func() []string {
+		var names []string
+		for _, p := range collect(t, packages, err) {
+			names = append(names, p.GetName())
+		}
+		return names
+	}())
+
+	replacements, err := c.GetChannelEntriesThatReplace(ctx, "foo.v1.0.0")
+	require.Equal(t, []string{"foo.v1.1.0"}, entryNames(collect(t, replacements, err)))
+
+	providers, err := c.GetChannelEntriesThatProvide(ctx, "example.com", "v1", "Foo")
+	require.ElementsMatch(t, []string{"foo.v1.0.0", "foo.v1.1.0"}, entryNames(collect(t, providers, err)))
+
+	latest, err := c.GetLatestChannelEntriesThatProvide(ctx, "example.com", "v1", "Foo")
+	require.Equal(t, []string{"foo.v1.1.0"}, entryNames(collect(t, latest, err)))
+
+	bundles, err := c.ListBundlesWithOptions(ctx, &api.ListBundlesRequest{PkgName: "foo", ChannelName: "stable"})
+	require.Len(t, collect(t, bundles, err), 2)
+
+	schemas, err := c.ListPackageCustomSchemas(ctx, "example.custom", "foo")
+	customSchemas := collect(t, schemas, err)
+	require.Len(t, customSchemas, 1)
+	require.Equal(t, "value", customSchemas[0].GetFields()["data"].GetStringValue())
+
+	blobs, err := c.ListCustomSchemaBlobs(ctx, "", "foo", "")
+	customBlobs := collect(t, blobs, err)
+	require.Len(t, customBlobs, 1)
+	require.Equal(t, "extra", customBlobs[0].GetName())
+
+	// Watch calls outlive the call timeout.
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	events, err := c.Watch(watchCtx)
+	require.NoError(t, err)
+	first := events.Next()
+	require.NoError(t, events.Error())
+	require.NotEmpty(t, first.GetDigest())
+
+	require.NoError(t, s.Update(ctx, fstest.MapFS{
+		"foo/catalog.json": {Data: []byte(testCatalog)},
+		"bar/catalog.json": {Data: []byte(barCatalog)},
+	}))
+	event := events.Next()
+	require.NoError(t, events.Error())
+	require.Equal(t, []string{"bar"}, event.GetAddedPackages())
+	require.Empty(t, event.GetUpdatedPackages())
+	require.NotEqual(t, first.GetDigest(), event.GetDigest())
+}
+
+func TestServerClose(t *testing.T) {
+	s, err := NewServer(context.Background(), fstest.MapFS{"foo/catalog.json": {Data: []byte(testCatalog)}})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+}
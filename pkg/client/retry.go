@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+)
+
+// RetryPolicy configures how calls that fail are retried. Retries are made
+// by gRPC, so streaming calls are only retried until the first message is
+// received.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts made for a call, including the
+	// first. gRPC makes at most 5 attempts.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the randomized delay before the
+	// first retry. It defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. It defaults to 5s.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the growth of the delay after each attempt. It
+	// defaults to 2.
+	BackoffMultiplier float64
+	// RetryableCodes are the status codes of failed calls that are retried.
+	// They default to Unavailable.
+	RetryableCodes []codes.Code
+}
+
+type serviceConfig struct {
+	MethodConfig []methodConfig `json:"methodConfig"`
+}
+
+type methodConfig struct {
+	Name        []methodName       `json:"name"`
+	Timeout     string             `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicyConfig `json:"retryPolicy,omitempty"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicyConfig struct {
+	MaxAttempts          int          `json:"maxAttempts"`
+	InitialBackoff       string       `json:"initialBackoff"`
+	MaxBackoff           string       `json:"maxBackoff"`
+	BackoffMultiplier    float64      `json:"backoffMultiplier"`
+	RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+}
+
+// serviceConfig returns the gRPC service config that applies the retry
+// policy and call timeout to the registry services.
+func (o *ClientOptions) serviceConfig() (string, error) {
+	var retry *retryPolicyConfig
+	if o.Retry != nil {
+		p := *o.Retry
+		if p.MaxAttempts < 2 {
+			return "", errors.New("retry policy must allow at least 2 attempts")
+		}
+		if p.InitialBackoff <= 0 {
+			p.InitialBackoff = 100 * time.Millisecond
+		}
+		if p.MaxBackoff <= 0 {
+			p.MaxBackoff = 5 * time.Second
+		}
+		if p.BackoffMultiplier <= 0 {
+			p.BackoffMultiplier = 2
+		}
+		if len(p.RetryableCodes) == 0 {
+			p.RetryableCodes = []codes.Code{codes.Unavailable}
+		}
+		retry = &retryPolicyConfig{
+			MaxAttempts:          p.MaxAttempts,
+			InitialBackoff:       durationString(p.InitialBackoff),
+			MaxBackoff:           durationString(p.MaxBackoff),
+			BackoffMultiplier:    p.BackoffMultiplier,
+			RetryableStatusCodes: p.RetryableCodes,
+		}
+	}
+
+	config := serviceConfig{MethodConfig: []methodConfig{{
+		Name: []methodName{
+			{Service: api.Registry_ServiceDesc.ServiceName},
+			{Service: api.ExperimentalRegistry_ServiceDesc.ServiceName},
+			{Service: apiv2.Catalog_ServiceDesc.ServiceName},
+		},
+		RetryPolicy: retry,
+	}}}
+	if o.CallTimeout > 0 {
+		config.MethodConfig[0].Timeout = durationString(o.CallTimeout)
+		// Watch calls last until they are cancelled, so only the more
+		// specific config of the method, which has no timeout, applies.
+		config.MethodConfig = append(config.MethodConfig, methodConfig{
+			Name:        []methodName{{Service: apiv2.Catalog_ServiceDesc.ServiceName, Method: "Watch"}},
+			RetryPolicy: retry,
+		})
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// durationString formats d as a JSON-encoded protobuf Duration.
+func durationString(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestServiceConfig(t *testing.T) {
+	const services = `"name":[{"service":"api.Registry"},{"service":"api.ExperimentalRegistry"},{"service":"api.v2.Catalog"}]`
+	for _, tt := range []struct {
+		name     string
+		options  ClientOptions
+		expected string
+		err      string
+	}{
+		{
+			name:     "RetryDefaults",
+			options:  ClientOptions{Retry: &RetryPolicy{MaxAttempts: 3}},
+			expected: `{"methodConfig":[{` + services + `,"retryPolicy":{"maxAttempts":3,"initialBackoff":"0.1s","maxBackoff":"5s","backoffMultiplier":2,"retryableStatusCodes":[14]}}]}`,
+		},
+		{
+			name: "Retry",
+			options: ClientOptions{Retry: &RetryPolicy{
+				MaxAttempts:       4,
+				InitialBackoff:    250 * time.Millisecond,
+				MaxBackoff:        time.Minute,
+				BackoffMultiplier: 1.5,
+				RetryableCodes:    []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+			}},
+			expected: `{"methodConfig":[{` + services + `,"retryPolicy":{"maxAttempts":4,"initialBackoff":"0.25s","maxBackoff":"60s","backoffMultiplier":1.5,"retryableStatusCodes":[14,4]}}]}`,
+		},
+		{
+			name:     "CallTimeout",
+			options:  ClientOptions{CallTimeout: 30 * time.Second},
+			expected: `{"methodConfig":[{` + services + `,"timeout":"30s"},{"name":[{"service":"api.v2.Catalog","method":"Watch"}]}]}`,
+		},
+		{
+			name:    "TooFewAttempts",
+			options: ClientOptions{Retry: &RetryPolicy{MaxAttempts: 1}},
+			err:     "retry policy must allow at least 2 attempts",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.options.serviceConfig()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, actual)
+		})
+	}
+}
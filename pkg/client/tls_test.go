@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// writeTestKeyPair writes a certificate for 127.0.0.1 and its key to dir,
+// signed by the given CA, or self-signed as a CA if ca is nil.
+func writeTestKeyPair(t *testing.T, dir, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		ca, caKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestNewClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeTestKeyPair(t, dir, "ca", nil, nil)
+	writeTestKeyPair(t, dir, "server", ca, caKey)
+	writeTestKeyPair(t, dir, "client", ca, caKey)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tlsConfig, err := certs.ServerTLSConfig(path("server.crt"), path("server.key"), path("ca.crt"))
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	grpc_health_v1.RegisterHealthServer(s, server.NewHealthServer())
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	type spec struct {
+		name        string
+		opts        []ClientOption
+		expectError bool
+	}
+	specs := []spec{
+		{
+			name: "MutualTLS",
+			opts: []ClientOption{WithCAFile(path("ca.crt")), WithClientCertificate(path("client.crt"), path("client.key"))},
+		},
+		{
+			name:        "NoClientCertificate",
+			opts:        []ClientOption{WithCAFile(path("ca.crt"))},
+			expectError: true,
+		},
+		{
+			name:        "UntrustedServer",
+			opts:        []ClientOption{WithTLS(), WithClientCertificate(path("client.crt"), path("client.key"))},
+			expectError: true,
+		},
+		{
+			name:        "Plaintext",
+			expectError: true,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			c, err := NewClient(lis.Addr().String(), s.opts...)
+			require.NoError(t, err)
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			healthy, err := c.HealthCheck(ctx, time.Second)
+			if s.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, healthy)
+		})
+	}
+}
+
+func TestNewClientInvalidTLSOptions(t *testing.T) {
+	_, err := NewClient("127.0.0.1:0", WithClientCertificate("tls.crt", ""))
+	require.Error(t, err)
+	_, err = NewClient("127.0.0.1:0", WithCAFile(filepath.Join(t.TempDir(), "missing.crt")))
+	require.Error(t, err)
+}
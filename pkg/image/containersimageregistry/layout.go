@@ -0,0 +1,125 @@
+package containersimageregistry
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	imgspec "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.podman.io/image/v5/oci/layout"
+	"go.podman.io/image/v5/types"
+
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// stagedImage is an OCI layout that an image is written to before it is
+// stored in the cache. The layout shares its blobs with the cache, so that
+// storing the image only adds it to the cache's index. This lets images be
+// written to the cache concurrently: the index, which is rewritten whenever
+// an image is stored, is the only state they share.
+type stagedImage struct {
+	dir string
+	// ref is the image in the staging layout, under the cache's layout key.
+	ref types.ImageReference
+	// sysCtx is the system context to write ref with.
+	sysCtx *types.SystemContext
+}
+
+func (r *Registry) stageImage(ref orimage.Reference) (*stagedImage, error) {
+	blobsDir := filepath.Join(r.cache.ociLayoutDir(), imgspecv1.ImageBlobsDir)
+	if err := os.MkdirAll(blobsDir, 0700); err != nil {
+		return nil, err
+	}
+	// The staging layout is next to the cache's layout, so that its blobs
+	// are moved into the cache rather than copied.
+	dir, err := os.MkdirTemp(r.cache.baseDir, "staging-")
+	if err != nil {
+		return nil, err
+	}
+	stagedRef, err := layout.NewReference(dir, layoutKey(ref.String()))
+	if err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+	sysCtx := r.cache.getSystemContext()
+	sysCtx.OCISharedBlobDirPath = blobsDir
+	return &stagedImage{dir: dir, ref: stagedRef, sysCtx: sysCtx}, nil
+}
+
+// storeStaged adds the staged image to the cache's index.
+func (r *Registry) storeStaged(s *stagedImage) error {
+	staged, err := readIndex(s.dir)
+	if err != nil {
+		return err
+	}
+
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+	layoutDir := r.cache.ociLayoutDir()
+	index, err := readIndex(layoutDir)
+	if errors.Is(err, os.ErrNotExist) {
+		index = &imgspecv1.Index{
+			Versioned: imgspec.Versioned{SchemaVersion: 2},
+			MediaType: imgspecv1.MediaTypeImageIndex,
+		}
+		layoutData, err := json.Marshal(imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(layoutDir, imgspecv1.ImageLayoutFile), layoutData, 0600); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	for _, desc := range staged.Manifests {
+		name := desc.Annotations[imgspecv1.AnnotationRefName]
+		index.Manifests = slices.DeleteFunc(index.Manifests, func(m imgspecv1.Descriptor) bool {
+			return m.Annotations[imgspecv1.AnnotationRefName] == name
+		})
+		index.Manifests = append(index.Manifests, desc)
+	}
+	return writeIndex(layoutDir, index)
+}
+
+// discard removes the staging layout.
+func (s *stagedImage) discard() error {
+	return os.RemoveAll(s.dir)
+}
+
+func readIndex(layoutDir string) (*imgspecv1.Index, error) {
+	data, err := os.ReadFile(filepath.Join(layoutDir, imgspecv1.ImageIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index imgspecv1.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// writeIndex replaces the index of the layout in layoutDir. The index is
+// renamed into place, so that it can be read while it is written.
+func writeIndex(layoutDir string, index *imgspecv1.Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(layoutDir, "index-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(layoutDir, imgspecv1.ImageIndexFile)); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return nil
+}
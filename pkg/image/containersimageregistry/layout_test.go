@@ -0,0 +1,43 @@
+package containersimageregistry
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
+)
+
+func TestStoreConcurrently(t *testing.T) {
+	ctx := context.Background()
+	r := newPackTestRegistry(t, packTestCtx(t))
+
+	const images = 8
+	ref := func(i int) orimage.Reference {
+		return orimage.SimpleReference(fmt.Sprintf("example.com/olmtest/packed:v%d", i))
+	}
+	var eg errgroup.Group
+	for i := range images {
+		layer := testLayer(t, map[string]string{"configs/catalog.json": "{}"})
+		eg.Go(func() error {
+			return r.Pack(ctx, ref(i), layer,
+				orimage.WithLabels(map[string]string{"example.com/image": fmt.Sprint(i)}),
+			)
+		})
+	}
+	require.NoError(t, eg.Wait())
+
+	// Every image is in the cache's index, and the staging layouts are gone.
+	for i := range images {
+		labels, err := r.Labels(ctx, ref(i))
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprint(i), labels["example.com/image"])
+	}
+	staging, err := filepath.Glob(filepath.Join(r.cache.baseDir, "staging-*"))
+	require.NoError(t, err)
+	require.Empty(t, staging)
+}
@@ -0,0 +1,264 @@
+package containersimageregistry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"runtime"
+
+	"github.com/opencontainers/go-digest"
+	imgspec "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.podman.io/image/v5/copy"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/oci/layout"
+	"go.podman.io/image/v5/pkg/blobinfocache/none"
+	"go.podman.io/image/v5/signature"
+	"go.podman.io/image/v5/types"
+
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
+)
+
+var _ orimage.Packer = (*Registry)(nil)
+
+// Pack creates an image from a single gzip-compressed layer and stores it in
+// the registry's OCI layout cache. The image configuration does not record a
+// creation time, so packing the same content on the same base image always
+// produces the same image.
+func (r *Registry) Pack(ctx context.Context, ref orimage.Reference, from io.Reader, opts ...orimage.PackOption) error {
+	o := orimage.PackOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config := imgspecv1.Image{
+		Platform: imgspecv1.Platform{
+			OS:           "linux",
+			Architecture: runtime.GOARCH,
+		},
+		RootFS: imgspecv1.RootFS{Type: "layers"},
+	}
+	var layers []imgspecv1.Descriptor
+	if o.Base != nil {
+		var err error
+		config, layers, err = r.baseImage(ctx, o.Base)
+		if err != nil {
+			return fmt.Errorf("could not load base image %q: %v", o.Base, err)
+		}
+	}
+
+	staged, err := r.stageImage(ref)
+	if err != nil {
+		return err
+	}
+	defer staged.discard() //nolint:errcheck
+	dest, err := staged.ref.NewImageDestination(ctx, staged.sysCtx)
+	if err != nil {
+		return fmt.Errorf("failed to create oci image destination: %v", err)
+	}
+	defer dest.Close()
+
+	layer, err := newLayerBlob(from)
+	if err != nil {
+		return fmt.Errorf("failed to create layer: %v", err)
+	}
+	defer layer.Close()
+	layerDesc := imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageLayerGzip,
+		Digest:    layer.digest,
+		Size:      layer.size,
+	}
+	if _, err := dest.PutBlob(ctx, layer.file, types.BlobInfo{Digest: layerDesc.Digest, Size: layerDesc.Size, MediaType: layerDesc.MediaType}, none.NoCache, false); err != nil {
+		return fmt.Errorf("failed to store layer: %v", err)
+	}
+	layers = append(layers, layerDesc)
+
+	config.Created = nil
+	config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, layer.diffID)
+	config.History = append(config.History, imgspecv1.History{CreatedBy: "opm"})
+	if len(o.Labels) > 0 {
+		if config.Config.Labels == nil {
+			config.Config.Labels = map[string]string{}
+		}
+		maps.Copy(config.Config.Labels, o.Labels)
+	}
+	if o.Entrypoint != nil {
+		config.Config.Entrypoint = o.Entrypoint
+	}
+	if o.Cmd != nil {
+		config.Config.Cmd = o.Cmd
+	}
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	configDesc := imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageConfig,
+		Digest:    digest.FromBytes(configData),
+		Size:      int64(len(configData)),
+	}
+	if _, err := dest.PutBlob(ctx, bytes.NewReader(configData), types.BlobInfo{Digest: configDesc.Digest, Size: configDesc.Size, MediaType: configDesc.MediaType}, none.NoCache, true); err != nil {
+		return fmt.Errorf("failed to store image config: %v", err)
+	}
+
+	manifestData, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: imgspec.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    layers,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dest.PutManifest(ctx, manifestData, nil); err != nil {
+		return fmt.Errorf("failed to store image manifest: %v", err)
+	}
+	if err := dest.Commit(ctx, nil); err != nil {
+		return err
+	}
+	return r.storeStaged(staged)
+}
+
+// baseImage returns the configuration and layers of a stored image.
+func (r *Registry) baseImage(ctx context.Context, base orimage.Reference) (imgspecv1.Image, []imgspecv1.Descriptor, error) {
+	ociLayoutRef, err := layout.NewReference(r.cache.ociLayoutDir(), layoutKey(base.String()))
+	if err != nil {
+		return imgspecv1.Image{}, nil, fmt.Errorf("could not create oci layout reference: %w", err)
+	}
+	img, err := ociLayoutRef.NewImage(ctx, r.cache.getSystemContext())
+	if err != nil {
+		return imgspecv1.Image{}, nil, fmt.Errorf("could not load image from oci image reference: %v", err)
+	}
+	defer img.Close()
+
+	config, err := img.OCIConfig(ctx)
+	if err != nil {
+		return imgspecv1.Image{}, nil, fmt.Errorf("could not get oci config from image: %v", err)
+	}
+	var layers []imgspecv1.Descriptor
+	for _, info := range img.LayerInfos() {
+		layers = append(layers, imgspecv1.Descriptor{
+			MediaType:   info.MediaType,
+			Digest:      info.Digest,
+			Size:        info.Size,
+			URLs:        info.URLs,
+			Annotations: info.Annotations,
+		})
+	}
+	return *config, layers, nil
+}
+
+// Push copies a stored image to the remote registry of its reference.
+func (r *Registry) Push(ctx context.Context, ref orimage.Reference) error {
+	namedRef, err := reference.ParseNamed(ref.String())
+	if err != nil {
+		return err
+	}
+	dockerRef, err := docker.NewReference(namedRef)
+	if err != nil {
+		return err
+	}
+	ociLayoutRef, err := layout.NewReference(r.cache.ociLayoutDir(), layoutKey(ref.String()))
+	if err != nil {
+		return fmt.Errorf("could not create oci layout reference: %w", err)
+	}
+
+	sysCtx := *r.sourceCtx
+	authFile := getAuthFile(r.sourceCtx, namedRef.Name())
+	if authFile != "" {
+		sysCtx.AuthFilePath = authFile
+	}
+	return r.copyFromCache(ctx, dockerRef, ociLayoutRef, &sysCtx)
+}
+
+// Export copies a stored image to the OCI image layout in dir, under name.
+func (r *Registry) Export(ctx context.Context, ref orimage.Reference, dir, name string) error {
+	destRef, err := layout.NewReference(dir, name)
+	if err != nil {
+		return fmt.Errorf("could not create oci layout reference: %w", err)
+	}
+	ociLayoutRef, err := layout.NewReference(r.cache.ociLayoutDir(), layoutKey(ref.String()))
+	if err != nil {
+		return fmt.Errorf("could not create oci layout reference: %w", err)
+	}
+	return r.copyFromCache(ctx, destRef, ociLayoutRef, &types.SystemContext{})
+}
+
+func (r *Registry) copyFromCache(ctx context.Context, destRef, srcRef types.ImageReference, destCtx *types.SystemContext) error {
+	policy, err := signature.DefaultPolicy(r.sourceCtx)
+	if err != nil {
+		return err
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return err
+	}
+	defer policyContext.Destroy() //nolint:errcheck
+
+	_, err = copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
+		SourceCtx:      r.cache.getSystemContext(),
+		DestinationCtx: destCtx,
+	})
+	return err
+}
+
+// layerBlob is a gzip-compressed layer stored in a temporary file.
+type layerBlob struct {
+	file   *os.File
+	size   int64
+	digest digest.Digest
+	diffID digest.Digest
+}
+
+func newLayerBlob(from io.Reader) (*layerBlob, error) {
+	file, err := os.CreateTemp("", "opm-layer-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	l := &layerBlob{file: file}
+	if err := l.write(from); err != nil {
+		return nil, errors.Join(err, l.Close())
+	}
+	return l, nil
+}
+
+func (l *layerBlob) write(from io.Reader) error {
+	compressedDigester := digest.Canonical.Digester()
+	uncompressedDigester := digest.Canonical.Digester()
+	counter := &countingWriter{}
+
+	gz := gzip.NewWriter(io.MultiWriter(l.file, compressedDigester.Hash(), counter))
+	if _, err := io.Copy(io.MultiWriter(gz, uncompressedDigester.Hash()), from); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	l.size = counter.n
+	l.digest = compressedDigester.Digest()
+	l.diffID = uncompressedDigester.Digest()
+	return nil
+}
+
+func (l *layerBlob) Close() error {
+	return errors.Join(l.file.Close(), os.Remove(l.file.Name()))
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,121 @@
+package containersimageregistry
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/oci/layout"
+	"go.podman.io/image/v5/types"
+
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// packTestCtx returns a hermetic SystemContext that accepts unsigned images.
+func packTestCtx(t *testing.T) *types.SystemContext {
+	t.Helper()
+	sourceCtx := hermeticCtx(t)
+	sourceCtx.SignaturePolicyPath = filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(sourceCtx.SignaturePolicyPath, []byte(`{"default":[{"type":"insecureAcceptAnything"}]}`), 0600))
+	return sourceCtx
+}
+
+func newPackTestRegistry(t *testing.T, sourceCtx *types.SystemContext) *Registry {
+	t.Helper()
+	r, err := New(sourceCtx, WithTemporaryImageCache())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, r.Destroy()) })
+	return r.(*Registry)
+}
+
+func testLayer(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func TestPackPushAndExport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The test registry stores pushed images in its root directory, so serve
+	// a copy of the golden images.
+	rootDir := t.TempDir()
+	require.NoError(t, os.CopyFS(rootDir, os.DirFS("../testdata/golden")))
+	sourceCtx := packTestCtx(t)
+	host := runTestRegistry(ctx, t, rootDir, sourceCtx)
+
+	base := orimage.SimpleReference(host + "/olmtest/kiali:1.4.2")
+	ref := orimage.SimpleReference(host + "/olmtest/packed:latest")
+
+	r := newPackTestRegistry(t, sourceCtx)
+	require.NoError(t, r.Pull(ctx, base))
+	require.NoError(t, r.Pack(ctx, ref, testLayer(t, map[string]string{"configs/catalog.json": "{}"}),
+		orimage.WithBase(base),
+		orimage.WithLabels(map[string]string{"operators.operatorframework.io.bundle.package.v1": "packed"}),
+		orimage.WithLabels(map[string]string{"example.com/label": "value"}),
+		orimage.WithEntrypoint("/bin/opm"),
+		orimage.WithCmd("serve", "/configs"),
+	))
+	require.NoError(t, r.Push(ctx, ref))
+
+	exportDir := t.TempDir()
+	require.NoError(t, r.Export(ctx, ref, exportDir, "packed"))
+	exportRef, err := layout.NewReference(exportDir, "packed")
+	require.NoError(t, err)
+	exported, err := exportRef.NewImage(ctx, &types.SystemContext{})
+	require.NoError(t, err)
+	defer exported.Close()
+	config, err := exported.OCIConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/bin/opm"}, config.Config.Entrypoint)
+	require.Equal(t, []string{"serve", "/configs"}, config.Config.Cmd)
+	require.Nil(t, config.Created)
+	// The golden kiali image has two layers.
+	require.Len(t, exported.LayerInfos(), 3)
+
+	// Pull the pushed image into a fresh cache to check that it is complete.
+	pulled := newPackTestRegistry(t, sourceCtx)
+	require.NoError(t, pulled.Pull(ctx, ref))
+	labels, err := pulled.Labels(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, "packed", labels["operators.operatorframework.io.bundle.package.v1"])
+	require.Equal(t, "value", labels["example.com/label"])
+	require.Equal(t, "registry+v1", labels["operators.operatorframework.io.bundle.mediatype.v1"])
+
+	unpackDir := t.TempDir()
+	require.NoError(t, pulled.Unpack(ctx, ref, unpackDir))
+	require.FileExists(t, filepath.Join(unpackDir, "configs", "catalog.json"))
+	require.DirExists(t, filepath.Join(unpackDir, "manifests"))
+}
+
+func TestPackIsReproducible(t *testing.T) {
+	ctx := context.Background()
+	digests := make([]string, 0, 2)
+	for range 2 {
+		r := newPackTestRegistry(t, packTestCtx(t))
+		ref := orimage.SimpleReference("example.com/olmtest/packed:latest")
+		require.NoError(t, r.Pack(ctx, ref, testLayer(t, map[string]string{"configs/catalog.json": "{}"})))
+
+		exportDir := t.TempDir()
+		require.NoError(t, r.Export(ctx, ref, exportDir, "packed"))
+		exportRef, err := layout.NewReference(exportDir, "packed")
+		require.NoError(t, err)
+		img, err := exportRef.NewImage(ctx, &types.SystemContext{})
+		require.NoError(t, err)
+		digests = append(digests, img.ConfigInfo().Digest.String())
+		require.NoError(t, img.Close())
+	}
+	require.Equal(t, digests[0], digests[1])
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/containerd/containerd/archive"
 	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/opencontainers/go-digest"
 	"go.podman.io/common/pkg/auth"
 	"go.podman.io/image/v5/copy"
 	"go.podman.io/image/v5/docker"
@@ -25,11 +27,17 @@ import (
 	orimage "github.com/operator-framework/operator-registry/pkg/image"
 )
 
-var _ orimage.Registry = (*Registry)(nil)
+var (
+	_ orimage.Registry = (*Registry)(nil)
+	_ orimage.Resolver = (*Registry)(nil)
+)
 
 type Registry struct {
 	sourceCtx *types.SystemContext
 	cache     *cacheConfig
+
+	// indexMu serializes updates of the index of the cache's OCI layout.
+	indexMu sync.Mutex
 }
 
 var DefaultSystemContext = &types.SystemContext{OSChoice: "linux"}
@@ -143,13 +151,11 @@ func (r *Registry) Pull(ctx context.Context, ref orimage.Reference) error {
 		return err
 	}
 
-	if err := os.MkdirAll(r.cache.ociLayoutDir(), 0700); err != nil {
-		return err
-	}
-	ociLayoutRef, err := layout.NewReference(r.cache.ociLayoutDir(), layoutKey(ref.String()))
+	staged, err := r.stageImage(ref)
 	if err != nil {
 		return err
 	}
+	defer staged.discard() //nolint:errcheck
 
 	policy, err := signature.DefaultPolicy(r.sourceCtx)
 	if err != nil {
@@ -166,9 +172,9 @@ func (r *Registry) Pull(ctx context.Context, ref orimage.Reference) error {
 		sysCtx.AuthFilePath = authFile
 	}
 
-	if _, err := copy.Image(ctx, policyContext, ociLayoutRef, dockerRef, &copy.Options{
+	if _, err := copy.Image(ctx, policyContext, staged.ref, dockerRef, &copy.Options{
 		SourceCtx:                             &sysCtx,
-		DestinationCtx:                        r.cache.getSystemContext(),
+		DestinationCtx:                        staged.sysCtx,
 		OptimizeDestinationImageAlreadyExists: true,
 
 		// We use the OCI layout as a temporary storage and
@@ -180,7 +186,28 @@ func (r *Registry) Pull(ctx context.Context, ref orimage.Reference) error {
 	}); err != nil {
 		return err
 	}
-	return nil
+	return r.storeStaged(staged)
+}
+
+func (r *Registry) Digest(ctx context.Context, ref orimage.Reference) (digest.Digest, error) {
+	namedRef, err := reference.ParseNamed(ref.String())
+	if err != nil {
+		return "", err
+	}
+	if canonical, ok := namedRef.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	dockerRef, err := docker.NewReference(namedRef)
+	if err != nil {
+		return "", err
+	}
+
+	sysCtx := *r.sourceCtx
+	authFile := getAuthFile(r.sourceCtx, namedRef.Name())
+	if authFile != "" {
+		sysCtx.AuthFilePath = authFile
+	}
+	return docker.GetDigest(ctx, &sysCtx, dockerRef)
 }
 
 func (r *Registry) Unpack(ctx context.Context, ref orimage.Reference, unpackDir string) error {
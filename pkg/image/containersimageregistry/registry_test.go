@@ -1,14 +1,20 @@
 package containersimageregistry
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.podman.io/image/v5/types"
+
+	libimage "github.com/operator-framework/operator-registry/internal/testutil/image"
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
 )
 
 func writeAuthFile(t *testing.T, dir, filename string, auths map[string]interface{}) string { //nolint:unparam
@@ -112,3 +118,46 @@ func TestGetAuthFile(t *testing.T) {
 		require.Equal(t, authPath, got)
 	})
 }
+
+// runTestRegistry serves the images in rootDir from a TLS registry until ctx
+// is done, configures sourceCtx to trust it, and returns its host.
+func runTestRegistry(ctx context.Context, t *testing.T, rootDir string, sourceCtx *types.SystemContext) string {
+	t.Helper()
+	dockerServer := libimage.RunDockerRegistry(ctx, rootDir)
+	t.Cleanup(dockerServer.Close)
+	serverURL, err := url.Parse(dockerServer.URL)
+	require.NoError(t, err)
+
+	caDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(caDir, "ca.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: dockerServer.Certificate().Raw}), 0600))
+	sourceCtx.DockerCertPath = caDir
+	sourceCtx.DockerPerHostCertDirPath = caDir
+	return serverURL.Host
+}
+
+func TestDigest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceCtx := packTestCtx(t)
+	host := runTestRegistry(ctx, t, "../testdata/golden", sourceCtx)
+	r, err := New(sourceCtx, WithTemporaryImageCache())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Destroy()) }()
+	resolver := r.(orimage.Resolver)
+
+	// A tag is resolved by the registry.
+	dgst, err := resolver.Digest(ctx, orimage.SimpleReference(host+"/olmtest/kiali:1.4.2"))
+	require.NoError(t, err)
+	require.NoError(t, dgst.Validate())
+
+	// A digest reference resolves to its own digest, and can be pulled.
+	pinned := orimage.SimpleReference(host + "/olmtest/kiali@" + dgst.String())
+	pinnedDigest, err := resolver.Digest(ctx, pinned)
+	require.NoError(t, err)
+	require.Equal(t, dgst, pinnedDigest)
+	require.NoError(t, r.Pull(ctx, pinned))
+
+	_, err = resolver.Digest(ctx, orimage.SimpleReference(host+"/olmtest/kiali:missing"))
+	require.Error(t, err)
+}
@@ -7,9 +7,14 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/opencontainers/go-digest"
 )
 
-var _ Registry = &MockRegistry{}
+var (
+	_ Registry = &MockRegistry{}
+	_ Resolver = &MockRegistry{}
+)
 
 type MockRegistry struct {
 	RemoteImages map[Reference]*MockImage
@@ -20,6 +25,7 @@ type MockRegistry struct {
 type MockImage struct {
 	Labels map[string]string
 	FS     fs.FS
+	Digest digest.Digest
 }
 
 func (i *MockImage) unpack(dir string) error {
@@ -77,6 +83,14 @@ func (m *MockRegistry) Labels(_ context.Context, ref Reference) (map[string]stri
 	return image.Labels, nil
 }
 
+func (m *MockRegistry) Digest(_ context.Context, ref Reference) (digest.Digest, error) {
+	image, ok := m.RemoteImages[ref]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return image.Digest, nil
+}
+
 func (m *MockRegistry) Destroy() error {
 	m.m.Lock()
 	defer m.m.Unlock()
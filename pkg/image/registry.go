@@ -2,18 +2,17 @@ package image
 
 import (
 	"context"
+	"io"
+
+	"github.com/opencontainers/go-digest"
 )
 
 // Registry knows how to Pull and Unpack Operator Bundle images to the filesystem.
-// Note: In the future, Registry will know how to Build and Push Operator Bundle images as well.
+// Registries that can also build and push images implement Packer.
 type Registry interface {
 	// Pull fetches and stores an image by reference.
 	Pull(ctx context.Context, ref Reference) error
 
-	// Push uploads an image to the remote registry of its reference.
-	// If the referenced image does not exist in the registry, an error is returned.
-	// Push(ctx context.Context, ref string) error
-
 	// Unpack writes the unpackaged content of an image to a directory.
 	// If the referenced image does not exist in the registry, an error is returned.
 	Unpack(ctx context.Context, ref Reference, dir string) error
@@ -23,9 +22,70 @@ type Registry interface {
 
 	// Destroy cleans up any on-disk resources used to track images
 	Destroy() error
+}
+
+// Packer knows how to build images and copy them out of a Registry.
+type Packer interface {
+	// Pack creates an image from a single layer, read from an uncompressed
+	// tar stream, and stores it by reference.
+	// If a base image is set, it must already be stored, and the layer is
+	// added on top of its layers. Otherwise, the image is created from scratch.
+	Pack(ctx context.Context, ref Reference, from io.Reader, opts ...PackOption) error
+
+	// Push uploads a stored image to the remote registry of its reference.
+	// If the referenced image is not stored, an error is returned.
+	Push(ctx context.Context, ref Reference) error
+
+	// Export writes a stored image to an OCI image layout directory, under
+	// the given name. Other images in the directory are preserved.
+	// If the referenced image is not stored, an error is returned.
+	Export(ctx context.Context, ref Reference, dir, name string) error
+}
+
+// Resolver knows how to look up the image that a reference currently points to.
+type Resolver interface {
+	// Digest returns the manifest digest that ref points to in its remote
+	// registry, without pulling the image.
+	Digest(ctx context.Context, ref Reference) (digest.Digest, error)
+}
+
+type PackOptions struct {
+	// Base is the image to add the layer to.
+	Base Reference
+	// Labels are added to the labels of the base image.
+	Labels map[string]string
+	// Entrypoint and Cmd, if set, replace those of the base image.
+	Entrypoint []string
+	Cmd        []string
+}
+
+type PackOption func(*PackOptions)
+
+func WithBase(base Reference) PackOption {
+	return func(o *PackOptions) {
+		o.Base = base
+	}
+}
+
+func WithLabels(labels map[string]string) PackOption {
+	return func(o *PackOptions) {
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+		for k, v := range labels {
+			o.Labels[k] = v
+		}
+	}
+}
+
+func WithEntrypoint(entrypoint ...string) PackOption {
+	return func(o *PackOptions) {
+		o.Entrypoint = entrypoint
+	}
+}
 
-	// Pack creates and stores an image based on the given reference and returns a reference to the new image.
-	// If the referenced image does not exist in the registry, a new image is created from scratch.
-	// If it exists, it's used as the base image.
-	// Pack(ctx context.Context, ref Reference, from io.Reader) (next string, err error)
+func WithCmd(cmd ...string) PackOption {
+	return func(o *PackOptions) {
+		o.Cmd = cmd
+	}
 }
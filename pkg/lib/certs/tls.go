@@ -0,0 +1,181 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeyPairReloader serves a certificate and key loaded from PEM files, and
+// reloads them when either file changes, so that rotated certificates are
+// picked up without a restart.
+//
+// The files are checked on every TLS handshake. If they cannot be loaded,
+// for instance because only one of them has been rewritten so far, the
+// previously loaded certificate continues to be served.
+type KeyPairReloader struct {
+	r *reloader[*tls.Certificate]
+}
+
+// NewKeyPairReloader loads the certificate and key in certFile and keyFile.
+func NewKeyPairReloader(certFile, keyFile string) (*KeyPairReloader, error) {
+	r := &reloader[*tls.Certificate]{
+		files: []string{certFile, keyFile},
+		load: func() (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load key pair %s, %s: %v", certFile, keyFile, err)
+			}
+			return &cert, nil
+		},
+	}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+	return &KeyPairReloader{r: r}, nil
+}
+
+// GetCertificate can be used as tls.Config.GetCertificate.
+func (k *KeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return k.r.get()
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate.
+func (k *KeyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return k.r.get()
+}
+
+// ServerTLSConfig returns a TLS configuration for a server that presents the
+// certificate and key in certFile and keyFile. If clientCAFile is set, clients
+// must present a certificate signed by one of the CAs in that file (mutual
+// TLS); the system roots are not trusted for client certificates.
+//
+// All files are reloaded when they change.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both a certificate and a key file are required")
+	}
+	keyPair, err := NewKeyPairReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: keyPair.GetCertificate,
+	}
+	if clientCAFile == "" {
+		return config, nil
+	}
+
+	clientCAs := &reloader[*x509.CertPool]{
+		files: []string{clientCAFile},
+		load: func() (*x509.CertPool, error) {
+			return certPool(clientCAFile)
+		},
+	}
+	if _, err := clientCAs.get(); err != nil {
+		return nil, err
+	}
+	base := config.Clone()
+	base.ClientAuth = tls.RequireAndVerifyClientCert
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := clientCAs.get()
+		if err != nil {
+			return nil, err
+		}
+		c := base.Clone()
+		c.ClientCAs = pool
+		return c, nil
+	}
+	return config, nil
+}
+
+// ClientTLSConfig returns a TLS configuration for a client that trusts the
+// system roots and the CAs in caFile, if set. If certFile and keyFile are set,
+// the client presents that certificate to servers that require mutual TLS,
+// reloading it when the files change.
+func ClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	rootCAs, err := RootCAs(caFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
+	}
+	if certFile == "" && keyFile == "" {
+		return config, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both a certificate and a key file are required for a client certificate")
+	}
+	keyPair, err := NewKeyPairReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config.GetClientCertificate = keyPair.GetClientCertificate
+	return config, nil
+}
+
+func certPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file %s: %v", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(data); !ok {
+		return nil, fmt.Errorf("unable to add certs specified in %s", caFile)
+	}
+	return pool, nil
+}
+
+// reloader holds a value loaded from a set of files, and loads it again
+// when the size or modification time of any of the files changes.
+type reloader[T any] struct {
+	files []string
+	load  func() (T, error)
+
+	mu     sync.Mutex
+	stamp  string
+	value  T
+	loaded bool
+}
+
+func (r *reloader[T]) get() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := fileStamp(r.files)
+	if err == nil && r.loaded && stamp == r.stamp {
+		return r.value, nil
+	}
+	if err == nil {
+		var value T
+		value, err = r.load()
+		if err == nil {
+			r.value, r.stamp, r.loaded = value, stamp, true
+			return value, nil
+		}
+	}
+	if r.loaded {
+		// Keep serving the last good value; the files are checked again
+		// on the next call.
+		return r.value, nil
+	}
+	return r.value, err
+}
+
+func fileStamp(files []string) (string, error) {
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", f, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,172 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signerCert, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestKeyPairReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	ca := newTestCert(t, "ca", nil)
+	first := newTestCert(t, "first", ca)
+	second := newTestCert(t, "second", ca)
+
+	start := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, first.certPEM, start)
+	writeFile(t, keyFile, first.keyPEM, start)
+
+	r, err := NewKeyPairReloader(certFile, keyFile)
+	require.NoError(t, err)
+	commonName := func() string {
+		t.Helper()
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	require.Equal(t, "first", commonName())
+
+	// Halfway through a rotation the certificate and key do not match; the
+	// previous key pair is served until both have been replaced.
+	writeFile(t, certFile, second.certPEM, start.Add(time.Second))
+	require.Equal(t, "first", commonName())
+
+	writeFile(t, keyFile, second.keyPEM, start.Add(time.Second))
+	require.Equal(t, "second", commonName())
+
+	// The key pair is also kept if the files disappear.
+	require.NoError(t, os.Remove(certFile))
+	require.Equal(t, "second", commonName())
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", nil)
+	serverCert := newTestCert(t, "server", ca)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, serverCert.certPEM, time.Now())
+	writeFile(t, keyFile, serverCert.keyPEM, time.Now())
+	writeFile(t, caFile, ca.certPEM, time.Now())
+
+	type spec struct {
+		name         string
+		certFile     string
+		keyFile      string
+		clientCAFile string
+		expectMTLS   bool
+		expectErr    bool
+	}
+	specs := []spec{
+		{
+			name:     "TLS",
+			certFile: certFile,
+			keyFile:  keyFile,
+		},
+		{
+			name:         "MutualTLS",
+			certFile:     certFile,
+			keyFile:      keyFile,
+			clientCAFile: caFile,
+			expectMTLS:   true,
+		},
+		{
+			name:      "MissingKey",
+			certFile:  certFile,
+			expectErr: true,
+		},
+		{
+			name:         "BadClientCA",
+			certFile:     certFile,
+			keyFile:      keyFile,
+			clientCAFile: keyFile,
+			expectErr:    true,
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			config, err := ServerTLSConfig(s.certFile, s.keyFile, s.clientCAFile)
+			if s.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			cert, err := config.GetCertificate(&tls.ClientHelloInfo{})
+			require.NoError(t, err)
+			require.NotNil(t, cert)
+			if !s.expectMTLS {
+				require.Nil(t, config.GetConfigForClient)
+				return
+			}
+			clientConfig, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+			require.NoError(t, err)
+			require.Equal(t, tls.RequireAndVerifyClientCert, clientConfig.ClientAuth)
+			require.NotNil(t, clientConfig.ClientCAs)
+		})
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"io/fs"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/lint"
 )
 
 // Validate takes a filesystem containing the declarative config file(s)
 // 1. Validate if declarative config file(s) are valid based on specified schema
 // 2. Validate the `replaces` chains of the upgrade graph
+// 3. Validate the blobs of custom schemas against their registered validators
 // Inputs:
 // directory: a filesystem where declarative config file(s) exist
 // Outputs:
@@ -24,9 +26,21 @@ func Validate(ctx context.Context, root fs.FS) error {
 	// This will convert declcfg objects to intermediate model objects that are
 	// also used for serve and add commands. The conversion process will run
 	// validation for the model objects and ensure they are valid.
-	_, err = declcfg.ConvertToModel(*cfg)
+	m, err := declcfg.ConvertToModel(*cfg)
 	if err != nil {
 		return err
 	}
-	return nil
+	// Blobs of custom schemas are not checked by the conversion, so that
+	// catalogs can be served regardless, but they must be valid here.
+	return declcfg.ValidateOthers(*cfg, m)
+}
+
+// Lint takes a filesystem containing the declarative config file(s) and runs
+// the rules of the given linter against them, configured by lintConfig.
+// Unlike Validate, problems found in the config are reported as findings in
+// the returned result rather than as an error. An error is only returned if
+// the config could not be loaded or the lint configuration is invalid.
+// Findings record the file, relative to root, of the object they apply to.
+func Lint(ctx context.Context, root fs.FS, linter *lint.Linter, lintConfig *lint.Config) (*lint.Result, error) {
+	return linter.LintFS(ctx, root, lintConfig)
 }
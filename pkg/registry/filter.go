@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+// FilteredBundleSender is implemented by stores that can select the bundles
+// they send, so that the bundles left out by the filter are never decoded.
+// Servers fall back to filtering the bundles sent by SendBundles for stores
+// that do not implement it.
+type FilteredBundleSender interface {
+	// Sends the bundles in the index that are selected by the filter
+	SendFilteredBundles(ctx context.Context, filter BundleFilter, stream BundleSender) error
+}
+
+// BundleFilter selects the bundles sent by SendFilteredBundles. The zero
+// value selects every bundle.
+type BundleFilter struct {
+	// PackageName, if set, selects only bundles of the package.
+	PackageName string
+
+	// ChannelName, if set, selects only bundles of the channel.
+	ChannelName string
+
+	// ExcludeDeprecated omits bundles that carry a deprecation, or the
+	// olm.deprecated property set by `opm index deprecatetruncate`.
+	ExcludeDeprecated bool
+}
+
+// MatchesEntry reports whether the filter selects bundles of the given
+// package and channel. Stores use it to skip bundles before decoding them.
+func (f BundleFilter) MatchesEntry(packageName, channelName string) bool {
+	return (f.PackageName == "" || f.PackageName == packageName) &&
+		(f.ChannelName == "" || f.ChannelName == channelName)
+}
+
+// Matches reports whether the filter selects the bundle.
+func (f BundleFilter) Matches(b *api.Bundle) bool {
+	if !f.MatchesEntry(b.GetPackageName(), b.GetChannelName()) {
+		return false
+	}
+	return !f.ExcludeDeprecated || !isDeprecatedBundle(b)
+}
+
+func isDeprecatedBundle(b *api.Bundle) bool {
+	if b.GetDeprecation() != nil {
+		return true
+	}
+	for _, p := range b.GetProperties() {
+		if p.GetType() == DeprecatedType {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
 )
 
 type Load interface {
@@ -59,6 +60,24 @@ type GRPCQuery interface {
 	GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error)
 }
 
+// CatalogQuery is the query interface of the api.v2.Catalog service. Methods
+// that are given the name of a package that is not in the index return an
+// error wrapping ErrPackageNotInDatabase.
+type CatalogQuery interface {
+	// Send each package in the index, with its metadata
+	SendPackages(ctx context.Context, sender func(*apiv2.Package) error) error
+
+	// Send the channels of a package, or of all packages if packageName is
+	// empty, with their entries
+	SendChannels(ctx context.Context, packageName string, sender func(*apiv2.Channel) error) error
+
+	// Get the deprecations of a package
+	GetDeprecations(ctx context.Context, packageName string) (*apiv2.Deprecations, error)
+
+	// Send the blobs of custom schemas that match the non-empty arguments
+	SendCustomSchemaBlobs(ctx context.Context, schema, packageName, name string, sender func(*apiv2.CustomSchemaBlob) error) error
+}
+
 type Query interface {
 	GRPCQuery
 
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	fbccache "github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// CatalogServer serves the api.v2.Catalog service.
+type CatalogServer struct {
+	apiv2.UnimplementedCatalogServer
+	store    registry.CatalogQuery
+	notifier *ChangeNotifier
+}
+
+var _ apiv2.CatalogServer = &CatalogServer{}
+
+type CatalogServerOption func(*CatalogServer)
+
+// WithChangeNotifier serves Watch calls with the events of notifier. Without
+// it, Watch is not supported.
+func WithChangeNotifier(notifier *ChangeNotifier) CatalogServerOption {
+	return func(s *CatalogServer) {
+		s.notifier = notifier
+	}
+}
+
+func NewCatalogServer(store registry.CatalogQuery, opts ...CatalogServerOption) *CatalogServer {
+	s := &CatalogServer{UnimplementedCatalogServer: apiv2.UnimplementedCatalogServer{}, store: store}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *CatalogServer) ListPackages(_ *apiv2.ListPackagesRequest, stream apiv2.Catalog_ListPackagesServer) error {
+	return catalogStatus(stream.Context(), s.store.SendPackages(stream.Context(), stream.Send))
+}
+
+func (s *CatalogServer) ListChannels(req *apiv2.ListChannelsRequest, stream apiv2.Catalog_ListChannelsServer) error {
+	return catalogStatus(stream.Context(), s.store.SendChannels(stream.Context(), req.GetPackageName(), stream.Send))
+}
+
+func (s *CatalogServer) GetDeprecations(ctx context.Context, req *apiv2.GetDeprecationsRequest) (*apiv2.Deprecations, error) {
+	if req.GetPackageName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "packageName is required")
+	}
+	deprecations, err := s.store.GetDeprecations(ctx, req.GetPackageName())
+	if err != nil {
+		return nil, catalogStatus(ctx, err)
+	}
+	return deprecations, nil
+}
+
+func (s *CatalogServer) ListCustomSchemaBlobs(req *apiv2.ListCustomSchemaBlobsRequest, stream apiv2.Catalog_ListCustomSchemaBlobsServer) error {
+	err := s.store.SendCustomSchemaBlobs(stream.Context(), req.GetSchema(), req.GetPackageName(), req.GetName(), stream.Send)
+	return catalogStatus(stream.Context(), err)
+}
+
+func (s *CatalogServer) Watch(_ *apiv2.WatchRequest, stream apiv2.Catalog_WatchServer) error {
+	if s.notifier == nil {
+		return status.Errorf(codes.Unimplemented, "this server does not report catalog changes")
+	}
+	current, events, stop := s.notifier.watch()
+	defer stop()
+	if err := stream.Send(current); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case event, ok := <-events:
+			if !ok {
+				return status.Errorf(codes.Aborted, "watch fell behind catalog changes, watch again to resume")
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// catalogStatus converts errors of the store to gRPC status errors. Errors
+// that already carry a status, such as those returned by stream.Send, are
+// returned unchanged.
+func catalogStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, registry.ErrPackageNotInDatabase):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, fbccache.ErrFBCNotCached):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
@@ -0,0 +1,364 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+)
+
+func catalogClient(t *testing.T, address string) (apiv2.CatalogClient, *grpc.ClientConn) {
+	// nolint:staticcheck
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("did not connect: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn.WaitForStateChange(ctx, connectivity.TransientFailure)
+
+	return apiv2.NewCatalogClient(conn), conn
+}
+
+// recvAll receives the messages of a stream until it ends.
+func recvAll[T any](recv func() (T, error)) ([]T, error) {
+	var out []T
+	for {
+		m, err := recv()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, m)
+	}
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	t.Helper()
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code(), st.Message())
+}
+
+func TestCatalogListPackages(t *testing.T) {
+	manifestPackages := []*apiv2.Package{
+		{Name: "etcd", DefaultChannelName: "alpha", ChannelNames: []string{"alpha", "beta", "stable"}, Icon: &apiv2.Icon{MediaType: "image/png"}},
+		{Name: "prometheus", DefaultChannelName: "preview", ChannelNames: []string{"preview"}, Icon: &apiv2.Icon{MediaType: "image/svg+xml"}},
+		{Name: "strimzi-kafka-operator", DefaultChannelName: "stable", ChannelNames: []string{"alpha", "beta", "stable"}, Icon: &apiv2.Icon{MediaType: "image/svg+xml"}},
+	}
+	for _, tt := range []struct {
+		name     string
+		addr     string
+		expected []*apiv2.Package
+		// described is set when every package is expected to have a
+		// description.
+		described bool
+	}{
+		{
+			name:      "Sqlite",
+			addr:      dbAddress,
+			expected:  manifestPackages,
+			described: true,
+		},
+		{
+			name:     "FBCCache",
+			addr:     cacheAddress,
+			expected: manifestPackages,
+		},
+		{
+			name: "FBCCacheWithCustomSchemas",
+			addr: customSchemaCacheAddress,
+			expected: []*apiv2.Package{
+				{Name: "testpkg", DefaultChannelName: "stable", ChannelNames: []string{"stable"}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, conn := catalogClient(t, tt.addr)
+			defer conn.Close()
+
+			stream, err := c.ListPackages(context.TODO(), &apiv2.ListPackagesRequest{})
+			require.NoError(t, err)
+			packages, err := recvAll(stream.Recv)
+			require.NoError(t, err)
+
+			// Icons are compared by media type only, and descriptions, which
+			// sqlite databases read from the default channel head's CSV, by
+			// presence only.
+			for _, p := range packages {
+				if p.GetIcon() != nil {
+					require.NotEmpty(t, p.GetIcon().GetData())
+					p.Icon.Data = nil
+				}
+				if tt.described {
+					require.NotEmpty(t, p.GetDescription(), p.GetName())
+					p.Description = ""
+				}
+			}
+			require.Empty(t, cmp.Diff(tt.expected, packages, protocmp.Transform()))
+		})
+	}
+}
+
+func TestCatalogListChannels(t *testing.T) {
+	etcdChannels := []*apiv2.Channel{
+		{PackageName: "etcd", Name: "alpha", Entries: []*apiv2.ChannelEntry{
+			{Name: "etcdoperator.v0.6.1"},
+			{Name: "etcdoperator.v0.9.0", Replaces: "etcdoperator.v0.6.1"},
+			{Name: "etcdoperator.v0.9.2", Replaces: "etcdoperator.v0.9.0", Skips: []string{"etcdoperator.v0.9.1"}, SkipRange: "< 0.6.0"},
+		}},
+		{PackageName: "etcd", Name: "beta", Entries: []*apiv2.ChannelEntry{
+			{Name: "etcdoperator.v0.6.1"},
+			{Name: "etcdoperator.v0.9.0", Replaces: "etcdoperator.v0.6.1"},
+		}},
+		{PackageName: "etcd", Name: "stable", Entries: []*apiv2.ChannelEntry{
+			{Name: "etcdoperator.v0.6.1"},
+			{Name: "etcdoperator.v0.9.0", Replaces: "etcdoperator.v0.6.1"},
+			{Name: "etcdoperator.v0.9.2", Replaces: "etcdoperator.v0.9.0", Skips: []string{"etcdoperator.v0.9.1"}, SkipRange: "< 0.6.0"},
+		}},
+	}
+	for _, tt := range []struct {
+		name        string
+		addr        string
+		req         *apiv2.ListChannelsRequest
+		expected    []*apiv2.Channel
+		wantCount   int
+		wantErrCode codes.Code
+	}{
+		{
+			name:     "Sqlite/Package",
+			addr:     dbAddress,
+			req:      &apiv2.ListChannelsRequest{PackageName: "etcd"},
+			expected: etcdChannels,
+		},
+		{
+			name:      "Sqlite/All",
+			addr:      dbAddress,
+			req:       &apiv2.ListChannelsRequest{},
+			wantCount: 7,
+		},
+		{
+			name:        "Sqlite/UnknownPackage",
+			addr:        dbAddress,
+			req:         &apiv2.ListChannelsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+		{
+			name:     "FBCCache/Package",
+			addr:     cacheAddress,
+			req:      &apiv2.ListChannelsRequest{PackageName: "etcd"},
+			expected: etcdChannels,
+		},
+		{
+			name:      "FBCCache/All",
+			addr:      cacheAddress,
+			req:       &apiv2.ListChannelsRequest{},
+			wantCount: 7,
+		},
+		{
+			name:        "FBCCache/UnknownPackage",
+			addr:        cacheAddress,
+			req:         &apiv2.ListChannelsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+		{
+			name: "FBCCacheWithDeprecations/SkipRange",
+			addr: deprecationCacheAddress,
+			req:  &apiv2.ListChannelsRequest{PackageName: "cockroachdb"},
+			expected: []*apiv2.Channel{
+				{PackageName: "cockroachdb", Name: "stable-5.x", Entries: []*apiv2.ChannelEntry{
+					{Name: "cockroachdb.v5.0.3"},
+					{Name: "cockroachdb.v5.0.4", Replaces: "cockroachdb.v5.0.3"},
+				}},
+				{PackageName: "cockroachdb", Name: "stable-v6.x", Entries: []*apiv2.ChannelEntry{
+					{Name: "cockroachdb.v6.0.0", SkipRange: "<6.0.0"},
+				}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, conn := catalogClient(t, tt.addr)
+			defer conn.Close()
+
+			stream, err := c.ListChannels(context.TODO(), tt.req)
+			require.NoError(t, err)
+			channels, err := recvAll(stream.Recv)
+			if tt.wantErrCode != 0 {
+				requireCode(t, tt.wantErrCode, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected == nil {
+				require.Len(t, channels, tt.wantCount)
+				return
+			}
+			require.Empty(t, cmp.Diff(tt.expected, channels, protocmp.Transform()))
+		})
+	}
+}
+
+func TestCatalogGetDeprecations(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		addr        string
+		req         *apiv2.GetDeprecationsRequest
+		expected    *apiv2.Deprecations
+		wantErrCode codes.Code
+	}{
+		{
+			name: "FBCCacheWithDeprecations",
+			addr: deprecationCacheAddress,
+			req:  &apiv2.GetDeprecationsRequest{PackageName: "cockroachdb"},
+			expected: &apiv2.Deprecations{PackageName: "cockroachdb", Entries: []*apiv2.DeprecationEntry{
+				{
+					Reference: &apiv2.PackageScopedReference{Schema: "olm.package"},
+					Message:   "package cockroachdb is end of life.  Please use 'nouveau-cockroachdb' package for support.\n",
+				},
+				{
+					Reference: &apiv2.PackageScopedReference{Schema: "olm.channel", Name: "stable-5.x"},
+					Message:   "channel stable-5.x is no longer supported.  Please switch to channel 'stable-6.x'.\n",
+				},
+				{
+					Reference: &apiv2.PackageScopedReference{Schema: "olm.bundle", Name: "cockroachdb.v5.0.3"},
+					Message:   "cockroachdb.v5.0.3 is deprecated. Uninstall and install cockroachdb.v5.0.4 for support.\n",
+				},
+			}},
+		},
+		{
+			name:     "FBCCache/NoDeprecations",
+			addr:     cacheAddress,
+			req:      &apiv2.GetDeprecationsRequest{PackageName: "etcd"},
+			expected: &apiv2.Deprecations{PackageName: "etcd"},
+		},
+		{
+			name:        "FBCCache/UnknownPackage",
+			addr:        cacheAddress,
+			req:         &apiv2.GetDeprecationsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+		{
+			name:        "FBCCache/MissingPackageName",
+			addr:        cacheAddress,
+			req:         &apiv2.GetDeprecationsRequest{},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Sqlite",
+			addr:     dbAddress,
+			req:      &apiv2.GetDeprecationsRequest{PackageName: "etcd"},
+			expected: &apiv2.Deprecations{PackageName: "etcd"},
+		},
+		{
+			name:        "Sqlite/UnknownPackage",
+			addr:        dbAddress,
+			req:         &apiv2.GetDeprecationsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, conn := catalogClient(t, tt.addr)
+			defer conn.Close()
+
+			deprecations, err := c.GetDeprecations(context.TODO(), tt.req)
+			if tt.wantErrCode != 0 {
+				requireCode(t, tt.wantErrCode, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, cmp.Diff(tt.expected, deprecations, protocmp.Transform()))
+		})
+	}
+}
+
+func TestCatalogListCustomSchemaBlobs(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		addr        string
+		req         *apiv2.ListCustomSchemaBlobsRequest
+		expected    []string
+		wantErrCode codes.Code
+	}{
+		{
+			name:     "FBCCache/All",
+			addr:     customSchemaCacheAddress,
+			req:      &apiv2.ListCustomSchemaBlobsRequest{},
+			expected: []string{"custom.packageless//global-config", "custom.operator.io/testpkg/another-custom-resource", "custom.operator.io/testpkg/my-custom-resource"},
+		},
+		{
+			name:     "FBCCache/Schema",
+			addr:     customSchemaCacheAddress,
+			req:      &apiv2.ListCustomSchemaBlobsRequest{Schema: "custom.packageless"},
+			expected: []string{"custom.packageless//global-config"},
+		},
+		{
+			name:     "FBCCache/Package",
+			addr:     customSchemaCacheAddress,
+			req:      &apiv2.ListCustomSchemaBlobsRequest{PackageName: "testpkg"},
+			expected: []string{"custom.operator.io/testpkg/another-custom-resource", "custom.operator.io/testpkg/my-custom-resource"},
+		},
+		{
+			name:     "FBCCache/Name",
+			addr:     customSchemaCacheAddress,
+			req:      &apiv2.ListCustomSchemaBlobsRequest{Schema: "custom.operator.io", PackageName: "testpkg", Name: "my-custom-resource"},
+			expected: []string{"custom.operator.io/testpkg/my-custom-resource"},
+		},
+		{
+			name: "FBCCache/CoreSchema",
+			addr: customSchemaCacheAddress,
+			req:  &apiv2.ListCustomSchemaBlobsRequest{Schema: "olm.bundle"},
+		},
+		{
+			name:        "FBCCache/UnknownPackage",
+			addr:        customSchemaCacheAddress,
+			req:         &apiv2.ListCustomSchemaBlobsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+		{
+			name: "Sqlite",
+			addr: dbAddress,
+			req:  &apiv2.ListCustomSchemaBlobsRequest{PackageName: "etcd"},
+		},
+		{
+			name:        "Sqlite/UnknownPackage",
+			addr:        dbAddress,
+			req:         &apiv2.ListCustomSchemaBlobsRequest{PackageName: "nonexistent"},
+			wantErrCode: codes.NotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, conn := catalogClient(t, tt.addr)
+			defer conn.Close()
+
+			stream, err := c.ListCustomSchemaBlobs(context.TODO(), tt.req)
+			require.NoError(t, err)
+			blobs, err := recvAll(stream.Recv)
+			if tt.wantErrCode != 0 {
+				requireCode(t, tt.wantErrCode, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var actual []string
+			for _, b := range blobs {
+				require.Equal(t, b.GetSchema(), b.GetBlob().AsMap()["schema"])
+				actual = append(actual, b.GetSchema()+"/"+b.GetPackageName()+"/"+b.GetName())
+			}
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
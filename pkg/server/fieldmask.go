@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// maskingBundleSender clears the fields of bundles that are not named by a
+// field mask before sending them.
+type maskingBundleSender struct {
+	stream registry.BundleSender
+	paths  []string
+}
+
+func (s *maskingBundleSender) Send(b *api.Bundle) error {
+	applyFieldMask(b.ProtoReflect(), s.paths)
+	return s.stream.Send(b)
+}
+
+// filteringBundleSender sends only the bundles selected by a filter, for
+// stores that cannot filter the bundles themselves.
+type filteringBundleSender struct {
+	stream registry.BundleSender
+	filter registry.BundleFilter
+}
+
+func (s *filteringBundleSender) Send(b *api.Bundle) error {
+	if !s.filter.Matches(b) {
+		return nil
+	}
+	return s.stream.Send(b)
+}
+
+// applyFieldMask clears the fields of m that are not named by paths. The
+// paths must be valid for m, so that only singular message fields have
+// subpaths.
+func applyFieldMask(m protoreflect.Message, paths []string) {
+	whole := map[protoreflect.Name]bool{}
+	subpaths := map[protoreflect.Name][]string{}
+	for _, path := range paths {
+		name, rest, nested := strings.Cut(path, ".")
+		if nested {
+			subpaths[protoreflect.Name(name)] = append(subpaths[protoreflect.Name(name)], rest)
+		} else {
+			whole[protoreflect.Name(name)] = true
+		}
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case whole[fd.Name()]:
+		case len(subpaths[fd.Name()]) > 0:
+			applyFieldMask(v.Message(), subpaths[fd.Name()])
+		default:
+			m.Clear(fd)
+		}
+		return true
+	})
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	fbccache "github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/lib/log"
+)
+
+const contentTypeJSONL = "application/jsonl"
+
+// CatalogHandler serves the declarative config content of a cache over
+// HTTP, for clients that do not speak the gRPC registry API.
+//
+// It serves:
+//
+//	GET /api/v1/all                      the full catalog
+//	GET /api/v1/packages/{name}          the objects of one package
+//	GET /api/v1/metas?schema=&package=   objects filtered by schema and/or package
+//
+// Responses are JSON Lines streams, one object per line. Each response has a
+// weak ETag derived from the content of the packages it serves, and clients
+// that send it back in If-None-Match get a 304 Not Modified until that
+// content changes.
+// Responses are gzip-compressed for clients that accept it.
+type CatalogHandler struct {
+	store *fbccache.Reloadable
+	log   *logrus.Entry
+	mux   *http.ServeMux
+}
+
+var _ http.Handler = &CatalogHandler{}
+
+type CatalogHandlerOption func(*CatalogHandler)
+
+func WithCatalogHandlerLog(log *logrus.Entry) CatalogHandlerOption {
+	return func(h *CatalogHandler) {
+		h.log = log
+	}
+}
+
+func NewCatalogHandler(store *fbccache.Reloadable, opts ...CatalogHandlerOption) *CatalogHandler {
+	h := &CatalogHandler{
+		store: store,
+		log:   log.Null(),
+		mux:   http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("GET /api/v1/all", func(w http.ResponseWriter, r *http.Request) {
+		h.serveFBC(w, r, fbccache.FBCFilter{})
+	})
+	h.mux.HandleFunc("GET /api/v1/packages/{name}", func(w http.ResponseWriter, r *http.Request) {
+		h.serveFBC(w, r, fbccache.FBCFilter{Package: r.PathValue("name")})
+	})
+	h.mux.HandleFunc("GET /api/v1/metas", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		h.serveFBC(w, r, fbccache.FBCFilter{Package: q.Get("package"), Schema: q.Get("schema")})
+	})
+	return h
+}
+
+func (h *CatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *CatalogHandler) serveFBC(w http.ResponseWriter, r *http.Request, filter fbccache.FBCFilter) {
+	// Read the digest and the content from the same cache, so that the ETag
+	// always describes the content it is sent with, even if the cache is
+	// reloaded during the request.
+	err := h.store.View(func(c fbccache.Cache) error {
+		digest, err := c.DeclarativeConfigDigest(r.Context(), filter)
+		if err != nil {
+			return err
+		}
+		etag := fmt.Sprintf("W/%q", digest)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept-Encoding")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		rw := &fbcResponseWriter{w: w, gzip: acceptsGzip(r.Header.Get("Accept-Encoding")), head: r.Method == http.MethodHead}
+		if err := c.SendDeclarativeConfig(r.Context(), filter, rw.send); err != nil {
+			if rw.started {
+				// The status has already been sent, so the best we can do is
+				// to cut the response short.
+				h.log.WithError(err).Warn("error streaming declarative config")
+				return nil
+			}
+			return err
+		}
+		return rw.finish()
+	})
+	if err == nil {
+		return
+	}
+	w.Header().Del("ETag")
+	switch {
+	case errors.Is(err, fbccache.ErrPackageNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, fbccache.ErrFBCNotCached):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case r.Context().Err() != nil:
+		// The client went away; there is nobody to respond to.
+	default:
+		h.log.WithError(err).Warn("error serving declarative config")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// fbcResponseWriter writes the response headers when the first object is
+// sent, so that errors found before any content is sent (e.g. an unknown
+// package) can still be reported with an error status.
+type fbcResponseWriter struct {
+	w       http.ResponseWriter
+	gzip    bool
+	head    bool
+	started bool
+	out     io.Writer
+	gz      *gzip.Writer
+}
+
+func (rw *fbcResponseWriter) start() {
+	rw.started = true
+	rw.w.Header().Set("Content-Type", contentTypeJSONL)
+	if rw.gzip {
+		rw.w.Header().Set("Content-Encoding", "gzip")
+	}
+	rw.w.WriteHeader(http.StatusOK)
+	switch {
+	case rw.head:
+		rw.out = io.Discard
+	case rw.gzip:
+		rw.gz = gzip.NewWriter(rw.w)
+		rw.out = rw.gz
+	default:
+		rw.out = rw.w
+	}
+}
+
+func (rw *fbcResponseWriter) send(meta *declcfg.Meta) error {
+	if !rw.started {
+		rw.start()
+	}
+	if _, err := rw.out.Write(meta.Blob); err != nil {
+		return err
+	}
+	if len(meta.Blob) == 0 || meta.Blob[len(meta.Blob)-1] != '\n' {
+		if _, err := io.WriteString(rw.out, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rw *fbcResponseWriter) finish() error {
+	if !rw.started {
+		rw.start()
+	}
+	if rw.gz != nil {
+		return rw.gz.Close()
+	}
+	return nil
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison that RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value allows a
+// gzip-encoded response.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
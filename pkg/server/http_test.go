@@ -0,0 +1,207 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	fbccache "github.com/operator-framework/operator-registry/pkg/cache"
+)
+
+var httpTestFS = fstest.MapFS{
+	"catalog.json": &fstest.MapFile{Data: []byte(`{"schema": "olm.package", "name": "foo", "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": "foo", "name": "stable", "entries": [{"name": "foo.v1.0.0"}]}
+{"schema": "olm.bundle", "package": "foo", "name": "foo.v1.0.0", "image": "quay.io/test/foo:v1.0.0", "properties": [{"type": "olm.package", "value": {"packageName": "foo", "version": "1.0.0"}}]}
+{"schema": "custom.example.com", "package": "foo", "name": "extra"}
+{"schema": "olm.package", "name": "bar", "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": "bar", "name": "stable", "entries": [{"name": "bar.v1.0.0"}]}
+{"schema": "olm.bundle", "package": "bar", "name": "bar.v1.0.0", "image": "quay.io/test/bar:v1.0.0", "properties": [{"type": "olm.package", "value": {"packageName": "bar", "version": "1.0.0"}}]}
+`)},
+}
+
+func TestCatalogHandler(t *testing.T) {
+	ctx := context.Background()
+	c, err := fbccache.New(t.TempDir(), fbccache.WithDeclarativeConfig())
+	require.NoError(t, err)
+	require.NoError(t, c.Build(ctx, httpTestFS))
+	require.NoError(t, c.Load(ctx))
+	store := fbccache.NewReloadable(c)
+	defer store.Close()
+	etag := func(t *testing.T, filter fbccache.FBCFilter) string {
+		t.Helper()
+		digest, err := c.DeclarativeConfigDigest(ctx, filter)
+		require.NoError(t, err)
+		return `W/"` + digest + `"`
+	}
+	allETag := etag(t, fbccache.FBCFilter{})
+
+	srv := httptest.NewServer(NewCatalogHandler(store))
+	defer srv.Close()
+
+	// Disable transparent decompression so that the encoding of responses
+	// can be checked.
+	transport := &http.Transport{DisableCompression: true}
+	defer transport.CloseIdleConnections()
+	get := func(t *testing.T, path string, header http.Header) (*http.Response, []string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		if resp.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(resp.Body)
+			require.NoError(t, err)
+			body = gz
+		}
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		var schemaNames []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" || resp.StatusCode != http.StatusOK {
+				continue
+			}
+			var meta struct{ Schema, Name string }
+			require.NoError(t, json.Unmarshal([]byte(line), &meta))
+			schemaNames = append(schemaNames, meta.Schema+"/"+meta.Name)
+		}
+		return resp, schemaNames
+	}
+
+	type spec struct {
+		name        string
+		path        string
+		filter      fbccache.FBCFilter
+		header      http.Header
+		expectCode  int
+		expectGzip  bool
+		expectNames []string
+	}
+	specs := []spec{
+		{
+			name:       "All",
+			path:       "/api/v1/all",
+			expectCode: http.StatusOK,
+			expectNames: []string{
+				"olm.package/bar", "olm.channel/stable", "olm.bundle/bar.v1.0.0",
+				"olm.package/foo", "olm.channel/stable", "olm.bundle/foo.v1.0.0", "custom.example.com/extra",
+			},
+		},
+		{
+			name:        "Package",
+			path:        "/api/v1/packages/foo",
+			filter:      fbccache.FBCFilter{Package: "foo"},
+			expectCode:  http.StatusOK,
+			expectNames: []string{"olm.package/foo", "olm.channel/stable", "olm.bundle/foo.v1.0.0", "custom.example.com/extra"},
+		},
+		{
+			name:       "UnknownPackage",
+			path:       "/api/v1/packages/baz",
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:        "Schema",
+			path:        "/api/v1/metas?schema=olm.bundle",
+			filter:      fbccache.FBCFilter{Schema: "olm.bundle"},
+			expectCode:  http.StatusOK,
+			expectNames: []string{"olm.bundle/bar.v1.0.0", "olm.bundle/foo.v1.0.0"},
+		},
+		{
+			name:        "SchemaAndPackage",
+			path:        "/api/v1/metas?schema=olm.package&package=bar",
+			filter:      fbccache.FBCFilter{Package: "bar", Schema: "olm.package"},
+			expectCode:  http.StatusOK,
+			expectNames: []string{"olm.package/bar"},
+		},
+		{
+			name:        "Gzip",
+			path:        "/api/v1/packages/bar",
+			filter:      fbccache.FBCFilter{Package: "bar"},
+			header:      http.Header{"Accept-Encoding": {"br;q=1.0, gzip;q=0.8"}},
+			expectCode:  http.StatusOK,
+			expectGzip:  true,
+			expectNames: []string{"olm.package/bar", "olm.channel/stable", "olm.bundle/bar.v1.0.0"},
+		},
+		{
+			name:        "GzipRefused",
+			path:        "/api/v1/packages/bar",
+			filter:      fbccache.FBCFilter{Package: "bar"},
+			header:      http.Header{"Accept-Encoding": {"gzip;q=0"}},
+			expectCode:  http.StatusOK,
+			expectNames: []string{"olm.package/bar", "olm.channel/stable", "olm.bundle/bar.v1.0.0"},
+		},
+		{
+			name:       "NotModified",
+			path:       "/api/v1/all",
+			header:     http.Header{"If-None-Match": {`"other", ` + allETag}},
+			expectCode: http.StatusNotModified,
+		},
+		{
+			// The ETag of the full catalog does not match the content of
+			// one package.
+			name:       "OtherContentETag",
+			path:       "/api/v1/packages/bar",
+			filter:     fbccache.FBCFilter{Package: "bar"},
+			header:     http.Header{"If-None-Match": {allETag}},
+			expectCode: http.StatusOK,
+			expectNames: []string{
+				"olm.package/bar", "olm.channel/stable", "olm.bundle/bar.v1.0.0",
+			},
+		},
+		{
+			name:       "StaleETag",
+			path:       "/api/v1/packages/bar",
+			filter:     fbccache.FBCFilter{Package: "bar"},
+			header:     http.Header{"If-None-Match": {`W/"stale"`}},
+			expectCode: http.StatusOK,
+			expectNames: []string{
+				"olm.package/bar", "olm.channel/stable", "olm.bundle/bar.v1.0.0",
+			},
+		},
+	}
+	for _, s := range specs {
+		t.Run(s.name, func(t *testing.T) {
+			resp, names := get(t, s.path, s.header)
+			require.Equal(t, s.expectCode, resp.StatusCode)
+			if s.expectCode == http.StatusOK || s.expectCode == http.StatusNotModified {
+				require.Equal(t, etag(t, s.filter), resp.Header.Get("ETag"))
+			}
+			if s.expectCode == http.StatusOK {
+				require.Equal(t, "application/jsonl", resp.Header.Get("Content-Type"))
+			}
+			require.Equal(t, s.expectGzip, resp.Header.Get("Content-Encoding") == "gzip")
+			require.Equal(t, s.expectNames, names)
+		})
+	}
+}
+
+func TestCatalogHandler_NoDeclarativeConfig(t *testing.T) {
+	ctx := context.Background()
+	c, err := fbccache.New(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, c.Build(ctx, httpTestFS))
+	require.NoError(t, c.Load(ctx))
+	store := fbccache.NewReloadable(c)
+	defer store.Close()
+
+	srv := httptest.NewServer(NewCatalogHandler(store))
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL + "/api/v1/all")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("ETag"))
+}
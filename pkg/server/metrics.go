@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	rpcTypeUnary        = "unary"
+	rpcTypeServerStream = "server_stream"
+	rpcTypeClientStream = "client_stream"
+	rpcTypeBidiStream   = "bidi_stream"
+)
+
+// Metrics records Prometheus metrics for a registry server: per-RPC request
+// counts, latencies, result codes and stream message counts, gathered by
+// gRPC interceptors, plus cache build and load timings and the digest of the
+// catalog being served.
+//
+// The gRPC metric names and labels match those of the widely used
+// go-grpc-prometheus interceptors, so existing dashboards can be reused.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	handled      *prometheus.CounterVec
+	handlingTime *prometheus.HistogramVec
+	msgSent      *prometheus.CounterVec
+
+	cacheOperationTime *prometheus.HistogramVec
+	catalogInfo        *prometheus.GaugeVec
+}
+
+// NewMetrics creates registry server metrics in a new Prometheus registry,
+// which also collects Go runtime and process metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"}),
+		handlingTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of response latency (seconds) of RPCs that had been application-level handled by the server.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of stream messages sent by the server.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+		cacheOperationTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "opm_cache_operation_duration_seconds",
+			Help:    "Duration of cache builds and loads.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+		}, []string{"operation", "result"}),
+		catalogInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "opm_catalog_info",
+			Help: "Information about the catalog being served. The value is always 1.",
+		}, []string{"digest", "backend"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.handled,
+		m.handlingTime,
+		m.msgSent,
+		m.cacheOperationTime,
+		m.catalogInfo,
+	)
+	return m
+}
+
+// Registry returns the Prometheus registry that the metrics are registered
+// with, so that callers can register their own collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns an HTTP handler that serves the metrics in the Prometheus
+// exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Serve serves the metrics at /metrics on lis until ctx is done.
+func (m *Metrics) Serve(ctx context.Context, lis net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeOn listens on addr and serves the metrics at /metrics in the
+// background until ctx is done. Errors after the listener is set up are
+// logged to logger.
+func (m *Metrics) ServeOn(ctx context.Context, addr string, logger *logrus.Entry) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics: %s", err)
+	}
+	logger = logger.WithField("metricsAddr", addr)
+	go func() {
+		logger.Info("serving metrics")
+		if err := m.Serve(ctx, lis); err != nil {
+			logger.WithError(err).Error("metrics server failed")
+		}
+	}()
+	return nil
+}
+
+// ServerOptions returns the options that install the metrics interceptors
+// in a gRPC server. Interceptors chained by earlier options run first.
+func (m *Metrics) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainStreamInterceptor(m.StreamServerInterceptor()),
+		grpc.ChainUnaryInterceptor(m.UnaryServerInterceptor()),
+	}
+}
+
+// ObserveCacheOperation records the duration and result of a cache
+// operation, such as "build" or "load".
+func (m *Metrics) ObserveCacheOperation(operation string, d time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.cacheOperationTime.WithLabelValues(operation, result).Observe(d.Seconds())
+}
+
+// SetCatalogInfo records the digest and cache backend of the catalog being
+// served, replacing any previously recorded values.
+func (m *Metrics) SetCatalogInfo(digest, backend string) {
+	m.catalogInfo.Reset()
+	m.catalogInfo.WithLabelValues(digest, backend).Set(1)
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		service, method := splitFullMethod(info.FullMethod)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(rpcTypeUnary, service, method, start, err)
+		return resp, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		rpcType := streamRPCType(info)
+		service, method := splitFullMethod(info.FullMethod)
+		start := time.Now()
+		err := handler(srv, &countingServerStream{
+			ServerStream: ss,
+			sent:         m.msgSent.WithLabelValues(rpcType, service, method),
+		})
+		m.observe(rpcType, service, method, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observe(rpcType, service, method string, start time.Time, err error) {
+	m.handlingTime.WithLabelValues(rpcType, service, method).Observe(time.Since(start).Seconds())
+	m.handled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+}
+
+type countingServerStream struct {
+	grpc.ServerStream
+	sent prometheus.Counter
+}
+
+func (s *countingServerStream) SendMsg(msg any) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.sent.Inc()
+	}
+	return err
+}
+
+func streamRPCType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return rpcTypeBidiStream
+	case info.IsClientStream:
+		return rpcTypeClientStream
+	}
+	return rpcTypeServerStream
+}
+
+// splitFullMethod splits a gRPC method name of the form
+// "/package.Service/Method" into its service and method.
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/lib/log"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (fakeServerStream) Context() context.Context { return context.Background() }
+func (fakeServerStream) SendMsg(any) error        { return nil }
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	unary := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.Registry/GetPackage"}
+	_, err := unary(context.Background(), nil, info, func(context.Context, any) (any, error) { return nil, nil })
+	require.NoError(t, err)
+	_, err = unary(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	stream := m.StreamServerInterceptor()
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/api.Registry/ListBundles", IsServerStream: true}
+	err = stream(nil, fakeServerStream{}, streamInfo, func(_ any, ss grpc.ServerStream) error {
+		for range 3 {
+			if err := ss.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	m.ObserveCacheOperation("build", time.Second, nil)
+	m.ObserveCacheOperation("load", time.Second, errors.New("failed"))
+	m.SetCatalogInfo("old", "json")
+	m.SetCatalogInfo("new", "pogreb.v1")
+
+	require.InDelta(t, 1, testutil.ToFloat64(m.handled.WithLabelValues("unary", "api.Registry", "GetPackage", "OK")), 0)
+	require.InDelta(t, 1, testutil.ToFloat64(m.handled.WithLabelValues("unary", "api.Registry", "GetPackage", "NotFound")), 0)
+	require.InDelta(t, 1, testutil.ToFloat64(m.handled.WithLabelValues("server_stream", "api.Registry", "ListBundles", "OK")), 0)
+	require.InDelta(t, 3, testutil.ToFloat64(m.msgSent.WithLabelValues("server_stream", "api.Registry", "ListBundles")), 0)
+	require.Equal(t, 2, testutil.CollectAndCount(m.handlingTime))
+	require.Equal(t, 2, testutil.CollectAndCount(m.cacheOperationTime))
+
+	// Only the latest catalog info is reported.
+	require.Equal(t, 1, testutil.CollectAndCount(m.catalogInfo))
+	require.InDelta(t, 1, testutil.ToFloat64(m.catalogInfo.WithLabelValues("new", "pogreb.v1")), 0)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(body), `opm_catalog_info{backend="pogreb.v1",digest="new"} 1`), string(body))
+}
+
+func TestMetrics_ServerOptions(t *testing.T) {
+	m := NewMetrics()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(m.ServerOptions()...)
+	health.RegisterHealthServer(s, NewHealthServer())
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = health.NewHealthClient(conn).Check(context.Background(), &health.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.InDelta(t, 1, testutil.ToFloat64(m.handled.WithLabelValues("unary", "grpc.health.v1.Health", "Check", "OK")), 0)
+}
+
+func TestMetrics_ServeOn(t *testing.T) {
+	m := NewMetrics()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	// The address is in use, so the error is returned rather than logged.
+	require.ErrorContains(t, m.ServeOn(context.Background(), lis.Addr().String(), log.Null()), "failed to listen for metrics")
+}
@@ -44,7 +44,22 @@ func (s *RegistryServer) ListPackages(req *api.ListPackageRequest, stream api.Re
 }
 
 func (s *RegistryServer) ListBundles(req *api.ListBundlesRequest, stream api.Registry_ListBundlesServer) error {
-	return s.store.SendBundles(stream.Context(), stream)
+	filter := registry.BundleFilter{
+		PackageName:       req.GetPkgName(),
+		ChannelName:       req.GetChannelName(),
+		ExcludeDeprecated: req.GetExcludeDeprecated(),
+	}
+	var sender registry.BundleSender = stream
+	if paths := req.GetFieldMask().GetPaths(); len(paths) > 0 {
+		if !req.GetFieldMask().IsValid(&api.Bundle{}) {
+			return status.Errorf(codes.InvalidArgument, "invalid field mask for bundles: %v", paths)
+		}
+		sender = &maskingBundleSender{stream: stream, paths: paths}
+	}
+	if fs, ok := s.store.(registry.FilteredBundleSender); ok {
+		return fs.SendFilteredBundles(stream.Context(), filter, sender)
+	}
+	return s.store.SendBundles(stream.Context(), &filteringBundleSender{stream: sender, filter: filter})
 }
 
 func (s *RegistryServer) GetPackage(ctx context.Context, req *api.GetPackageRequest) (*api.Package, error) {
@@ -23,10 +23,12 @@ import (
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/operator-framework/operator-registry/alpha/action"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/pkg/api"
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
 	fbccache "github.com/operator-framework/operator-registry/pkg/cache"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/sqlite"
@@ -105,10 +107,14 @@ func fbcCacheFromFs(catalogFS fs.FS, cacheDir string) (fbccache.Cache, error) {
 	return store, nil
 }
 
-func server(store registry.GRPCQuery) *grpc.Server {
+func server(store interface {
+	registry.GRPCQuery
+	registry.CatalogQuery
+}) *grpc.Server {
 	s := grpc.NewServer()
 	api.RegisterRegistryServer(s, NewRegistryServer(store))
 	api.RegisterExperimentalRegistryServer(s, NewExperimentalRegistryServer(store))
+	apiv2.RegisterCatalogServer(s, NewCatalogServer(store))
 	return s
 }
 
@@ -813,6 +819,157 @@ func testListBundles(addr string, etcdAlpha *api.Bundle, etcdStable *api.Bundle)
 	}
 }
 
+func TestListBundlesFiltered(t *testing.T) {
+	etcdAlpha := []string{"etcdoperator.v0.6.1", "etcdoperator.v0.9.0", "etcdoperator.v0.9.2"}
+	for _, tt := range []struct {
+		name        string
+		addr        string
+		req         *api.ListBundlesRequest
+		expected    []string
+		check       func(*testing.T, *api.Bundle)
+		wantErrCode codes.Code
+	}{
+		{
+			name:     "Sqlite/PackageAndChannel",
+			addr:     dbAddress,
+			req:      &api.ListBundlesRequest{PkgName: "etcd", ChannelName: "alpha"},
+			expected: etcdAlpha,
+		},
+		{
+			name:     "FBCCache/PackageAndChannel",
+			addr:     cacheAddress,
+			req:      &api.ListBundlesRequest{PkgName: "etcd", ChannelName: "alpha"},
+			expected: etcdAlpha,
+		},
+		{
+			name:     "FBCCache/UnknownPackage",
+			addr:     cacheAddress,
+			req:      &api.ListBundlesRequest{PkgName: "nonexistent"},
+			expected: nil,
+		},
+		{
+			name:     "Sqlite/FieldMask",
+			addr:     dbAddress,
+			req:      &api.ListBundlesRequest{PkgName: "etcd", ChannelName: "alpha", FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"csvName", "version", "providedApis"}}},
+			expected: etcdAlpha,
+			check: func(t *testing.T, b *api.Bundle) {
+				require.NotEmpty(t, b.Version)
+				require.NotEmpty(t, b.ProvidedApis)
+				require.Empty(t, b.PackageName)
+				require.Empty(t, b.Properties)
+				require.Empty(t, b.BundlePath)
+			},
+		},
+		{
+			name:     "FBCCache/FieldMask",
+			addr:     cacheAddress,
+			req:      &api.ListBundlesRequest{PkgName: "etcd", ChannelName: "alpha", FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"csvName", "version", "providedApis"}}},
+			expected: etcdAlpha,
+			check: func(t *testing.T, b *api.Bundle) {
+				require.NotEmpty(t, b.Version)
+				require.NotEmpty(t, b.ProvidedApis)
+				require.Empty(t, b.PackageName)
+				require.Empty(t, b.Properties)
+				require.Empty(t, b.BundlePath)
+			},
+		},
+		{
+			name:     "FBCCacheWithDeprecations/NestedFieldMask",
+			addr:     deprecationCacheAddress,
+			req:      &api.ListBundlesRequest{ChannelName: "stable-5.x", FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"csvName", "deprecation.message"}}},
+			expected: []string{"cockroachdb.v5.0.3", "cockroachdb.v5.0.4"},
+			check: func(t *testing.T, b *api.Bundle) {
+				if b.CsvName == "cockroachdb.v5.0.3" {
+					require.NotEmpty(t, b.GetDeprecation().GetMessage())
+				}
+				require.Empty(t, b.ChannelName)
+			},
+		},
+		{
+			name:        "InvalidFieldMask",
+			addr:        cacheAddress,
+			req:         &api.ListBundlesRequest{FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"providedApis.kind"}}},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name:     "FBCCacheWithDeprecations/All",
+			addr:     deprecationCacheAddress,
+			req:      &api.ListBundlesRequest{PkgName: "cockroachdb", ChannelName: "stable-5.x"},
+			expected: []string{"cockroachdb.v5.0.3", "cockroachdb.v5.0.4"},
+		},
+		{
+			name:     "FBCCacheWithDeprecations/ExcludeDeprecated",
+			addr:     deprecationCacheAddress,
+			req:      &api.ListBundlesRequest{PkgName: "cockroachdb", ChannelName: "stable-5.x", ExcludeDeprecated: true},
+			expected: []string{"cockroachdb.v5.0.4"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, conn := client(t, tt.addr)
+			defer conn.Close()
+
+			stream, err := c.ListBundles(context.TODO(), tt.req)
+			require.NoError(t, err)
+			bundles, err := recvAll(stream.Recv)
+			if tt.wantErrCode != codes.OK {
+				requireCode(t, tt.wantErrCode, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, b := range bundles {
+				names = append(names, b.CsvName)
+				if tt.check != nil {
+					tt.check(t, b)
+				}
+			}
+			require.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
+
+// unfilteredStore is a store that does not implement
+// registry.FilteredBundleSender, and so sends every bundle.
+type unfilteredStore struct {
+	registry.GRPCQuery
+	bundles []*api.Bundle
+}
+
+func (s unfilteredStore) SendBundles(_ context.Context, stream registry.BundleSender) error {
+	for _, b := range s.bundles {
+		if err := stream.Send(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type listBundlesStream struct {
+	fakeServerStream
+	sent []*api.Bundle
+}
+
+func (s *listBundlesStream) Send(b *api.Bundle) error {
+	s.sent = append(s.sent, b)
+	return nil
+}
+
+func TestListBundlesFilteredFallback(t *testing.T) {
+	store := unfilteredStore{bundles: []*api.Bundle{
+		{CsvName: "foo.v0.1.0", PackageName: "foo", ChannelName: "alpha"},
+		{CsvName: "foo.v0.2.0", PackageName: "foo", ChannelName: "alpha", Deprecation: &api.Deprecation{Message: "foo.v0.2.0 is bad"}},
+		{CsvName: "foo.v0.1.0", PackageName: "foo", ChannelName: "beta"},
+		{CsvName: "bar.v0.1.0", PackageName: "bar", ChannelName: "alpha"},
+	}}
+	stream := &listBundlesStream{}
+	req := &api.ListBundlesRequest{PkgName: "foo", ChannelName: "alpha", ExcludeDeprecated: true}
+	require.NoError(t, NewRegistryServer(store).ListBundles(req, stream))
+	require.Len(t, stream.sent, 1)
+	require.Equal(t, "foo.v0.1.0", stream.sent[0].CsvName)
+	require.Equal(t, "alpha", stream.sent[0].ChannelName)
+}
+
 func TestExperimentalListPackageCustomSchemas(t *testing.T) {
 	for _, tt := range []struct {
 		name        string
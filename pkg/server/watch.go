@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+)
+
+// watchBuffer is the number of events that may be queued for a Watch call
+// before it is considered to have fallen behind.
+const watchBuffer = 16
+
+// CatalogState reports the digests of a served catalog. Both cache.Cache and
+// cache.Reloadable implement it.
+type CatalogState interface {
+	Digest(ctx context.Context) (string, error)
+	PackageDigests(ctx context.Context) (map[string]string, error)
+}
+
+// ChangeNotifier tracks the state of a served catalog and sends an event to
+// every Watch call when it changes.
+type ChangeNotifier struct {
+	mu       sync.Mutex
+	digest   string
+	packages map[string]string
+	watches  map[chan *apiv2.WatchEvent]struct{}
+}
+
+// NewChangeNotifier returns a ChangeNotifier for a catalog that is currently
+// in the given state.
+func NewChangeNotifier(ctx context.Context, state CatalogState) (*ChangeNotifier, error) {
+	digest, packages, err := readCatalogState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeNotifier{
+		digest:   digest,
+		packages: packages,
+		watches:  map[chan *apiv2.WatchEvent]struct{}{},
+	}, nil
+}
+
+// Update reads the state of the catalog and, if it has changed since the
+// last update, sends an event describing the change to every Watch call. It
+// is meant to be called each time the served catalog is reloaded.
+//
+// Watch calls that have fallen behind are ended rather than blocking the
+// update, and their clients are expected to watch again.
+func (n *ChangeNotifier) Update(ctx context.Context, state CatalogState) error {
+	digest, packages, err := readCatalogState(ctx, state)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	event := &apiv2.WatchEvent{Digest: digest}
+	for pkgName, pkgDigest := range packages {
+		if prevDigest, ok := n.packages[pkgName]; !ok {
+			event.AddedPackages = append(event.AddedPackages, pkgName)
+		} else if prevDigest != pkgDigest {
+			event.UpdatedPackages = append(event.UpdatedPackages, pkgName)
+		}
+	}
+	for pkgName := range n.packages {
+		if _, ok := packages[pkgName]; !ok {
+			event.RemovedPackages = append(event.RemovedPackages, pkgName)
+		}
+	}
+	if digest == n.digest && len(event.AddedPackages)+len(event.UpdatedPackages)+len(event.RemovedPackages) == 0 {
+		return nil
+	}
+	slices.Sort(event.AddedPackages)
+	slices.Sort(event.UpdatedPackages)
+	slices.Sort(event.RemovedPackages)
+	n.digest, n.packages = digest, packages
+
+	for events := range n.watches {
+		select {
+		case events <- event:
+		default:
+			close(events)
+			delete(n.watches, events)
+		}
+	}
+	return nil
+}
+
+// watch registers a Watch call. It returns an event with the current digest,
+// a channel of later events that is closed if the call falls behind, and a
+// function that unregisters the call.
+func (n *ChangeNotifier) watch() (*apiv2.WatchEvent, <-chan *apiv2.WatchEvent, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	events := make(chan *apiv2.WatchEvent, watchBuffer)
+	n.watches[events] = struct{}{}
+	return &apiv2.WatchEvent{Digest: n.digest}, events, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.watches, events)
+	}
+}
+
+func readCatalogState(ctx context.Context, state CatalogState) (string, map[string]string, error) {
+	digest, err := state.Digest(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	packages, err := state.PackageDigests(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return digest, packages, nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	fbccache "github.com/operator-framework/operator-registry/pkg/cache"
+)
+
+type staticCatalogState struct {
+	digest   string
+	packages map[string]string
+}
+
+func (s staticCatalogState) Digest(context.Context) (string, error) {
+	return s.digest, nil
+}
+
+func (s staticCatalogState) PackageDigests(context.Context) (map[string]string, error) {
+	return s.packages, nil
+}
+
+func TestChangeNotifierUpdate(t *testing.T) {
+	initial := staticCatalogState{digest: "1", packages: map[string]string{"a": "a1", "b": "b1", "c": "c1"}}
+	for _, tt := range []struct {
+		name     string
+		state    staticCatalogState
+		expected *apiv2.WatchEvent
+	}{
+		{
+			name:     "Unchanged",
+			state:    initial,
+			expected: nil,
+		},
+		{
+			name:     "PackagesChanged",
+			state:    staticCatalogState{digest: "2", packages: map[string]string{"a": "a2", "c": "c1", "e": "e1", "d": "d1"}},
+			expected: &apiv2.WatchEvent{Digest: "2", AddedPackages: []string{"d", "e"}, UpdatedPackages: []string{"a"}, RemovedPackages: []string{"b"}},
+		},
+		{
+			name:     "OnlyDigestChanged",
+			state:    staticCatalogState{digest: "2", packages: initial.packages},
+			expected: &apiv2.WatchEvent{Digest: "2"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewChangeNotifier(context.Background(), initial)
+			require.NoError(t, err)
+			current, events, stop := n.watch()
+			defer stop()
+			require.Equal(t, "1", current.GetDigest())
+
+			require.NoError(t, n.Update(context.Background(), tt.state))
+			var actual *apiv2.WatchEvent
+			select {
+			case actual = <-events:
+			default:
+			}
+			if diff := cmp.Diff(tt.expected, actual, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected event (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangeNotifierSlowWatch(t *testing.T) {
+	n, err := NewChangeNotifier(context.Background(), staticCatalogState{digest: "0"})
+	require.NoError(t, err)
+	_, events, stop := n.watch()
+	defer stop()
+
+	for i := 1; i <= watchBuffer+1; i++ {
+		require.NoError(t, n.Update(context.Background(), staticCatalogState{digest: fmt.Sprint(i)}))
+	}
+	for i := 1; i <= watchBuffer; i++ {
+		event, ok := <-events
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprint(i), event.GetDigest())
+	}
+	_, ok := <-events
+	require.False(t, ok, "expected the events of a watch that fell behind to be closed")
+}
+
+func watchTestFS(packages map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for pkgName, version := range packages {
+		fsys[pkgName+"/catalog.json"] = &fstest.MapFile{Data: []byte(fmt.Sprintf(`{"schema": "olm.package", "name": %[1]q, "defaultChannel": "stable"}
+{"schema": "olm.channel", "package": %[1]q, "name": "stable", "entries": [{"name": "%[1]s.v%[2]s"}]}
+{"schema": "olm.bundle", "package": %[1]q, "name": "%[1]s.v%[2]s", "image": "%[1]s:v%[2]s", "properties": [{"type": "olm.package", "value": {"packageName": %[1]q, "version": %[2]q}}]}
+`, pkgName, version))}
+	}
+	return fsys
+}
+
+func TestCatalogWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial, err := fbcCacheFromFs(watchTestFS(map[string]string{"a": "1.0.0", "b": "1.0.0"}), t.TempDir())
+	require.NoError(t, err)
+	reloadable := fbccache.NewReloadable(initial)
+	defer reloadable.Close()
+	notifier, err := NewChangeNotifier(ctx, reloadable)
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	apiv2.RegisterCatalogServer(s, NewCatalogServer(reloadable, WithChangeNotifier(notifier)))
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := apiv2.NewCatalogClient(conn).Watch(ctx, &apiv2.WatchRequest{})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	initialDigest, err := initial.Digest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, initialDigest, first.GetDigest())
+	require.Empty(t, first.GetAddedPackages())
+
+	reloaded, err := fbcCacheFromFs(watchTestFS(map[string]string{"a": "1.1.0", "c": "1.0.0"}), t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, reloadable.Swap(reloaded))
+	require.NoError(t, notifier.Update(ctx, reloaded))
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	reloadedDigest, err := reloaded.Digest(ctx)
+	require.NoError(t, err)
+	expected := &apiv2.WatchEvent{
+		Digest:          reloadedDigest,
+		AddedPackages:   []string{"c"},
+		UpdatedPackages: []string{"a"},
+		RemovedPackages: []string{"b"},
+	}
+	if diff := cmp.Diff(expected, event, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected event (-want +got):\n%s", diff)
+	}
+}
+
+func TestCatalogWatchUnsupported(t *testing.T) {
+	c, conn := catalogClient(t, dbAddress)
+	defer conn.Close()
+
+	stream, err := c.Watch(context.Background(), &apiv2.WatchRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	requireCode(t, codes.Unimplemented, err)
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	apiv2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+var _ registry.CatalogQuery = &SQLQuerier{}
+
+// SendPackages sends packages with the description and icon of the CSV at
+// the head of their default channel, since sqlite databases do not store
+// them for packages.
+func (s *SQLQuerier) SendPackages(ctx context.Context, sender func(*apiv2.Package) error) error {
+	pkgNames, err := s.ListPackages(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(pkgNames)
+	for _, pkgName := range pkgNames {
+		rPkg, err := s.GetPackage(ctx, pkgName)
+		if err != nil {
+			return err
+		}
+		csv, csvName, err := defaultChannelHeadCSV(ctx, s, rPkg.PackageName, rPkg.DefaultChannelName)
+		if err != nil {
+			return err
+		}
+		p := &apiv2.Package{
+			Name:               rPkg.PackageName,
+			DefaultChannelName: rPkg.DefaultChannelName,
+			Description:        csv.Spec.Description,
+		}
+		for _, ch := range rPkg.Channels {
+			p.ChannelNames = append(p.ChannelNames, ch.Name)
+		}
+		sort.Strings(p.ChannelNames)
+		if icon := csvIcon(csv, csvName); icon != nil {
+			p.Icon = &apiv2.Icon{Data: icon.Data, MediaType: icon.MediaType}
+		}
+		if err := sender(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendChannels sends channels as they are converted to declarative config,
+// so bundles deprecated with `opm index deprecatetruncate` are not among
+// their entries.
+func (s *SQLQuerier) SendChannels(ctx context.Context, packageName string, sender func(*apiv2.Channel) error) error {
+	pkgNames := []string{packageName}
+	if packageName == "" {
+		var err error
+		if pkgNames, err = s.ListPackages(ctx); err != nil {
+			return err
+		}
+		sort.Strings(pkgNames)
+	} else if err := s.checkPackage(ctx, packageName); err != nil {
+		return err
+	}
+	// Packages are converted one at a time, so that sending the channels of
+	// one package does not read the bundles of the others.
+	for _, pkgName := range pkgNames {
+		pkg, err := packageToModel(ctx, s, pkgName)
+		if err != nil {
+			return err
+		}
+		for _, chName := range slices.Sorted(maps.Keys(pkg.Channels)) {
+			ch := pkg.Channels[chName]
+			channel := &apiv2.Channel{
+				PackageName: pkg.Name,
+				Name:        ch.Name,
+				Entries:     make([]*apiv2.ChannelEntry, 0, len(ch.Bundles)),
+			}
+			for _, bName := range slices.Sorted(maps.Keys(ch.Bundles)) {
+				b := ch.Bundles[bName]
+				channel.Entries = append(channel.Entries, &apiv2.ChannelEntry{
+					Name:      b.Name,
+					Replaces:  b.Replaces,
+					Skips:     b.Skips,
+					SkipRange: b.SkipRange,
+				})
+			}
+			if err := sender(channel); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetDeprecations returns no deprecation entries, because sqlite databases
+// cannot hold olm.deprecations blobs.
+func (s *SQLQuerier) GetDeprecations(ctx context.Context, packageName string) (*apiv2.Deprecations, error) {
+	if err := s.checkPackage(ctx, packageName); err != nil {
+		return nil, err
+	}
+	return &apiv2.Deprecations{PackageName: packageName}, nil
+}
+
+// SendCustomSchemaBlobs sends nothing, because sqlite databases cannot hold
+// blobs of custom schemas.
+func (s *SQLQuerier) SendCustomSchemaBlobs(ctx context.Context, _, packageName, _ string, _ func(*apiv2.CustomSchemaBlob) error) error {
+	if packageName != "" {
+		return s.checkPackage(ctx, packageName)
+	}
+	return nil
+}
+
+func (s *SQLQuerier) checkPackage(ctx context.Context, packageName string) error {
+	pkgNames, err := s.ListPackages(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(pkgNames, packageName) {
+		return fmt.Errorf("%w: %q", registry.ErrPackageNotInDatabase, packageName)
+	}
+	return nil
+}
@@ -17,11 +17,15 @@ import (
 )
 
 func ToModel(ctx context.Context, q *SQLQuerier) (model.Model, error) {
-	pkgs, err := initializeModelPackages(ctx, q)
+	pkgNames, err := q.ListPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := initializeModelPackages(ctx, q, pkgNames)
 	if err != nil {
 		return nil, err
 	}
-	if err := populateModelChannels(ctx, pkgs, q); err != nil {
+	if err := populateModelChannels(ctx, pkgs, q, registry.BundleFilter{}); err != nil {
 		return nil, fmt.Errorf("populate channels: %v", err)
 	}
 	if err := populatePackageIcons(ctx, pkgs, q); err != nil {
@@ -34,12 +38,23 @@ func ToModel(ctx context.Context, q *SQLQuerier) (model.Model, error) {
 	return pkgs, nil
 }
 
-func initializeModelPackages(ctx context.Context, q *SQLQuerier) (model.Model, error) {
-	pkgNames, err := q.ListPackages(ctx)
+// packageToModel converts a single package of the database, without its
+// icon, so that the rest of the database is not read.
+func packageToModel(ctx context.Context, q *SQLQuerier, pkgName string) (*model.Package, error) {
+	pkgs, err := initializeModelPackages(ctx, q, []string{pkgName})
 	if err != nil {
 		return nil, err
 	}
+	if err := populateModelChannels(ctx, pkgs, q, registry.BundleFilter{PackageName: pkgName}); err != nil {
+		return nil, fmt.Errorf("populate channels: %v", err)
+	}
+	if err := pkgs.Validate(); err != nil {
+		return nil, err
+	}
+	return pkgs[pkgName], nil
+}
 
+func initializeModelPackages(ctx context.Context, q *SQLQuerier, pkgNames []string) (model.Model, error) {
 	// nolint:prealloc
 	var rPkgs []registry.PackageManifest
 	for _, pkgName := range pkgNames {
@@ -73,9 +88,9 @@ func initializeModelPackages(ctx context.Context, q *SQLQuerier) (model.Model, e
 	return pkgs, nil
 }
 
-func populateModelChannels(ctx context.Context, pkgs model.Model, q *SQLQuerier) error {
-	bundles, err := q.ListBundles(ctx)
-	if err != nil {
+func populateModelChannels(ctx context.Context, pkgs model.Model, q *SQLQuerier, filter registry.BundleFilter) error {
+	var bundles sliceBundleSender
+	if err := q.SendFilteredBundles(ctx, filter, &bundles); err != nil {
 		return err
 	}
 
@@ -113,32 +128,49 @@ ConvertBundles:
 // of the default channel of each of the pacakges in pkgs.
 func populatePackageIcons(ctx context.Context, pkgs model.Model, q *SQLQuerier) error {
 	for _, pkg := range pkgs {
-		head, err := q.GetBundleForChannel(ctx, pkg.Name, pkg.DefaultChannel.Name)
+		csv, csvName, err := defaultChannelHeadCSV(ctx, q, pkg.Name, pkg.DefaultChannel.Name)
 		if err != nil {
-			return fmt.Errorf("get default channel head for package %q: %v", pkg.Name, err)
-		}
-		var csv v1alpha1.ClusterServiceVersion
-		if err := json.Unmarshal([]byte(head.CsvJson), &csv); err != nil {
-			return fmt.Errorf("unmarshal CSV json for bundle %q: %v", head.CsvName, err)
-		}
-		if len(csv.Spec.Icon) == 0 {
-			continue
-		}
-		iconData, origErr := base64.StdEncoding.DecodeString(csv.Spec.Icon[0].Data)
-		if origErr != nil {
-			// Try decoding after removing spaces (this is a problem with the planetscale operator).
-			iconData, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(csv.Spec.Icon[0].Data, " ", ""))
-			if err != nil {
-				logrus.WithError(err).Warnf("base64 decode CSV icon for bundle %q", head.CsvName)
-				continue
-			}
-		}
-		if len(iconData) > 0 {
-			pkg.Icon = &model.Icon{
-				Data:      iconData,
-				MediaType: csv.Spec.Icon[0].MediaType,
-			}
+			return err
 		}
+		pkg.Icon = csvIcon(csv, csvName)
 	}
 	return nil
 }
+
+// defaultChannelHeadCSV returns the CSV of the bundle at the head of the
+// default channel of a package, and the name of the CSV.
+func defaultChannelHeadCSV(ctx context.Context, q *SQLQuerier, pkgName, defaultChannel string) (*v1alpha1.ClusterServiceVersion, string, error) {
+	head, err := q.GetBundleForChannel(ctx, pkgName, defaultChannel)
+	if err != nil {
+		return nil, "", fmt.Errorf("get default channel head for package %q: %v", pkgName, err)
+	}
+	var csv v1alpha1.ClusterServiceVersion
+	if err := json.Unmarshal([]byte(head.CsvJson), &csv); err != nil {
+		return nil, "", fmt.Errorf("unmarshal CSV json for bundle %q: %v", head.CsvName, err)
+	}
+	return &csv, head.CsvName, nil
+}
+
+// csvIcon returns the first icon of csv, or nil if it has none.
+func csvIcon(csv *v1alpha1.ClusterServiceVersion, csvName string) *model.Icon {
+	if len(csv.Spec.Icon) == 0 {
+		return nil
+	}
+	iconData, origErr := base64.StdEncoding.DecodeString(csv.Spec.Icon[0].Data)
+	if origErr != nil {
+		// Try decoding after removing spaces (this is a problem with the planetscale operator).
+		var err error
+		iconData, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(csv.Spec.Icon[0].Data, " ", ""))
+		if err != nil {
+			logrus.WithError(err).Warnf("base64 decode CSV icon for bundle %q", csvName)
+			return nil
+		}
+	}
+	if len(iconData) == 0 {
+		return nil
+	}
+	return &model.Icon{
+		Data:      iconData,
+		MediaType: csv.Spec.Icon[0].MediaType,
+	}
+}
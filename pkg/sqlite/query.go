@@ -993,10 +993,20 @@ SELECT
     LEFT OUTER JOIN merged_dependencies
       ON operatorbundle.name = merged_dependencies.bundle_name
     LEFT OUTER JOIN merged_properties
-      ON operatorbundle.name = merged_properties.bundle_name`
+      ON operatorbundle.name = merged_properties.bundle_name
+  WHERE (:package_name = "" OR replaces_bundle.package_name = :package_name)
+    AND (:channel_name = "" OR replaces_bundle.channel_name = :channel_name)`
 
 func (s *SQLQuerier) SendBundles(ctx context.Context, stream registry.BundleSender) error {
-	rows, err := s.db.QueryContext(ctx, listBundlesQuery, sql.Named("omit_manifests", s.omitManifests))
+	return s.SendFilteredBundles(ctx, registry.BundleFilter{}, stream)
+}
+
+func (s *SQLQuerier) SendFilteredBundles(ctx context.Context, filter registry.BundleFilter, stream registry.BundleSender) error {
+	rows, err := s.db.QueryContext(ctx, listBundlesQuery,
+		sql.Named("omit_manifests", s.omitManifests),
+		sql.Named("package_name", filter.PackageName),
+		sql.Named("channel_name", filter.ChannelName),
+	)
 	if err != nil {
 		return err
 	}
@@ -1059,6 +1069,9 @@ func (s *SQLQuerier) SendBundles(ctx context.Context, stream registry.BundleSend
 		}
 		_ = buildLegacyProvidedAPIs(out.Properties, &out.ProvidedApis)
 		out.Properties = uniqueProps(out.Properties)
+		if !filter.Matches(out) {
+			continue
+		}
 		if err := stream.Send(out); err != nil {
 			return err
 		}
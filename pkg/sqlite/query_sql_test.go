@@ -225,7 +225,11 @@ func TestListBundlesQuery(t *testing.T) {
 			_, err = db.Exec("PRAGMA foreign_keys = ON")
 			require.NoError(t, err)
 
-			rows, err := db.QueryContext(ctx, listBundlesQuery, sql.Named("omit_manifests", tt.OmitManfests))
+			rows, err := db.QueryContext(ctx, listBundlesQuery,
+				sql.Named("omit_manifests", tt.OmitManfests),
+				sql.Named("package_name", ""),
+				sql.Named("channel_name", ""),
+			)
 			if err != nil {
 				t.Fatalf("unexpected error executing list bundles query: %v", err)
 			}